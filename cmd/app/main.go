@@ -82,16 +82,46 @@ func main() {
 		cfg.JWT.RefreshExpDays,
 	)
 
+	realIP, err := customMiddleware.RealIP(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Error("Ошибка настройки доверенных прокси", "error", err.Error())
+		os.Exit(1)
+	}
+
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(realIP)
 	r.Use(customMiddleware.RequestLogging(log))
 	r.Use(customMiddleware.Recovery(log))
-	r.Use(middleware.Timeout(60 * time.Second))
+	// Потоковое соединение живет дольше любого таймаута запроса
+	r.Use(customMiddleware.Timeout(60*time.Second, "/api/v1/stream"))
 	r.Use(customMiddleware.CORS)
+	// Языки клиента для выбора переводов названий и описаний
+	r.Use(customMiddleware.Language)
+
+	var oidcProviders []*auth.OIDCProvider
+	if cfg.OIDC.Enabled() {
+		oidcProviders = append(oidcProviders, auth.NewOIDCProvider(auth.OIDCProviderConfig{
+			Name:         cfg.OIDC.ProviderName,
+			IssuerURL:    cfg.OIDC.IssuerURL,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+		}))
+		log.Info("Вход через OIDC включен", "provider", cfg.OIDC.ProviderName, "issuer", cfg.OIDC.IssuerURL)
+	}
+
+	// Контекст фоновых обработчиков отменяется после остановки HTTP-сервера
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	// Потоковые соединения закрываются сразу при начале остановки, иначе Shutdown ждал бы их до таймаута
+	streamsCtx, stopStreams := context.WithCancel(ctx)
+	defer stopStreams()
 
-	router.SetupRoutes(r, postgresDB, redisClient, jwtService, log)
+	workers := router.SetupRoutes(workersCtx, streamsCtx, r, postgresDB, redisClient, jwtService, oidcProviders, log)
 
 	server := &http.Server{
 		Addr:         cfg.Server.Address(),
@@ -100,6 +130,7 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  120 * time.Second,
 	}
+	server.RegisterOnShutdown(stopStreams)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -122,5 +153,20 @@ func main() {
 		log.Error("Ошибка грациозного завершения сервера", "error", err.Error())
 	}
 
+	// Обработчики дописывают накопленные данные в БД и Redis, поэтому соединения закрываются только после них
+	stopWorkers()
+
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+
+	select {
+	case <-workersDone:
+	case <-time.After(cfg.Server.ShutdownTimeout):
+		log.Warn("Фоновые обработчики не завершились за отведенное время")
+	}
+
 	log.Info("Сервер успешно остановлен")
 }
@@ -16,6 +16,7 @@ type Config struct {
 	Postgres PostgresConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	OIDC     OIDCConfig
 	Log      LogConfig
 }
 
@@ -26,6 +27,7 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	TrustedProxies  []string // прокси, которым разрешено передавать адрес клиента в X-Forwarded-For
 }
 
 // PostgresConfig содержит настройки PostgreSQL
@@ -54,6 +56,16 @@ type JWTConfig struct {
 	RefreshExpDays  int
 }
 
+// OIDCConfig содержит настройки внешнего OpenID Connect провайдера
+type OIDCConfig struct {
+	ProviderName string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
 // LogConfig содержит настройки логирования
 type LogConfig struct {
 	Level string
@@ -72,6 +84,7 @@ func NewConfig() (*Config, error) {
 			ReadTimeout:     time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 10)) * time.Second,
 			WriteTimeout:    time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)) * time.Second,
 			ShutdownTimeout: time.Duration(getEnvAsInt("SERVER_SHUTDOWN_TIMEOUT", 10)) * time.Second,
+			TrustedProxies:  getEnvAsSlice("SERVER_TRUSTED_PROXIES", nil, ","),
 		},
 		Postgres: PostgresConfig{
 			Host:     getEnv("POSTGRES_HOST", "localhost"),
@@ -93,6 +106,14 @@ func NewConfig() (*Config, error) {
 			RefreshSecret:   getEnv("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
 			RefreshExpDays:  getEnvAsInt("JWT_REFRESH_EXPIRATION_DAYS", 7),
 		},
+		OIDC: OIDCConfig{
+			ProviderName: getEnv("OIDC_PROVIDER_NAME", "oidc"),
+			IssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+			Scopes:       getEnvAsSlice("OIDC_SCOPES", []string{"openid", "email", "profile"}, ","),
+		},
 		Log: LogConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
@@ -120,6 +141,11 @@ func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
+// Enabled сообщает, настроен ли вход через OIDC провайдера
+func (c *OIDCConfig) Enabled() bool {
+	return c.IssuerURL != "" && c.ClientID != ""
+}
+
 // RedisAddress возвращает адрес Redis сервера
 func (c *RedisConfig) RedisAddress() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
@@ -1 +1,182 @@
 package handler
+
+import (
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+)
+
+// AnalyticsHandler обработчик запросов статистики просмотров
+type AnalyticsHandler struct {
+	analyticsUseCase usecase.AnalyticsUseCase
+	log              logger.Logger
+}
+
+// NewAnalyticsHandler создает новый экземпляр AnalyticsHandler
+func NewAnalyticsHandler(analyticsUseCase usecase.AnalyticsUseCase, log logger.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUseCase: analyticsUseCase,
+		log:              log,
+	}
+}
+
+// GetTopManga обрабатывает запрос на получение самой просматриваемой манги
+// @Summary      Самая просматриваемая манга
+// @Description  Получить мангу с наибольшим числом просмотров за период
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        period  query     string  false  "Период статистики (daily, weekly, monthly, all_time)"
+// @Param        limit   query     int     false  "Лимит результатов (по умолчанию 10, максимум 100)"
+// @Success      200     {object}  response.Response{data=[]entity.MangaStat}
+// @Failure      400     {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500     {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /analytics/manga/top [get]
+func (h *AnalyticsHandler) GetTopManga(w http.ResponseWriter, r *http.Request) {
+	period, limit, err := parseStatsQuery(r)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	stats, err := h.analyticsUseCase.GetTopManga(r.Context(), period, limit)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, stats)
+}
+
+// GetTopChapters обрабатывает запрос на получение самых просматриваемых глав
+// @Summary      Самые просматриваемые главы
+// @Description  Получить главы с наибольшим числом просмотров за период
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        period  query     string  false  "Период статистики (daily, weekly, monthly, all_time)"
+// @Param        limit   query     int     false  "Лимит результатов (по умолчанию 10, максимум 100)"
+// @Success      200     {object}  response.Response{data=[]entity.ChapterStat}
+// @Failure      400     {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500     {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /analytics/chapters/top [get]
+func (h *AnalyticsHandler) GetTopChapters(w http.ResponseWriter, r *http.Request) {
+	period, limit, err := parseStatsQuery(r)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	stats, err := h.analyticsUseCase.GetTopChapters(r.Context(), period, limit)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, stats)
+}
+
+// GetStats обрабатывает запрос на получение суммарной статистики
+// @Summary      Суммарная статистика
+// @Description  Получить общее число просмотров манги, глав и страниц по всем периодам
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  response.Response{data=[]entity.AnalyticsTotals}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /analytics/stats [get]
+func (h *AnalyticsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.analyticsUseCase.GetStats(r.Context())
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, stats)
+}
+
+// ResetDailyStats обрабатывает запрос на сброс дневной статистики
+// @Summary      Сбросить дневную статистику
+// @Description  Удалить счетчики просмотров за день
+// @Tags         analytics
+// @Produce      json
+// @Security     Bearer
+// @Success      204  {object}  nil
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /analytics/reset/daily [post]
+func (h *AnalyticsHandler) ResetDailyStats(w http.ResponseWriter, r *http.Request) {
+	h.resetStats(w, r, entity.StatsPeriodDaily)
+}
+
+// ResetWeeklyStats обрабатывает запрос на сброс недельной статистики
+// @Summary      Сбросить недельную статистику
+// @Description  Удалить счетчики просмотров за неделю
+// @Tags         analytics
+// @Produce      json
+// @Security     Bearer
+// @Success      204  {object}  nil
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /analytics/reset/weekly [post]
+func (h *AnalyticsHandler) ResetWeeklyStats(w http.ResponseWriter, r *http.Request) {
+	h.resetStats(w, r, entity.StatsPeriodWeekly)
+}
+
+// ResetMonthlyStats обрабатывает запрос на сброс месячной статистики
+// @Summary      Сбросить месячную статистику
+// @Description  Удалить счетчики просмотров за месяц
+// @Tags         analytics
+// @Produce      json
+// @Security     Bearer
+// @Success      204  {object}  nil
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /analytics/reset/monthly [post]
+func (h *AnalyticsHandler) ResetMonthlyStats(w http.ResponseWriter, r *http.Request) {
+	h.resetStats(w, r, entity.StatsPeriodMonthly)
+}
+
+// resetStats сбрасывает статистику за период
+func (h *AnalyticsHandler) resetStats(w http.ResponseWriter, r *http.Request, period entity.StatsPeriod) {
+	if err := h.analyticsUseCase.ResetStats(r.Context(), period); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// parseStatsQuery разбирает период и лимит рейтинга просмотров
+func parseStatsQuery(r *http.Request) (entity.StatsPeriod, int, error) {
+	period := entity.StatsPeriodAllTime
+	if value := r.URL.Query().Get("period"); value != "" {
+		period = entity.StatsPeriod(value)
+		switch period {
+		case entity.StatsPeriodDaily, entity.StatsPeriodWeekly, entity.StatsPeriodMonthly, entity.StatsPeriodAllTime:
+		default:
+			return "", 0, errors.NewBadRequestError("Некорректный период статистики", nil)
+		}
+	}
+
+	limit := 10
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return "", 0, errors.NewBadRequestError("Некорректный лимит", err)
+		}
+		limit = parsed
+	}
+
+	return period, limit, nil
+}
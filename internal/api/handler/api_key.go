@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeyHandler обработчик запросов для управления персональными API ключами
+type APIKeyHandler struct {
+	apiKeyUseCase usecase.APIKeyUseCase
+	log           logger.Logger
+}
+
+// NewAPIKeyHandler создает новый экземпляр APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase usecase.APIKeyUseCase, log logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+		log:           log,
+	}
+}
+
+// List обрабатывает запрос на получение списка API ключей текущего пользователя
+// @Summary      Список API ключей
+// @Description  Получить список персональных API ключей текущего пользователя
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]entity.APIKey}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me/api-keys [get]
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	keys, err := h.apiKeyUseCase.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, keys)
+}
+
+// Create обрабатывает запрос на создание API ключа
+// @Summary      Создать API ключ
+// @Description  Создать именованный API ключ с ограниченными областями действия. Значение ключа показывается только один раз
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        key  body      entity.APIKeyCreate  true  "Параметры ключа"
+// @Success      201  {object}  response.Response{data=entity.APIKeyWithSecret}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me/api-keys [post]
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var req entity.APIKeyCreate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	key, err := h.apiKeyUseCase.Create(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, key)
+}
+
+// Revoke обрабатывает запрос на отзыв API ключа
+// @Summary      Отозвать API ключ
+// @Description  Отозвать персональный API ключ текущего пользователя
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID ключа"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.apiKeyUseCase.Revoke(r.Context(), userID, id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/domain/entity"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetBookmarks обрабатывает запрос на получение закладок текущего пользователя
+// @Summary      Закладки
+// @Description  Получить закладки текущего пользователя с данными манги и количеством непрочитанных глав
+// @Tags         bookmarks
+// @Accept       json
+// @Produce      json
+// @Param        sort    query     string  false  "Сортировка: added (по времени добавления) или updated (по обновлению манги)"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.BookmarkWithManga}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/bookmarks [get]
+func (h *UserHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := entity.BookmarkFilter{
+		Sort:  query.Get("sort"),
+		Limit: 20,
+	}
+
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	bookmarks, total, err := h.bookmarkUseCase.List(r.Context(), userID, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, bookmarks, meta)
+}
+
+// AddBookmark обрабатывает запрос на добавление закладки
+// @Summary      Добавить закладку
+// @Description  Добавить мангу в закладки или обновить последнюю прочитанную главу
+// @Tags         bookmarks
+// @Accept       json
+// @Produce      json
+// @Param        bookmark  body      entity.BookmarkCreate  true  "Закладка"
+// @Success      201  {object}  response.Response{data=entity.Bookmark}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/bookmarks [post]
+func (h *UserHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var req entity.BookmarkCreate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	bookmark, err := h.bookmarkUseCase.Add(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, bookmark)
+}
+
+// RemoveBookmark обрабатывает запрос на удаление закладки
+// @Summary      Удалить закладку
+// @Description  Удалить мангу из закладок текущего пользователя
+// @Tags         bookmarks
+// @Accept       json
+// @Produce      json
+// @Param        mangaID  path      int  true  "ID манги"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/bookmarks/{mangaID} [delete]
+func (h *UserHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaIDStr := chi.URLParam(r, "mangaID")
+	mangaID, err := strconv.ParseInt(mangaIDStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+		return
+	}
+
+	if err = h.bookmarkUseCase.Remove(r.Context(), userID, mangaID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
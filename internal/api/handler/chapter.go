@@ -1 +1,193 @@
 package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ChapterHandler обработчик запросов для API глав
+type ChapterHandler struct {
+	chapterUseCase usecase.ChapterUseCase
+	log            logger.Logger
+}
+
+// NewChapterHandler создает новый экземпляр ChapterHandler
+func NewChapterHandler(chapterUseCase usecase.ChapterUseCase, log logger.Logger) *ChapterHandler {
+	return &ChapterHandler{
+		chapterUseCase: chapterUseCase,
+		log:            log,
+	}
+}
+
+// GetByID обрабатывает запрос на получение главы
+// @Summary      Получить главу
+// @Description  Получить главу по ID с числом страниц и просмотров. Неопубликованные главы и главы в раннем доступе
+// @Description  доступны только пользователям с правами
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID главы"
+// @Success      200  {object}  response.Response{data=entity.ChapterWithStats}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /chapters/{id} [get]
+func (h *ChapterHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	chapter, err := h.chapterUseCase.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, chapter)
+}
+
+// GetPages обрабатывает запрос на получение страниц главы
+// @Summary      Получить страницы главы
+// @Description  Получить страницы главы по порядку номеров
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID главы"
+// @Success      200  {object}  response.Response{data=[]entity.Page}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /chapters/{id}/pages [get]
+func (h *ChapterHandler) GetPages(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	pages, err := h.chapterUseCase.GetPages(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, pages)
+}
+
+// Create обрабатывает запрос на создание новой главы
+// @Summary      Создать главу
+// @Description  Создать новую главу манги. Глава со статусом draft или scheduled не видна читателям до публикации
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        chapter  body      entity.Chapter  true  "Данные главы"
+// @Success      201      {object}  response.Response{data=entity.Chapter}
+// @Failure      400      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500      {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters [post]
+func (h *ChapterHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var chapter entity.Chapter
+	if err := json.NewDecoder(r.Body).Decode(&chapter); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	createdChapter, err := h.chapterUseCase.Create(r.Context(), &chapter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, createdChapter)
+}
+
+// Update обрабатывает запрос на обновление главы
+// @Summary      Обновить главу
+// @Description  Обновить существующую главу. Пустые язык и статус, а также отсутствующие группа и том сохраняют прежние значения
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true  "ID главы"
+// @Param        chapter  body      entity.Chapter  true  "Новые данные главы"
+// @Success      200      {object}  response.Response{data=entity.Chapter}
+// @Failure      400      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500      {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id} [put]
+func (h *ChapterHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var chapter entity.Chapter
+	if err := json.NewDecoder(r.Body).Decode(&chapter); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	chapter.ID = id
+
+	updatedChapter, err := h.chapterUseCase.Update(r.Context(), &chapter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, updatedChapter)
+}
+
+// Delete обрабатывает запрос на удаление главы
+// @Summary      Удалить главу
+// @Description  Удалить главу по ID вместе с ее страницами
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID главы"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id} [delete]
+func (h *ChapterHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.chapterUseCase.Delete(r.Context(), id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ChapterReadHandler обработчик запросов для отметок о прочтении глав
+type ChapterReadHandler struct {
+	chapterReadUseCase usecase.ChapterReadUseCase
+	log                logger.Logger
+}
+
+// NewChapterReadHandler создает новый экземпляр ChapterReadHandler
+func NewChapterReadHandler(chapterReadUseCase usecase.ChapterReadUseCase, log logger.Logger) *ChapterReadHandler {
+	return &ChapterReadHandler{
+		chapterReadUseCase: chapterReadUseCase,
+		log:                log,
+	}
+}
+
+// MarkRead обрабатывает запрос на отметку главы прочитанной
+// @Summary      Отметить главу прочитанной
+// @Description  Отметить главу прочитанной текущим пользователем
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID главы"
+// @Success      200  {object}  response.Response{data=entity.ChapterReadResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id}/read [put]
+func (h *ChapterReadHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	h.markChapter(w, r, true)
+}
+
+// MarkUnread обрабатывает запрос на снятие отметки о прочтении главы
+// @Summary      Отметить главу непрочитанной
+// @Description  Снять отметку о прочтении главы текущим пользователем
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID главы"
+// @Success      200  {object}  response.Response{data=entity.ChapterReadResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id}/read [delete]
+func (h *ChapterReadHandler) MarkUnread(w http.ResponseWriter, r *http.Request) {
+	h.markChapter(w, r, false)
+}
+
+// MarkRangeRead обрабатывает запрос на отметку диапазона глав прочитанными
+// @Summary      Отметить главы прочитанными
+// @Description  Отметить прочитанными главы манги с номерами от from до to включительно.
+// @Description  Только to — все главы до указанной, пустое тело — все главы манги
+// @Tags         manga
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                  true   "ID манги"
+// @Param        range  body      entity.ChapterRange  false  "Диапазон номеров глав"
+// @Success      200  {object}  response.Response{data=entity.ChapterReadResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/chapters/read [post]
+func (h *ChapterReadHandler) MarkRangeRead(w http.ResponseWriter, r *http.Request) {
+	h.markRange(w, r, true)
+}
+
+// MarkRangeUnread обрабатывает запрос на снятие отметок о прочтении диапазона глав
+// @Summary      Отметить главы непрочитанными
+// @Description  Снять отметки о прочтении с глав манги с номерами от from до to включительно.
+// @Description  Только to — все главы до указанной, пустое тело — все главы манги
+// @Tags         manga
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                  true   "ID манги"
+// @Param        range  body      entity.ChapterRange  false  "Диапазон номеров глав"
+// @Success      200  {object}  response.Response{data=entity.ChapterReadResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/chapters/unread [post]
+func (h *ChapterReadHandler) MarkRangeUnread(w http.ResponseWriter, r *http.Request) {
+	h.markRange(w, r, false)
+}
+
+// UnreadCounts обрабатывает запрос на получение количества непрочитанных глав
+// @Summary      Счетчики непрочитанных глав
+// @Description  Получить количество непрочитанных текущим пользователем глав по каждой из указанных манг
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        manga_ids  query     string  true  "ID манги через запятую"
+// @Success      200  {object}  response.Response{data=map[int64]int}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/unread-counts [get]
+func (h *ChapterReadHandler) UnreadCounts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var mangaIDs []int64
+	for _, part := range strings.Split(r.URL.Query().Get("manga_ids"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+			return
+		}
+		mangaIDs = append(mangaIDs, id)
+	}
+
+	counts, err := h.chapterReadUseCase.UnreadCounts(r.Context(), userID, mangaIDs)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, counts)
+}
+
+// markChapter изменяет отметку о прочтении главы из адреса запроса
+func (h *ChapterReadHandler) markChapter(w http.ResponseWriter, r *http.Request, read bool) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	chapterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	result, err := h.chapterReadUseCase.MarkChapter(r.Context(), userID, chapterID, read)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
+
+// markRange изменяет отметки о прочтении глав манги в диапазоне из тела запроса
+func (h *ChapterReadHandler) markRange(w http.ResponseWriter, r *http.Request, read bool) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var rng entity.ChapterRange
+	if err = json.NewDecoder(r.Body).Decode(&rng); err != nil && !stderrors.Is(err, io.EOF) {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	result, err := h.chapterReadUseCase.MarkRange(r.Context(), userID, mangaID, rng, read)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
@@ -0,0 +1,472 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CommentHandler обработчик запросов для комментариев
+type CommentHandler struct {
+	commentUseCase usecase.CommentUseCase
+	log            logger.Logger
+}
+
+// NewCommentHandler создает новый экземпляр CommentHandler
+func NewCommentHandler(commentUseCase usecase.CommentUseCase, log logger.Logger) *CommentHandler {
+	return &CommentHandler{
+		commentUseCase: commentUseCase,
+		log:            log,
+	}
+}
+
+// ListMangaComments обрабатывает запрос на получение комментариев к манге
+// @Summary      Комментарии к манге
+// @Description  Получить комментарии верхнего уровня к манге; ответы загружаются через /comments/{id}/replies
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int     true   "ID манги"
+// @Param        sort    query     string  false  "Сортировка: newest (по умолчанию), oldest, top"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /manga/{id}/comments [get]
+func (h *CommentHandler) ListMangaComments(w http.ResponseWriter, r *http.Request) {
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	h.list(w, r, entity.CommentFilter{MangaID: &mangaID})
+}
+
+// ListChapterComments обрабатывает запрос на получение комментариев к главе
+// @Summary      Комментарии к главе
+// @Description  Получить комментарии верхнего уровня к главе; ответы загружаются через /comments/{id}/replies
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int     true   "ID главы"
+// @Param        sort    query     string  false  "Сортировка: newest (по умолчанию), oldest, top"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /chapters/{id}/comments [get]
+func (h *CommentHandler) ListChapterComments(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	h.list(w, r, entity.CommentFilter{ChapterID: &chapterID})
+}
+
+// ListReplies обрабатывает запрос на получение ответов на комментарий
+// @Summary      Ответы на комментарий
+// @Description  Получить прямые ответы на комментарий
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int     true   "ID комментария"
+// @Param        sort    query     string  false  "Сортировка: oldest (по умолчанию), newest, top"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /comments/{id}/replies [get]
+func (h *CommentHandler) ListReplies(w http.ResponseWriter, r *http.Request) {
+	parentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	h.list(w, r, entity.CommentFilter{ParentID: &parentID})
+}
+
+// list разбирает параметры пагинации и сортировки и отдает страницу комментариев
+func (h *CommentHandler) list(w http.ResponseWriter, r *http.Request, filter entity.CommentFilter) {
+	query := r.URL.Query()
+
+	filter.Sort = query.Get("sort")
+	filter.Limit = 20
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	comments, total, err := h.commentUseCase.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, comments, meta)
+}
+
+// CreateMangaComment обрабатывает запрос на создание комментария к манге
+// @Summary      Комментировать мангу
+// @Description  Оставить комментарий к манге. Текст поддерживает Markdown и спойлеры ||текст||
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "ID манги"
+// @Param        comment  body      entity.CommentCreate  true  "Комментарий"
+// @Success      201  {object}  response.Response{data=entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      429  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/comments [post]
+func (h *CommentHandler) CreateMangaComment(w http.ResponseWriter, r *http.Request) {
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	h.create(w, r, func(userID int64, req *entity.CommentCreate) (*entity.CommentWithUser, error) {
+		req.MangaID = &mangaID
+		return h.commentUseCase.Create(r.Context(), userID, req)
+	})
+}
+
+// CreateChapterComment обрабатывает запрос на создание комментария к главе
+// @Summary      Комментировать главу
+// @Description  Оставить комментарий к главе. Текст поддерживает Markdown и спойлеры ||текст||
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "ID главы"
+// @Param        comment  body      entity.CommentCreate  true  "Комментарий"
+// @Success      201  {object}  response.Response{data=entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      429  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id}/comments [post]
+func (h *CommentHandler) CreateChapterComment(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	h.create(w, r, func(userID int64, req *entity.CommentCreate) (*entity.CommentWithUser, error) {
+		req.ChapterID = &chapterID
+		return h.commentUseCase.Create(r.Context(), userID, req)
+	})
+}
+
+// Reply обрабатывает запрос на ответ на комментарий
+// @Summary      Ответить на комментарий
+// @Description  Оставить ответ на комментарий; ответ относится к той же манге или главе
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "ID комментария"
+// @Param        comment  body      entity.CommentCreate  true  "Ответ"
+// @Success      201  {object}  response.Response{data=entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      429  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /comments/{id}/replies [post]
+func (h *CommentHandler) Reply(w http.ResponseWriter, r *http.Request) {
+	parentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	h.create(w, r, func(userID int64, req *entity.CommentCreate) (*entity.CommentWithUser, error) {
+		return h.commentUseCase.Reply(r.Context(), userID, parentID, req)
+	})
+}
+
+// create разбирает тело запроса и создает комментарий указанным способом
+func (h *CommentHandler) create(
+	w http.ResponseWriter,
+	r *http.Request,
+	createFn func(userID int64, req *entity.CommentCreate) (*entity.CommentWithUser, error),
+) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var req entity.CommentCreate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	comment, err := createFn(userID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Created(w, comment)
+}
+
+// GetByID обрабатывает запрос на получение комментария
+// @Summary      Комментарий
+// @Description  Получить комментарий по ID
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID комментария"
+// @Success      200  {object}  response.Response{data=entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /comments/{id} [get]
+func (h *CommentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	comment, err := h.commentUseCase.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, comment)
+}
+
+// Update обрабатывает запрос на редактирование комментария
+// @Summary      Редактировать комментарий
+// @Description  Изменить текст собственного комментария; предыдущая версия сохраняется в истории правок
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "ID комментария"
+// @Param        comment  body      entity.CommentUpdate  true  "Новый текст"
+// @Success      200  {object}  response.Response{data=entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /comments/{id} [put]
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.CommentUpdate
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	comment, err := h.commentUseCase.Update(r.Context(), userID, id, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, comment)
+}
+
+// Delete обрабатывает запрос на удаление комментария
+// @Summary      Удалить комментарий
+// @Description  Удалить собственный комментарий; модераторы могут удалять любые. Ответы на комментарий сохраняются
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID комментария"
+// @Success      204  "No Content"
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /comments/{id} [delete]
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.commentUseCase.Delete(r.Context(), userID, id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// History обрабатывает запрос на получение истории правок комментария
+// @Summary      История правок
+// @Description  Получить предыдущие версии текста комментария от старых к новым
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID комментария"
+// @Success      200  {object}  response.Response{data=[]entity.CommentEdit}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /comments/{id}/history [get]
+func (h *CommentHandler) History(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	edits, err := h.commentUseCase.History(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, edits)
+}
+
+// Vote обрабатывает запрос на оценку комментария
+// @Summary      Оценить комментарий
+// @Description  Поставить комментарию оценку 1 или -1; значение 0 снимает оценку. Собственные комментарии оценивать нельзя
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                 true  "ID комментария"
+// @Param        vote  body      entity.CommentVote  true  "Оценка"
+// @Success      200  {object}  response.Response{data=entity.CommentVoteResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /comments/{id}/vote [put]
+func (h *CommentHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.CommentVote
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	result, err := h.commentUseCase.Vote(r.Context(), userID, id, req.Value)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
+
+// Report обрабатывает запрос на отправку жалобы на комментарий
+// @Summary      Пожаловаться на комментарий
+// @Description  Отправить жалобу модераторам. Причины: spam, abuse, spoiler, offtopic, other (с пояснением).
+// @Description  Комментарий с несколькими открытыми жалобами скрывается до решения модератора
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                         true  "ID комментария"
+// @Param        report  body      entity.CommentReportCreate  true  "Жалоба"
+// @Success      201  {object}  response.Response{data=entity.CommentReport}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /comments/{id}/reports [post]
+func (h *CommentHandler) Report(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.CommentReportCreate
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	report, err := h.commentUseCase.Report(r.Context(), userID, id, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Created(w, report)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CreatorHandler обработчик запросов для авторов и художников
+type CreatorHandler struct {
+	creatorUseCase usecase.CreatorUseCase
+	log            logger.Logger
+}
+
+// NewCreatorHandler создает новый экземпляр CreatorHandler
+func NewCreatorHandler(creatorUseCase usecase.CreatorUseCase, log logger.Logger) *CreatorHandler {
+	return &CreatorHandler{
+		creatorUseCase: creatorUseCase,
+		log:            log,
+	}
+}
+
+// List обрабатывает запрос на поиск авторов
+// @Summary      Авторы
+// @Description  Найти авторов и художников по любому варианту написания имени
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Param        q       query     string  false  "Часть имени"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.Creator}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /creators [get]
+func (h *CreatorHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := entity.CreatorFilter{Query: query.Get("q"), Limit: 20}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	creators, total, err := h.creatorUseCase.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, creators, meta)
+}
+
+// Get обрабатывает запрос на получение автора
+// @Summary      Получить автора
+// @Description  Получить автора или художника с вариантами имени и библиографией
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID автора"
+// @Success      200  {object}  response.Response{data=entity.CreatorDetails}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /creators/{id} [get]
+func (h *CreatorHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	creator, err := h.creatorUseCase.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, creator)
+}
+
+// Create обрабатывает запрос на создание автора
+// @Summary      Создать автора
+// @Description  Создать автора или художника
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Param        creator  body      entity.CreatorInput  true  "Данные автора"
+// @Success      201      {object}  response.Response{data=entity.Creator}
+// @Failure      400      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500      {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /creators [post]
+func (h *CreatorHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input entity.CreatorInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	creator, err := h.creatorUseCase.Create(r.Context(), input)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Created(w, creator)
+}
+
+// Update обрабатывает запрос на изменение автора
+// @Summary      Изменить автора
+// @Description  Изменить имя, описание и варианты имени автора. Имена в манге автора обновляются
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "ID автора"
+// @Param        creator  body      entity.CreatorInput  true  "Данные автора"
+// @Success      200      {object}  response.Response{data=entity.Creator}
+// @Failure      400      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404      {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500      {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /creators/{id} [put]
+func (h *CreatorHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var input entity.CreatorInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	creator, err := h.creatorUseCase.Update(r.Context(), id, input)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, creator)
+}
+
+// Merge обрабатывает запрос на объединение авторов
+// @Summary      Объединить авторов
+// @Description  Перенести мангу и варианты имени дубликата к автору и удалить дубликат
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                       true  "ID автора, который остается"
+// @Param        merge  body      entity.CreatorMergeInput  true  "ID дубликата"
+// @Success      200    {object}  response.Response{data=entity.CreatorDetails}
+// @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /creators/{id}/merge [post]
+func (h *CreatorHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var input entity.CreatorMergeInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	creator, err := h.creatorUseCase.Merge(r.Context(), id, input.SourceID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, creator)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// FollowHandler обработчик запросов для подписок на мангу
+type FollowHandler struct {
+	followUseCase usecase.FollowUseCase
+	log           logger.Logger
+}
+
+// NewFollowHandler создает новый экземпляр FollowHandler
+func NewFollowHandler(followUseCase usecase.FollowUseCase, log logger.Logger) *FollowHandler {
+	return &FollowHandler{
+		followUseCase: followUseCase,
+		log:           log,
+	}
+}
+
+// Status обрабатывает запрос на получение состояния подписки на мангу
+// @Summary      Состояние подписки
+// @Description  Проверить, подписан ли текущий пользователь на мангу
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID манги"
+// @Success      200  {object}  response.Response{data=entity.FollowStatus}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/follow [get]
+func (h *FollowHandler) Status(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	status, err := h.followUseCase.Status(r.Context(), userID, mangaID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, status)
+}
+
+// Follow обрабатывает запрос на подписку на мангу
+// @Summary      Подписаться на мангу
+// @Description  Подписаться на уведомления о новых главах манги
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID манги"
+// @Success      200  {object}  response.Response{data=entity.MangaFollow}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/follow [put]
+func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	follow, err := h.followUseCase.Follow(r.Context(), userID, mangaID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, follow)
+}
+
+// Unfollow обрабатывает запрос на отмену подписки на мангу
+// @Summary      Отписаться от манги
+// @Description  Отменить подписку на уведомления о новых главах манги
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID манги"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/follow [delete]
+func (h *FollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.followUseCase.Unfollow(r.Context(), userID, mangaID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// List обрабатывает запрос на получение подписок текущего пользователя
+// @Summary      Подписки
+// @Description  Получить мангу, на которую подписан текущий пользователь
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        limit   query     int  false  "Лимит результатов"
+// @Param        offset  query     int  false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.MangaFollowWithManga}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/follows [get]
+func (h *FollowHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := entity.FollowFilter{Limit: 20}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	follows, total, err := h.followUseCase.List(r.Context(), userID, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, follows, meta)
+}
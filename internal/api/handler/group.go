@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ScanlationGroupHandler обработчик запросов для групп переводчиков
+type ScanlationGroupHandler struct {
+	groupUseCase usecase.ScanlationGroupUseCase
+	log          logger.Logger
+}
+
+// NewScanlationGroupHandler создает новый экземпляр ScanlationGroupHandler
+func NewScanlationGroupHandler(groupUseCase usecase.ScanlationGroupUseCase, log logger.Logger) *ScanlationGroupHandler {
+	return &ScanlationGroupHandler{
+		groupUseCase: groupUseCase,
+		log:          log,
+	}
+}
+
+// List обрабатывает запрос на поиск групп
+// @Summary      Группы переводчиков
+// @Description  Найти группы переводчиков по названию
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        q       query     string  false  "Часть названия"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.ScanlationGroup}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /groups [get]
+func (h *ScanlationGroupHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := entity.ScanlationGroupFilter{Query: query.Get("q"), Limit: 20}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	groups, total, err := h.groupUseCase.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, groups, meta)
+}
+
+// Get обрабатывает запрос на получение группы
+// @Summary      Получить группу
+// @Description  Получить группу переводчиков с числом выпущенных глав
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID группы"
+// @Success      200  {object}  response.Response{data=entity.ScanlationGroup}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /groups/{id} [get]
+func (h *ScanlationGroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	group, err := h.groupUseCase.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, group)
+}
+
+// ListReleases обрабатывает запрос на получение глав группы
+// @Summary      Главы группы
+// @Description  Получить главы, выпущенные группой, начиная с последних
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int  true   "ID группы"
+// @Param        limit   query     int  false  "Лимит результатов"
+// @Param        offset  query     int  false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.GroupRelease}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /groups/{id}/chapters [get]
+func (h *ScanlationGroupHandler) ListReleases(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	limit, offset := 20, 0
+	if value, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && value > 0 && value <= 100 {
+		limit = value
+	}
+	if value, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && value >= 0 {
+		offset = value
+	}
+
+	releases, total, err := h.groupUseCase.ListReleases(r.Context(), id, limit, offset)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     limit,
+		CurrentPage: offset/limit + 1,
+		LastPage:    (total + limit - 1) / limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, releases, meta)
+}
+
+// Create обрабатывает запрос на создание группы
+// @Summary      Создать группу
+// @Description  Создать группу переводчиков
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        group  body      entity.ScanlationGroupInput  true  "Данные группы"
+// @Success      201    {object}  response.Response{data=entity.ScanlationGroup}
+// @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /groups [post]
+func (h *ScanlationGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input entity.ScanlationGroupInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	group, err := h.groupUseCase.Create(r.Context(), input)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Created(w, group)
+}
+
+// Update обрабатывает запрос на изменение группы
+// @Summary      Изменить группу
+// @Description  Изменить название, описание и сайт группы переводчиков
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                          true  "ID группы"
+// @Param        group  body      entity.ScanlationGroupInput  true  "Данные группы"
+// @Success      200    {object}  response.Response{data=entity.ScanlationGroup}
+// @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /groups/{id} [put]
+func (h *ScanlationGroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var input entity.ScanlationGroupInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	group, err := h.groupUseCase.Update(r.Context(), id, input)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, group)
+}
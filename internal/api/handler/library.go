@@ -0,0 +1,473 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// libraryImportMaxBytes максимальный размер файла импорта библиотеки
+const libraryImportMaxBytes = 10 << 20
+
+// LibraryHandler обработчик запросов для библиотеки пользователя
+type LibraryHandler struct {
+	libraryUseCase usecase.LibraryUseCase
+	log            logger.Logger
+}
+
+// NewLibraryHandler создает новый экземпляр LibraryHandler
+func NewLibraryHandler(libraryUseCase usecase.LibraryUseCase, log logger.Logger) *LibraryHandler {
+	return &LibraryHandler{
+		libraryUseCase: libraryUseCase,
+		log:            log,
+	}
+}
+
+// List обрабатывает запрос на получение библиотеки текущего пользователя
+// @Summary      Библиотека
+// @Description  Получить мангу из библиотеки текущего пользователя с фильтрами по статусу, полке и названию
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        status    query     string  false  "Статус: reading, planned, completed, dropped"
+// @Param        shelf_id  query     int     false  "ID полки"
+// @Param        q         query     string  false  "Поиск по названию манги"
+// @Param        sort      query     string  false  "Сортировка: updated, added, title, score"
+// @Param        limit     query     int     false  "Лимит результатов"
+// @Param        offset    query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.LibraryEntryWithManga}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library [get]
+func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := entity.LibraryFilter{
+		Status: query.Get("status"),
+		Query:  query.Get("q"),
+		Sort:   query.Get("sort"),
+		Limit:  20,
+	}
+
+	if shelfIDStr := query.Get("shelf_id"); shelfIDStr != "" {
+		shelfID, err := strconv.ParseInt(shelfIDStr, 10, 64)
+		if err != nil {
+			response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID полки", err))
+			return
+		}
+		filter.ShelfID = &shelfID
+	}
+
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	entries, total, err := h.libraryUseCase.List(r.Context(), userID, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, entries, meta)
+}
+
+// Stats обрабатывает запрос на получение количества манги по статусам
+// @Summary      Статистика библиотеки
+// @Description  Получить количество манги в библиотеке по каждому статусу
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=entity.LibraryStats}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/stats [get]
+func (h *LibraryHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	stats, err := h.libraryUseCase.Stats(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, stats)
+}
+
+// Set обрабатывает запрос на добавление манги в библиотеку или изменение записи
+// @Summary      Добавить в библиотеку
+// @Description  Добавить мангу в библиотеку или заменить статус, оценку и заметки
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        mangaID  path      int                        true  "ID манги"
+// @Param        entry    body      entity.LibraryEntryUpsert  true  "Запись библиотеки"
+// @Success      200  {object}  response.Response{data=entity.LibraryEntry}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/{mangaID} [put]
+func (h *LibraryHandler) Set(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "mangaID"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+		return
+	}
+
+	var req entity.LibraryEntryUpsert
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	entry, err := h.libraryUseCase.Set(r.Context(), userID, mangaID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, entry)
+}
+
+// Remove обрабатывает запрос на удаление манги из библиотеки
+// @Summary      Удалить из библиотеки
+// @Description  Удалить мангу из библиотеки и со всех полок
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        mangaID  path      int  true  "ID манги"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/{mangaID} [delete]
+func (h *LibraryHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "mangaID"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+		return
+	}
+
+	if err = h.libraryUseCase.Remove(r.Context(), userID, mangaID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// ListShelves обрабатывает запрос на получение полок пользователя
+// @Summary      Полки
+// @Description  Получить пользовательские полки с количеством манги на каждой
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]entity.LibraryShelf}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/shelves [get]
+func (h *LibraryHandler) ListShelves(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	shelves, err := h.libraryUseCase.ListShelves(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, shelves)
+}
+
+// CreateShelf обрабатывает запрос на создание полки
+// @Summary      Создать полку
+// @Description  Создать пользовательскую полку
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        shelf  body      entity.LibraryShelfCreate  true  "Полка"
+// @Success      201  {object}  response.Response{data=entity.LibraryShelf}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/shelves [post]
+func (h *LibraryHandler) CreateShelf(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var req entity.LibraryShelfCreate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	shelf, err := h.libraryUseCase.CreateShelf(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, shelf)
+}
+
+// RenameShelf обрабатывает запрос на переименование полки
+// @Summary      Переименовать полку
+// @Description  Изменить название пользовательской полки
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                        true  "ID полки"
+// @Param        shelf  body      entity.LibraryShelfCreate  true  "Полка"
+// @Success      200  {object}  response.Response{data=entity.LibraryShelf}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/shelves/{id} [put]
+func (h *LibraryHandler) RenameShelf(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	shelfID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID полки", err))
+		return
+	}
+
+	var req entity.LibraryShelfCreate
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	shelf, err := h.libraryUseCase.RenameShelf(r.Context(), userID, shelfID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, shelf)
+}
+
+// DeleteShelf обрабатывает запрос на удаление полки
+// @Summary      Удалить полку
+// @Description  Удалить пользовательскую полку; манга остается в библиотеке
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        id  path      int  true  "ID полки"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/shelves/{id} [delete]
+func (h *LibraryHandler) DeleteShelf(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	shelfID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID полки", err))
+		return
+	}
+
+	if err = h.libraryUseCase.DeleteShelf(r.Context(), userID, shelfID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// AddToShelf обрабатывает запрос на добавление манги на полку
+// @Summary      Положить на полку
+// @Description  Положить мангу из библиотеки на пользовательскую полку
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int  true  "ID полки"
+// @Param        mangaID  path      int  true  "ID манги"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/shelves/{id}/manga/{mangaID} [put]
+func (h *LibraryHandler) AddToShelf(w http.ResponseWriter, r *http.Request) {
+	h.changeShelf(w, r, h.libraryUseCase.AddToShelf)
+}
+
+// RemoveFromShelf обрабатывает запрос на удаление манги с полки
+// @Summary      Убрать с полки
+// @Description  Убрать мангу с пользовательской полки; манга остается в библиотеке
+// @Tags         library
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int  true  "ID полки"
+// @Param        mangaID  path      int  true  "ID манги"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/shelves/{id}/manga/{mangaID} [delete]
+func (h *LibraryHandler) RemoveFromShelf(w http.ResponseWriter, r *http.Request) {
+	h.changeShelf(w, r, h.libraryUseCase.RemoveFromShelf)
+}
+
+// Export обрабатывает запрос на выгрузку библиотеки
+// @Summary      Экспорт библиотеки
+// @Description  Выгрузить библиотеку в XML формате MyAnimeList; полки записываются в теги.
+// @Description  ID манги на MyAnimeList (manga_mangadb_id) выгружается, если он известен из каталога или из импорта
+// @Tags         library
+// @Produce      xml
+// @Success      200  {file}    file
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/export [get]
+func (h *LibraryHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	data, err := h.libraryUseCase.Export(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="mangalist.xml"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Import обрабатывает запрос на загрузку библиотеки из файла MyAnimeList
+// @Summary      Импорт библиотеки
+// @Description  Загрузить библиотеку из XML экспорта MyAnimeList. Манга сопоставляется с каталогом по manga_mangadb_id, а без него — по основному или альтернативному названию; теги становятся полками
+// @Tags         library
+// @Accept       xml
+// @Produce      json
+// @Param        file  body      string  true  "XML экспорт MyAnimeList"
+// @Success      200  {object}  response.Response{data=entity.LibraryImportResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/library/import [post]
+func (h *LibraryHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, libraryImportMaxBytes)
+
+	result, err := h.libraryUseCase.Import(r.Context(), userID, body)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
+
+// changeShelf разбирает ID полки и манги и выполняет операцию с полкой
+func (h *LibraryHandler) changeShelf(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, userID, shelfID, mangaID int64) error) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	shelfID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID полки", err))
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "mangaID"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+		return
+	}
+
+	if err = op(r.Context(), userID, shelfID, mangaID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"manga-reader2/internal/api/middleware"
 	"manga-reader2/internal/api/response"
 	"manga-reader2/internal/common/errors"
 	"manga-reader2/internal/common/logger"
@@ -9,21 +10,24 @@ import (
 	"manga-reader2/internal/usecase"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
 
 // MangaHandler обработчик запросов для API манги
 type MangaHandler struct {
-	mangaUseCase usecase.MangaUseCase
-	log          logger.Logger
+	mangaUseCase       usecase.MangaUseCase
+	chapterReadUseCase usecase.ChapterReadUseCase
+	log                logger.Logger
 }
 
 // NewMangaHandler создает новый экземпляр MangaHandler
-func NewMangaHandler(mangaUseCase usecase.MangaUseCase, log logger.Logger) *MangaHandler {
+func NewMangaHandler(mangaUseCase usecase.MangaUseCase, chapterReadUseCase usecase.ChapterReadUseCase, log logger.Logger) *MangaHandler {
 	return &MangaHandler{
-		mangaUseCase: mangaUseCase,
-		log:          log,
+		mangaUseCase:       mangaUseCase,
+		chapterReadUseCase: chapterReadUseCase,
+		log:                log,
 	}
 }
 
@@ -33,9 +37,12 @@ func NewMangaHandler(mangaUseCase usecase.MangaUseCase, log logger.Logger) *Mang
 // @Tags         manga
 // @Accept       json
 // @Produce      json
-// @Param        title    query     string  false  "Фильтр по названию"
+// @Param        title    query     string  false  "Фильтр по основному или альтернативному названию"
+// @Param        lang     query     string  false  "Язык описаний и названий (приоритетнее заголовка Accept-Language)"
+// @Param        Accept-Language  header  string  false  "Предпочитаемые языки"
 // @Param        status   query     string  false  "Фильтр по статусу (ongoing, completed, hiatus)"
 // @Param        genres   query     string  false  "Фильтр по жанрам (через запятую)"
+// @Param        sort     query     string  false  "Сортировка: updated (по умолчанию), score, average, ratings"
 // @Param        limit    query     int     false  "Лимит результатов"
 // @Param        offset   query     int     false  "Смещение результатов"
 // @Success      200      {object}  response.Response{data=[]entity.Manga}
@@ -76,6 +83,7 @@ func (h *MangaHandler) List(w http.ResponseWriter, r *http.Request) {
 		Title:  title,
 		Status: status,
 		Genres: genres,
+		Sort:   r.URL.Query().Get("sort"),
 		Limit:  limit,
 		Offset: offset,
 	}
@@ -98,11 +106,15 @@ func (h *MangaHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // GetByID обрабатывает запрос на получение манги по ID
 // @Summary      Получить мангу
-// @Description  Получить детальную информацию о манге по ID
+// @Description  Получить детальную информацию о манге по ID. Раздел related содержит связанную мангу
+// @Description  (продолжения, спин-оффы, адаптации) и похожую по жанрам и авторам. Описание и localized_title
+// @Description  выбираются по параметру lang или заголовку Accept-Language
 // @Tags         manga
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "ID манги"
+// @Param        id               path    int     true   "ID манги"
+// @Param        lang             query   string  false  "Язык описания и названия"
+// @Param        Accept-Language  header  string  false  "Предпочитаемые языки"
 // @Success      200  {object}  response.Response{data=entity.Manga}
 // @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
@@ -134,6 +146,7 @@ func (h *MangaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Success      201    {object}  response.Response{data=entity.Manga}
 // @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409    {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
 // @Security     Bearer
 // @Router       /manga [post]
@@ -165,6 +178,7 @@ func (h *MangaHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      404    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409    {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
 // @Security     Bearer
 // @Router       /manga/{id} [put]
@@ -223,14 +237,98 @@ func (h *MangaHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.NoContent(w)
 }
 
+// SetRelation обрабатывает запрос на создание связи между мангой
+// @Summary      Связать мангу
+// @Description  Создать или изменить связь с другой мангой (sequel, prequel, spin_off, parent_story, adaptation,
+// @Description  source, same_universe). Обратная связь у другой манги создается автоматически
+// @Tags         manga
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                        true  "ID манги"
+// @Param        relation  body      entity.MangaRelationInput  true  "Связанная манга и тип связи"
+// @Success      200       {object}  response.Response{data=[]entity.MangaRelation}
+// @Failure      400       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500       {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/relations [put]
+func (h *MangaHandler) SetRelation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var input entity.MangaRelationInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	relations, err := h.mangaUseCase.SetRelation(r.Context(), userID, id, input)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, relations)
+}
+
+// RemoveRelation обрабатывает запрос на удаление связи между мангой
+// @Summary      Удалить связь манги
+// @Description  Удалить связь с другой мангой вместе с обратной связью
+// @Tags         manga
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int  true  "ID манги"
+// @Param        relatedID  path      int  true  "ID связанной манги"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/relations/{relatedID} [delete]
+func (h *MangaHandler) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	relatedID, err := strconv.ParseInt(chi.URLParam(r, "relatedID"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID связанной манги", err))
+		return
+	}
+
+	if err = h.mangaUseCase.RemoveRelation(r.Context(), id, relatedID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
 // GetChapters обрабатывает запрос на получение глав манги
 // @Summary      Получить главы манги
-// @Description  Получить список всех глав манги
+// @Description  Получить список всех глав манги с числом страниц и просмотров. Для аутентифицированного пользователя главы содержат отметку read,
+// @Description  а meta — количество прочитанных и непрочитанных глав. Без параметра languages аутентифицированный
+// @Description  пользователь получает главы на предпочитаемых языках, если они есть
 // @Tags         manga
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "ID манги"
-// @Success      200  {object}  response.Response{data=[]entity.Chapter}
+// @Param        id             path   int     true   "ID манги"
+// @Param        languages      query  string  false  "Языки перевода через запятую"
+// @Param        all_languages  query  bool    false  "Все переводы без учета предпочитаемых языков"
+// @Success      200  {object}  response.Response{data=[]entity.ChapterWithReadState,meta=entity.ChapterReadSummary}
 // @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
 // @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
@@ -243,13 +341,113 @@ func (h *MangaHandler) GetChapters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chapters, err := h.mangaUseCase.GetChapters(r.Context(), id)
+	filter, err := parseChapterFilter(r)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	chapters, err := h.mangaUseCase.GetChapters(r.Context(), id, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Success(w, http.StatusOK, chapters)
+		return
+	}
+
+	withReadState, summary, err := h.chapterReadUseCase.Annotate(r.Context(), userID, id, chapters)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, withReadState, summary)
+}
+
+// GetVolumes обрабатывает запрос на получение томов манги
+// @Summary      Тома манги
+// @Description  Получить главы манги, сгруппированные по томам, с названиями и обложками томов.
+// @Description  Главы без тома собраны в последнюю группу с number = null. Языки выбираются как в списке глав
+// @Tags         manga
+// @Accept       json
+// @Produce      json
+// @Param        id             path   int     true   "ID манги"
+// @Param        languages      query  string  false  "Языки перевода через запятую"
+// @Param        all_languages  query  bool    false  "Все переводы без учета предпочитаемых языков"
+// @Success      200  {object}  response.Response{data=[]entity.Volume}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /manga/{id}/volumes [get]
+func (h *MangaHandler) GetVolumes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	filter, err := parseChapterFilter(r)
 	if err != nil {
 		response.Error(w, h.log, err)
 		return
 	}
 
-	response.Success(w, http.StatusOK, chapters)
+	volumes, err := h.mangaUseCase.GetVolumes(r.Context(), id, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, volumes)
+}
+
+// SetVolume обрабатывает запрос на изменение тома
+// @Summary      Изменить том
+// @Description  Задать название и обложку тома манги
+// @Tags         manga
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                 true  "ID манги"
+// @Param        number  path      int                 true  "Номер тома"
+// @Param        volume  body      entity.VolumeInput  true  "Название и обложка"
+// @Success      200     {object}  response.Response{data=entity.VolumeInfo}
+// @Failure      400     {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401     {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403     {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404     {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500     {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/volumes/{number} [put]
+func (h *MangaHandler) SetVolume(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный номер тома", err))
+		return
+	}
+
+	var input entity.VolumeInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	volume, err := h.mangaUseCase.SetVolume(r.Context(), id, number, input)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, volume)
 }
 
 // GetPopular обрабатывает запрос на получение популярной манги
@@ -296,6 +494,23 @@ func (h *MangaHandler) GetPopular(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, popular)
 }
 
+// parseChapterFilter разбирает языки перевода из параметров languages и all_languages
+func parseChapterFilter(r *http.Request) (entity.ChapterFilter, error) {
+	filter := entity.ChapterFilter{AllLanguages: r.URL.Query().Get("all_languages") == "true"}
+	for _, value := range strings.Split(r.URL.Query().Get("languages"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		language := usecase.NormalizeLanguage(value)
+		if language == "" {
+			return filter, errors.NewBadRequestError("Некорректный код языка", nil)
+		}
+		filter.Languages = append(filter.Languages, language)
+	}
+
+	return filter, nil
+}
+
 // parseGenres разбивает строку с жанрами на список
 func parseGenres(genresStr string) []string {
 	return nil // Заглушка, будет реализована позже
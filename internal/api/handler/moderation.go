@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ModerationHandler обработчик запросов модераторов
+type ModerationHandler struct {
+	moderationUseCase usecase.ModerationUseCase
+	log               logger.Logger
+}
+
+// NewModerationHandler создает новый экземпляр ModerationHandler
+func NewModerationHandler(moderationUseCase usecase.ModerationUseCase, log logger.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		moderationUseCase: moderationUseCase,
+		log:               log,
+	}
+}
+
+// Queue обрабатывает запрос на получение очереди модерации
+// @Summary      Очередь модерации
+// @Description  Получить комментарии с открытыми жалобами, начиная с наиболее обжалованных
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        limit   query     int  false  "Лимит результатов"
+// @Param        offset  query     int  false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.ModerationQueueItem}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /moderation/queue [get]
+func (h *ModerationHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := entity.ModerationQueueFilter{Limit: 20}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	items, total, err := h.moderationUseCase.Queue(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, items, meta)
+}
+
+// Act обрабатывает запрос на действие модератора над комментарием
+// @Summary      Действие модератора
+// @Description  Выполнить действие над комментарием: hide, unhide, delete, warn (уведомление автору с причиной), ban (блокировка автора), dismiss (отклонить жалобы).
+// @Description  Открытые жалобы на комментарий закрываются, действие записывается в журнал модерации
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                             true  "ID комментария"
+// @Param        action  body      entity.ModerationActionRequest  true  "Действие"
+// @Success      200  {object}  response.Response{data=entity.CommentWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /moderation/comments/{id}/actions [post]
+func (h *ModerationHandler) Act(w http.ResponseWriter, r *http.Request) {
+	moderatorID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.ModerationActionRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	comment, err := h.moderationUseCase.Act(r.Context(), moderatorID, id, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, comment)
+}
+
+// Log обрабатывает запрос на получение журнала модерации
+// @Summary      Журнал модерации
+// @Description  Получить действия модераторов и автоматические скрытия от новых к старым
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        moderator_id    query     int     false  "ID модератора"
+// @Param        target_user_id  query     int     false  "ID автора комментария"
+// @Param        action          query     string  false  "Действие: hide, unhide, delete, warn, ban, dismiss, auto_hide"
+// @Param        limit           query     int     false  "Лимит результатов"
+// @Param        offset          query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.ModerationLogEntry}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /moderation/log [get]
+func (h *ModerationHandler) Log(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := entity.ModerationLogFilter{
+		Action: query.Get("action"),
+		Limit:  20,
+	}
+	if value := query.Get("moderator_id"); value != "" {
+		moderatorID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID модератора", err))
+			return
+		}
+		filter.ModeratorID = &moderatorID
+	}
+	if value := query.Get("target_user_id"); value != "" {
+		targetUserID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID пользователя", err))
+			return
+		}
+		filter.TargetUserID = &targetUserID
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	entries, total, err := h.moderationUseCase.Log(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, entries, meta)
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NotificationHandler обработчик запросов для уведомлений пользователя
+type NotificationHandler struct {
+	notificationUseCase usecase.NotificationUseCase
+	log                 logger.Logger
+}
+
+// NewNotificationHandler создает новый экземпляр NotificationHandler
+func NewNotificationHandler(notificationUseCase usecase.NotificationUseCase, log logger.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+		log:                 log,
+	}
+}
+
+// List обрабатывает запрос на получение уведомлений текущего пользователя
+// @Summary      Уведомления
+// @Description  Получить уведомления текущего пользователя, начиная с новых
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        unread  query     bool  false  "Только непрочитанные"
+// @Param        limit   query     int   false  "Лимит результатов"
+// @Param        offset  query     int   false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.NotificationItem}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/notifications [get]
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := entity.NotificationFilter{Limit: 20}
+	if unread, err := strconv.ParseBool(query.Get("unread")); err == nil {
+		filter.UnreadOnly = unread
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	notifications, total, err := h.notificationUseCase.List(r.Context(), userID, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, notifications, meta)
+}
+
+// UnreadCount обрабатывает запрос на получение количества непрочитанных уведомлений
+// @Summary      Непрочитанные уведомления
+// @Description  Получить количество непрочитанных уведомлений текущего пользователя
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=entity.NotificationUnreadCount}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	count, err := h.notificationUseCase.UnreadCount(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, count)
+}
+
+// MarkRead обрабатывает запрос на отметку уведомления прочитанным
+// @Summary      Прочитать уведомление
+// @Description  Отметить уведомление прочитанным
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID уведомления"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.notificationUseCase.MarkRead(r.Context(), userID, id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// MarkAllRead обрабатывает запрос на отметку всех уведомлений прочитанными
+// @Summary      Прочитать все уведомления
+// @Description  Отметить прочитанными все уведомления текущего пользователя
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=entity.NotificationMarkResult}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	result, err := h.notificationUseCase.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/usecase"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// oidcStateCookie cookie, привязывающая state к браузеру, начавшему вход (защита от login CSRF)
+	oidcStateCookie = "oidc_state"
+	// oidcStateCookiePath ограничивает отправку cookie маршрутами входа через провайдеров
+	oidcStateCookiePath = "/api/v1/users/oidc"
+	// oidcStateCookieMaxAge совпадает со временем жизни state в кэше, в секундах
+	oidcStateCookieMaxAge = 10 * 60
+)
+
+// OIDCHandler обработчик запросов для входа через внешних OpenID Connect провайдеров
+type OIDCHandler struct {
+	oidcUseCase usecase.OIDCUseCase
+	log         logger.Logger
+}
+
+// NewOIDCHandler создает новый экземпляр OIDCHandler
+func NewOIDCHandler(oidcUseCase usecase.OIDCUseCase, log logger.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUseCase: oidcUseCase,
+		log:         log,
+	}
+}
+
+// Login обрабатывает запрос на начало входа через внешнего провайдера
+// @Summary      Вход через провайдера
+// @Description  Перенаправить пользователя на страницу авторизации OpenID Connect провайдера (authorization code + PKCE)
+// @Tags         users
+// @Produce      json
+// @Param        provider  path      string  true   "Имя провайдера"
+// @Param        redirect  query     bool    false  "false — вернуть адрес авторизации в JSON вместо редиректа"
+// @Success      200       {object}  response.Response{data=entity.OIDCAuthorization}
+// @Success      302       {object}  nil
+// @Failure      404       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500       {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /users/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	authorization, err := h.oidcUseCase.BeginLogin(r.Context(), provider)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	setOIDCStateCookie(w, r, authorization.State, oidcStateCookieMaxAge)
+
+	if r.URL.Query().Get("redirect") == "false" {
+		response.Success(w, http.StatusOK, authorization)
+		return
+	}
+
+	http.Redirect(w, r, authorization.AuthorizationURL, http.StatusFound)
+}
+
+// Callback обрабатывает возврат пользователя от внешнего провайдера
+// @Summary      Завершение входа через провайдера
+// @Description  Обменять код авторизации на токены приложения. State должен совпадать с cookie, выставленной при начале входа
+// @Tags         users
+// @Produce      json
+// @Param        provider  path      string  true  "Имя провайдера"
+// @Param        code      query     string  true  "Код авторизации"
+// @Param        state     query     string  true  "Значение state"
+// @Success      200       {object}  response.Response{data=entity.TokenPair}
+// @Failure      400       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500       {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /users/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.log.Warn("Провайдер вернул ошибку авторизации", "provider", provider, "error", providerErr)
+		response.Unauthorized(w, h.log, "Вход через провайдера отменен")
+		return
+	}
+
+	state := query.Get("state")
+	cookie, err := r.Cookie(oidcStateCookie)
+	setOIDCStateCookie(w, r, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		h.log.Warn("State не совпадает с cookie браузера", "event", "oidc_state_mismatch", "provider", provider)
+		response.Unauthorized(w, h.log, "Вход начат в другом браузере или истек")
+		return
+	}
+
+	tokens, err := h.oidcUseCase.CompleteLogin(r.Context(), provider, state, query.Get("code"))
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tokens)
+}
+
+// Link обрабатывает запрос на привязку внешнего провайдера к текущему пользователю
+// @Summary      Привязка провайдера
+// @Description  Начать привязку учетной записи OpenID Connect провайдера к аутентифицированному пользователю. После возврата от провайдера вход завершается через callback
+// @Tags         users
+// @Produce      json
+// @Param        provider  path      string  true  "Имя провайдера"
+// @Success      200       {object}  response.Response{data=entity.OIDCAuthorization}
+// @Failure      401       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404       {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500       {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/oidc/{provider}/link [post]
+func (h *OIDCHandler) Link(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	authorization, err := h.oidcUseCase.BeginLink(r.Context(), chi.URLParam(r, "provider"), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	setOIDCStateCookie(w, r, authorization.State, oidcStateCookieMaxAge)
+
+	response.Success(w, http.StatusOK, authorization)
+}
+
+// setOIDCStateCookie выставляет или (при maxAge < 0) удаляет cookie со state
+func setOIDCStateCookie(w http.ResponseWriter, r *http.Request, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcStateCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		// Lax: cookie отправляется при возврате от провайдера обычным переходом
+		SameSite: http.SameSiteLaxMode,
+	})
+}
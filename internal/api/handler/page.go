@@ -1 +1,444 @@
 package handler
+
+import (
+	"encoding/json"
+	"io"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Параметры изображений страниц
+const (
+	pageImageMaxBytes     = 20 << 20  // максимальный размер загружаемого изображения
+	chapterImagesMaxBytes = 200 << 20 // максимальный размер всех изображений при замене страниц главы
+	pageImageRoot         = "uploads" // изображения отдаются только из этого каталога
+)
+
+// PageHandler обработчик запросов для API страниц
+type PageHandler struct {
+	pageUseCase usecase.PageUseCase
+	log         logger.Logger
+}
+
+// NewPageHandler создает новый экземпляр PageHandler
+func NewPageHandler(pageUseCase usecase.PageUseCase, log logger.Logger) *PageHandler {
+	return &PageHandler{
+		pageUseCase: pageUseCase,
+		log:         log,
+	}
+}
+
+// GetByID обрабатывает запрос на получение страницы
+// @Summary      Получить страницу
+// @Description  Получить страницу главы по ID
+// @Tags         pages
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID страницы"
+// @Success      200  {object}  response.Response{data=entity.Page}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /pages/{id} [get]
+func (h *PageHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	page, err := h.pageUseCase.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, page)
+}
+
+// ServeImage обрабатывает запрос на получение изображения страницы
+// @Summary      Изображение страницы
+// @Description  Получить файл изображения страницы. Просмотры не учитываются. Для глав в раннем доступе
+// @Description  читалка выдает подписанные ссылки с expires и signature: тег <img> не умеет отправлять заголовок Authorization
+// @Tags         pages
+// @Produce      image/jpeg,image/png,image/webp
+// @Param        id         path      int     true   "ID страницы"
+// @Param        expires    query     int     false  "Окончание действия подписанной ссылки (Unix-время)"
+// @Param        signature  query     string  false  "Подпись ссылки"
+// @Success      200        {file}    file
+// @Failure      400        {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403        {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404        {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500        {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /pages/{id}/image [get]
+func (h *PageHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var signed *entity.PageImageSignature
+	if signature := r.URL.Query().Get("signature"); signature != "" {
+		expiresAt, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+		if err != nil {
+			response.Error(w, h.log, errors.NewBadRequestError("Некорректный параметр expires", err))
+			return
+		}
+		signed = &entity.PageImageSignature{ExpiresAt: expiresAt, Signature: signature}
+	}
+
+	page, err := h.pageUseCase.GetImage(r.Context(), id, signed)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	// Путь задается при создании страницы, поэтому файлы вне каталога загрузок не отдаются
+	rel, err := filepath.Rel(pageImageRoot, filepath.Clean(page.ImagePath))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		response.Error(w, h.log, errors.NewNotFoundError("Изображение страницы не найдено", err))
+		return
+	}
+
+	// Изображения глав в раннем доступе не должны оседать в общих кешах прокси
+	w.Header().Set("Cache-Control", "private")
+	http.ServeFile(w, r, filepath.Join(pageImageRoot, rel))
+}
+
+// Create обрабатывает запрос на создание новой страницы
+// @Summary      Создать страницу
+// @Description  Создать страницу главы для уже загруженного изображения
+// @Tags         pages
+// @Accept       json
+// @Produce      json
+// @Param        page  body      entity.Page  true  "Данные страницы"
+// @Success      201   {object}  response.Response{data=entity.Page}
+// @Failure      400   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500   {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /pages [post]
+func (h *PageHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var page entity.Page
+	if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	createdPage, err := h.pageUseCase.Create(r.Context(), &page)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, createdPage)
+}
+
+// UploadImage обрабатывает запрос на загрузку изображения страницы
+// @Summary      Загрузить страницу
+// @Description  Загрузить изображение и создать из него страницу главы
+// @Tags         pages
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        chapter_id  formData  int   true  "ID главы"
+// @Param        number      formData  int   true  "Номер страницы"
+// @Param        image       formData  file  true  "Изображение страницы"
+// @Success      201  {object}  response.Response{data=entity.Page}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /pages/upload [post]
+func (h *PageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, pageImageMaxBytes)
+	if err := r.ParseMultipartForm(pageImageMaxBytes); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка чтения формы или файл слишком большой", err))
+		return
+	}
+
+	chapterID, err := strconv.ParseInt(r.FormValue("chapter_id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID главы", err))
+		return
+	}
+
+	number, err := strconv.Atoi(r.FormValue("number"))
+	if err != nil || number <= 0 {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный номер страницы", err))
+		return
+	}
+
+	image, err := formPageImage(r)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	page, err := h.pageUseCase.UploadImage(r.Context(), chapterID, number, image.Filename, image.Data)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, page)
+}
+
+// Update обрабатывает запрос на обновление страницы
+// @Summary      Обновить страницу
+// @Description  Обновить номер, главу или изображение страницы
+// @Tags         pages
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int          true  "ID страницы"
+// @Param        page  body      entity.Page  true  "Новые данные страницы"
+// @Success      200   {object}  response.Response{data=entity.Page}
+// @Failure      400   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500   {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /pages/{id} [put]
+func (h *PageHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var page entity.Page
+	if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	page.ID = id
+
+	updatedPage, err := h.pageUseCase.Update(r.Context(), &page)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, updatedPage)
+}
+
+// Delete обрабатывает запрос на удаление страницы
+// @Summary      Удалить страницу
+// @Description  Удалить страницу и ее изображение
+// @Tags         pages
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID страницы"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /pages/{id} [delete]
+func (h *PageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.pageUseCase.Delete(r.Context(), id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Reorder обрабатывает запрос на изменение порядка страниц главы
+// @Summary      Изменить порядок страниц
+// @Description  Перенумеровать страницы главы с 1 в переданном порядке. Список должен содержать каждую страницу главы ровно один раз
+// @Tags         pages
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                    true  "ID главы"
+// @Param        order  body      entity.PageOrderInput  true  "ID страниц в новом порядке"
+// @Success      200    {object}  response.Response{data=[]entity.Page}
+// @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id}/pages/order [put]
+func (h *PageHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var input entity.PageOrderInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	pages, err := h.pageUseCase.Reorder(r.Context(), chapterID, input.PageIDs)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, pages)
+}
+
+// Insert обрабатывает запрос на вставку страницы в главу
+// @Summary      Вставить страницу
+// @Description  Загрузить изображение и вставить страницу на позицию, сдвинув эту и последующие страницы главы
+// @Tags         pages
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id        path      int   true  "ID главы"
+// @Param        position  formData  int   true  "Позиция новой страницы, от 1 до номера последней страницы + 1"
+// @Param        image     formData  file  true  "Изображение страницы"
+// @Success      201  {object}  response.Response{data=entity.Page}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id}/pages/insert [post]
+func (h *PageHandler) Insert(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, pageImageMaxBytes)
+	if err := r.ParseMultipartForm(pageImageMaxBytes); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка чтения формы или файл слишком большой", err))
+		return
+	}
+
+	position, err := strconv.Atoi(r.FormValue("position"))
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректная позиция страницы", err))
+		return
+	}
+
+	image, err := formPageImage(r)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	page, err := h.pageUseCase.Insert(r.Context(), chapterID, position, image)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, page)
+}
+
+// ReplaceAll обрабатывает запрос на замену всех страниц главы
+// @Summary      Заменить страницы главы
+// @Description  Загрузить изображения и заменить ими все страницы главы. Страницы нумеруются в порядке файлов
+// @Tags         pages
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id      path      int   true  "ID главы"
+// @Param        images  formData  file  true  "Изображения страниц по порядку"
+// @Success      200  {object}  response.Response{data=[]entity.Page}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /chapters/{id}/pages [put]
+func (h *PageHandler) ReplaceAll(w http.ResponseWriter, r *http.Request) {
+	chapterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, chapterImagesMaxBytes)
+	if err := r.ParseMultipartForm(pageImageMaxBytes); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка чтения формы или файлы слишком большие", err))
+		return
+	}
+
+	headers := r.MultipartForm.File["images"]
+	images := make([]*entity.PageImage, 0, len(headers))
+	for _, header := range headers {
+		image, err := readPageImage(header)
+		if err != nil {
+			response.Error(w, h.log, err)
+			return
+		}
+		images = append(images, image)
+	}
+
+	pages, err := h.pageUseCase.ReplaceAll(r.Context(), chapterID, images)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, pages)
+}
+
+// formPageImage читает изображение страницы из поля image разобранной формы
+func formPageImage(r *http.Request) (*entity.PageImage, error) {
+	_, header, err := r.FormFile("image")
+	if err != nil {
+		return nil, errors.NewBadRequestError("Не передано изображение", err)
+	}
+
+	return readPageImage(header)
+}
+
+// readPageImage читает загруженный файл и проверяет, что это изображение
+func readPageImage(header *multipart.FileHeader) (*entity.PageImage, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, errors.NewBadRequestError("Ошибка чтения изображения", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.NewBadRequestError("Ошибка чтения изображения", err)
+	}
+
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		return nil, errors.NewValidationError("Файл не является изображением", map[string]interface{}{
+			"filename": header.Filename,
+		})
+	}
+
+	return &entity.PageImage{Filename: header.Filename, Data: data}, nil
+}
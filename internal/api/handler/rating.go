@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RatingHandler обработчик запросов для оценок манги
+type RatingHandler struct {
+	ratingUseCase usecase.RatingUseCase
+	log           logger.Logger
+}
+
+// NewRatingHandler создает новый экземпляр RatingHandler
+func NewRatingHandler(ratingUseCase usecase.RatingUseCase, log logger.Logger) *RatingHandler {
+	return &RatingHandler{
+		ratingUseCase: ratingUseCase,
+		log:           log,
+	}
+}
+
+// Summary обрабатывает запрос на получение оценок манги
+// @Summary      Оценки манги
+// @Description  Получить среднюю и байесовскую оценки манги с гистограммой по оценкам от 1 до 10.
+// @Description  Аутентифицированный пользователь также получает свою оценку
+// @Tags         ratings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID манги"
+// @Success      200  {object}  response.Response{data=entity.MangaRatingSummary}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /manga/{id}/rating [get]
+func (h *RatingHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	summary, err := h.ratingUseCase.Summary(r.Context(), mangaID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, summary)
+}
+
+// Rate обрабатывает запрос на оценку манги
+// @Summary      Оценить мангу
+// @Description  Поставить или изменить оценку манги от 1 до 10
+// @Tags         ratings
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                      true  "ID манги"
+// @Param        rating  body      entity.MangaRatingInput  true  "Оценка"
+// @Success      200  {object}  response.Response{data=entity.MangaRatingSummary}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/rating [put]
+func (h *RatingHandler) Rate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.MangaRatingInput
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	summary, err := h.ratingUseCase.Rate(r.Context(), userID, mangaID, req.Score)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, summary)
+}
+
+// Remove обрабатывает запрос на удаление оценки манги
+// @Summary      Удалить оценку
+// @Description  Удалить свою оценку манги
+// @Tags         ratings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID манги"
+// @Success      204  "No Content"
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/rating [delete]
+func (h *RatingHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.ratingUseCase.Remove(r.Context(), userID, mangaID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
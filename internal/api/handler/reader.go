@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ReaderHandler обработчик запросов читалки
+type ReaderHandler struct {
+	readerUseCase usecase.ReaderUseCase
+	log           logger.Logger
+}
+
+// NewReaderHandler создает новый экземпляр ReaderHandler
+func NewReaderHandler(readerUseCase usecase.ReaderUseCase, log logger.Logger) *ReaderHandler {
+	return &ReaderHandler{
+		readerUseCase: readerUseCase,
+		log:           log,
+	}
+}
+
+// Get обрабатывает запрос на открытие главы в читалке
+// @Summary      Открыть главу в читалке
+// @Description  Получить главу, краткие данные манги, упорядоченные страницы с адресами изображений и соседние главы на том же языке одним запросом
+// @Description  Для глав в раннем доступе и черновиков адреса изображений подписаны и действуют ограниченное время
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID главы"
+// @Success      200  {object}  response.Response{data=entity.ReaderBootstrap}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /chapters/{id}/reader [get]
+func (h *ReaderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	bootstrap, err := h.readerUseCase.Get(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, bootstrap)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/domain/entity"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetReadingHistory обрабатывает запрос на получение истории чтения текущего пользователя
+// @Summary      История чтения
+// @Description  Получить историю чтения текущего пользователя, последние прочитанные главы первыми
+// @Tags         history
+// @Accept       json
+// @Produce      json
+// @Param        limit   query     int  false  "Лимит результатов"
+// @Param        offset  query     int  false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.ReadingHistoryItem}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/history [get]
+func (h *UserHandler) GetReadingHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := entity.ReadingHistoryFilter{
+		Limit: 20,
+	}
+
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	items, total, err := h.historyUseCase.List(r.Context(), userID, filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, items, meta)
+}
+
+// ContinueReading обрабатывает запрос на получение позиций для продолжения чтения
+// @Summary      Продолжить чтение
+// @Description  Получить последнюю прочитанную главу и страницу по каждой манге
+// @Tags         history
+// @Accept       json
+// @Produce      json
+// @Param        limit  query     int  false  "Количество манги"
+// @Success      200  {object}  response.Response{data=[]entity.ReadingHistoryItem}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/history/continue [get]
+func (h *UserHandler) ContinueReading(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	limit := 10
+	if parsedLimit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsedLimit > 0 {
+		limit = parsedLimit
+	}
+
+	items, err := h.historyUseCase.ContinueReading(r.Context(), userID, limit)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, items)
+}
+
+// RemoveFromHistory обрабатывает запрос на удаление записи из истории чтения
+// @Summary      Удалить запись истории
+// @Description  Удалить запись из истории чтения текущего пользователя
+// @Tags         history
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID записи"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/history/{id} [delete]
+func (h *UserHandler) RemoveFromHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.historyUseCase.Remove(r.Context(), userID, id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// ClearHistory обрабатывает запрос на очистку истории чтения
+// @Summary      Очистить историю
+// @Description  Удалить всю историю чтения текущего пользователя
+// @Tags         history
+// @Accept       json
+// @Produce      json
+// @Success      204  {object}  nil
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/history [delete]
+func (h *UserHandler) ClearHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	if err := h.historyUseCase.Clear(r.Context(), userID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
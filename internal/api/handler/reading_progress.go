@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ReadingProgressHandler обработчик запросов для синхронизации прогресса чтения между устройствами
+type ReadingProgressHandler struct {
+	progressUseCase usecase.ReadingProgressUseCase
+	log             logger.Logger
+}
+
+// NewReadingProgressHandler создает новый экземпляр ReadingProgressHandler
+func NewReadingProgressHandler(progressUseCase usecase.ReadingProgressUseCase, log logger.Logger) *ReadingProgressHandler {
+	return &ReadingProgressHandler{
+		progressUseCase: progressUseCase,
+		log:             log,
+	}
+}
+
+// List обрабатывает запрос на получение позиций чтения текущего пользователя
+// @Summary      Прогресс чтения
+// @Description  Получить позиции чтения по всей манге; с параметром since — только измененные после указанного времени
+// @Tags         progress
+// @Accept       json
+// @Produce      json
+// @Param        since  query     string  false  "Время последней синхронизации (RFC3339)"
+// @Success      200  {object}  response.Response{data=[]entity.ReadingProgress}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/progress [get]
+func (h *ReadingProgressHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	since, err := parseTimeParam(r.URL.Query().Get("since"))
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный параметр since", err))
+		return
+	}
+
+	progress, err := h.progressUseCase.List(r.Context(), userID, since)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, progress)
+}
+
+// Get обрабатывает запрос на получение позиции чтения манги
+// @Summary      Прогресс чтения манги
+// @Description  Получить сохраненную позицию чтения манги
+// @Tags         progress
+// @Accept       json
+// @Produce      json
+// @Param        mangaID  path      int  true  "ID манги"
+// @Success      200  {object}  response.Response{data=entity.ReadingProgress}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/progress/{mangaID} [get]
+func (h *ReadingProgressHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaIDStr := chi.URLParam(r, "mangaID")
+	mangaID, err := strconv.ParseInt(mangaIDStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+		return
+	}
+
+	progress, err := h.progressUseCase.Get(r.Context(), userID, mangaID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, progress)
+}
+
+// Update обрабатывает запрос на сохранение позиции чтения манги
+// @Summary      Сохранить прогресс чтения
+// @Description  Сохранить главу, страницу и прокрутку с устройства. При конфликте побеждает более позднее изменение (latest_write) или позиция дальше по манге (furthest_read)
+// @Tags         progress
+// @Accept       json
+// @Produce      json
+// @Param        mangaID   path      int                    true  "ID манги"
+// @Param        progress  body      entity.ProgressUpdate  true  "Позиция чтения"
+// @Success      200  {object}  response.Response{data=entity.ProgressSyncResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/progress/{mangaID} [put]
+func (h *ReadingProgressHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaIDStr := chi.URLParam(r, "mangaID")
+	mangaID, err := strconv.ParseInt(mangaIDStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID манги", err))
+		return
+	}
+
+	var update entity.ProgressUpdate
+	if err = json.NewDecoder(r.Body).Decode(&update); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	result, err := h.progressUseCase.Update(r.Context(), userID, mangaID, &update)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
+
+// Sync обрабатывает запрос на пакетную синхронизацию позиций чтения
+// @Summary      Пакетная синхронизация прогресса
+// @Description  Применить позиции, накопленные устройством без сети. Для каждой манги возвращается актуальная позиция на сервере
+// @Tags         progress
+// @Accept       json
+// @Produce      json
+// @Param        batch  body      entity.ProgressSyncRequest  true  "Пакет позиций"
+// @Success      200  {object}  response.Response{data=[]entity.ProgressSyncResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/progress/sync [post]
+func (h *ReadingProgressHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var req entity.ProgressSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	results, err := h.progressUseCase.Sync(r.Context(), userID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, results)
+}
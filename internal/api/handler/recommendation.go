@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+)
+
+// RecommendationHandler обработчик запросов персональных рекомендаций
+type RecommendationHandler struct {
+	recommendationUseCase usecase.RecommendationUseCase
+	log                   logger.Logger
+}
+
+// NewRecommendationHandler создает новый экземпляр RecommendationHandler
+func NewRecommendationHandler(recommendationUseCase usecase.RecommendationUseCase, log logger.Logger) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationUseCase: recommendationUseCase,
+		log:                   log,
+	}
+}
+
+// List обрабатывает запрос на получение рекомендаций текущего пользователя
+// @Summary      Рекомендации
+// @Description  Получить подборку манги "для вас": похожую по совместному чтению на прочитанную
+// @Description  и подходящую по жанрам, без уже прочитанной и добавленной в библиотеку.
+// @Description  Пользователю без истории чтения подбирается популярная манга (reason=popular)
+// @Tags         recommendations
+// @Accept       json
+// @Produce      json
+// @Param        limit   query     int  false  "Лимит результатов"
+// @Param        offset  query     int  false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.Recommendation}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me/recommendations [get]
+func (h *RecommendationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := 20
+	if value, err := strconv.Atoi(query.Get("limit")); err == nil && value > 0 && value <= 100 {
+		limit = value
+	}
+	offset := 0
+	if value, err := strconv.Atoi(query.Get("offset")); err == nil && value >= 0 {
+		offset = value
+	}
+
+	recommendations, total, err := h.recommendationUseCase.List(r.Context(), userID, limit, offset)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     limit,
+		CurrentPage: offset/limit + 1,
+		LastPage:    (total + limit - 1) / limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, recommendations, meta)
+}
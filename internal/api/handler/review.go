@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ReviewHandler обработчик запросов для рецензий
+type ReviewHandler struct {
+	reviewUseCase usecase.ReviewUseCase
+	log           logger.Logger
+}
+
+// NewReviewHandler создает новый экземпляр ReviewHandler
+func NewReviewHandler(reviewUseCase usecase.ReviewUseCase, log logger.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		reviewUseCase: reviewUseCase,
+		log:           log,
+	}
+}
+
+// ListMangaReviews обрабатывает запрос на получение рецензий манги
+// @Summary      Рецензии манги
+// @Description  Получить рецензии манги с оценкой каждого автора
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int     true   "ID манги"
+// @Param        sort    query     string  false  "Сортировка: helpful (по умолчанию), newest"
+// @Param        limit   query     int     false  "Лимит результатов"
+// @Param        offset  query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.ReviewWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /manga/{id}/reviews [get]
+func (h *ReviewHandler) ListMangaReviews(w http.ResponseWriter, r *http.Request) {
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := entity.ReviewFilter{
+		MangaID: mangaID,
+		Sort:    query.Get("sort"),
+		Limit:   20,
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	reviews, total, err := h.reviewUseCase.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, reviews, meta)
+}
+
+// Create обрабатывает запрос на публикацию рецензии
+// @Summary      Написать рецензию
+// @Description  Опубликовать рецензию на мангу (не короче 100 символов, поддерживается Markdown).
+// @Description  У пользователя может быть только одна рецензия на мангу
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                 true  "ID манги"
+// @Param        review  body      entity.ReviewInput  true  "Рецензия"
+// @Success      201  {object}  response.Response{data=entity.ReviewWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/reviews [post]
+func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.ReviewInput
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	review, err := h.reviewUseCase.Create(r.Context(), userID, mangaID, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Created(w, review)
+}
+
+// GetByID обрабатывает запрос на получение рецензии
+// @Summary      Получить рецензию
+// @Description  Получить рецензию по ID
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID рецензии"
+// @Success      200  {object}  response.Response{data=entity.ReviewWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /reviews/{id} [get]
+func (h *ReviewHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	review, err := h.reviewUseCase.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, review)
+}
+
+// Update обрабатывает запрос на редактирование рецензии
+// @Summary      Редактировать рецензию
+// @Description  Изменить заголовок и текст собственной рецензии
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                 true  "ID рецензии"
+// @Param        review  body      entity.ReviewInput  true  "Рецензия"
+// @Success      200  {object}  response.Response{data=entity.ReviewWithUser}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /reviews/{id} [put]
+func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.ReviewInput
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	review, err := h.reviewUseCase.Update(r.Context(), userID, id, &req)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, review)
+}
+
+// Delete обрабатывает запрос на удаление рецензии
+// @Summary      Удалить рецензию
+// @Description  Удалить собственную рецензию; модераторы могут удалять любые
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID рецензии"
+// @Success      204  "No Content"
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /reviews/{id} [delete]
+func (h *ReviewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	if err = h.reviewUseCase.Delete(r.Context(), userID, id); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Vote обрабатывает запрос на оценку полезности рецензии
+// @Summary      Оценить полезность рецензии
+// @Description  Отметить рецензию полезной (1) или бесполезной (-1); значение 0 снимает отметку
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                true  "ID рецензии"
+// @Param        vote  body      entity.ReviewVote  true  "Оценка"
+// @Success      200  {object}  response.Response{data=entity.ReviewVoteResult}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /reviews/{id}/vote [put]
+func (h *ReviewHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req entity.ReviewVote
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	result, err := h.reviewUseCase.Vote(r.Context(), userID, id, req.Value)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RoleHandler обработчик запросов для управления ролями, разрешениями и загрузчиками манги
+type RoleHandler struct {
+	roleUseCase usecase.RoleUseCase
+	log         logger.Logger
+}
+
+// NewRoleHandler создает новый экземпляр RoleHandler
+func NewRoleHandler(roleUseCase usecase.RoleUseCase, log logger.Logger) *RoleHandler {
+	return &RoleHandler{
+		roleUseCase: roleUseCase,
+		log:         log,
+	}
+}
+
+// updatePermissionsRequest тело запроса на изменение разрешений роли
+type updatePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// assignUploaderRequest тело запроса на назначение загрузчика манги
+type assignUploaderRequest struct {
+	UserID int64 `json:"user_id"`
+}
+
+// List обрабатывает запрос на получение списка ролей
+// @Summary      Список ролей
+// @Description  Получить список ролей с их разрешениями
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]entity.Role}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /roles [get]
+func (h *RoleHandler) List(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleUseCase.List(r.Context())
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, roles)
+}
+
+// UpdatePermissions обрабатывает запрос на изменение разрешений роли
+// @Summary      Изменить разрешения роли
+// @Description  Заменить набор разрешений роли. Изменения применяются к токенам, выданным после изменения
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        name         path      string                    true  "Название роли"
+// @Param        permissions  body      updatePermissionsRequest  true  "Разрешения"
+// @Success      200  {object}  response.Response{data=entity.Role}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /roles/{name}/permissions [put]
+func (h *RoleHandler) UpdatePermissions(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req updatePermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	role, err := h.roleUseCase.UpdatePermissions(r.Context(), name, req.Permissions)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, role)
+}
+
+// AssignUploader обрабатывает запрос на назначение загрузчика манги
+// @Summary      Назначить загрузчика
+// @Description  Разрешить пользователю с ролью uploader изменять главы и страницы манги
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                    true  "ID манги"
+// @Param        user  body      assignUploaderRequest  true  "Пользователь"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/uploaders [post]
+func (h *RoleHandler) AssignUploader(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	mangaID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var req assignUploaderRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	if err = h.roleUseCase.AssignUploader(r.Context(), mangaID, req.UserID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// UnassignUploader обрабатывает запрос на снятие загрузчика манги
+// @Summary      Снять загрузчика
+// @Description  Запретить пользователю изменять главы и страницы манги
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int  true  "ID манги"
+// @Param        userID  path      int  true  "ID пользователя"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /manga/{id}/uploaders/{userID} [delete]
+func (h *RoleHandler) UnassignUploader(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	mangaID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	userIDStr := chi.URLParam(r, "userID")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID пользователя", err))
+		return
+	}
+
+	if err = h.roleUseCase.UnassignUploader(r.Context(), mangaID, userID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Параметры потоковых соединений
+const (
+	streamHeartbeatInterval = 25 * time.Second
+	streamWriteTimeout      = 10 * time.Second
+	// streamRetryMillis подсказывает EventSource, через сколько переподключаться после обрыва
+	streamRetryMillis = 5000
+)
+
+// StreamHandler обработчик потока событий реального времени
+type StreamHandler struct {
+	streamUseCase usecase.StreamUseCase
+	log           logger.Logger
+}
+
+// NewStreamHandler создает новый экземпляр StreamHandler
+func NewStreamHandler(streamUseCase usecase.StreamUseCase, log logger.Logger) *StreamHandler {
+	return &StreamHandler{
+		streamUseCase: streamUseCase,
+		log:           log,
+	}
+}
+
+// streamMessage представляет событие в сообщении WebSocket
+type streamMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Stream godoc
+// @Summary      Поток событий
+// @Description  Уведомления и выход новых глав в реальном времени через Server-Sent Events.
+// @Description  С заголовками Upgrade: websocket соединение переводится на WebSocket (события приходят текстовыми JSON-кадрами).
+// @Description  Для возобновления после обрыва передайте ID последнего события в заголовке Last-Event-ID или параметре last_event_id.
+// @Description  Если часть событий уже недоступна, первым приходит событие reset — состояние нужно перезапросить через API.
+// @Description  Браузерные клиенты, не умеющие передавать заголовки, могут указать токен в параметре access_token
+// @Tags         stream
+// @Produce      text/event-stream
+// @Param        Last-Event-ID  header    string  false  "ID последнего полученного события"
+// @Param        last_event_id  query     int     false  "ID последнего полученного события"
+// @Param        access_token   query     string  false  "JWT токен доступа"
+// @Success      200  {string}  string  "Поток событий"
+// @Success      101  {string}  string  "Переход на WebSocket"
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /stream [get]
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	websocket := isWebSocketUpgrade(r)
+	if websocket {
+		if err = validateWebSocketHandshake(r); err != nil {
+			response.Error(w, h.log, err)
+			return
+		}
+	}
+
+	subscription, err := h.streamUseCase.Subscribe(r.Context(), userID, lastEventID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+	defer subscription.Close()
+
+	if websocket {
+		h.serveWebSocket(w, r, subscription)
+		return
+	}
+	h.serveSSE(w, r, subscription)
+}
+
+// serveSSE отдает события в формате text/event-stream
+func (h *StreamHandler) serveSSE(w http.ResponseWriter, r *http.Request, subscription *usecase.StreamSubscription) {
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Дедлайн записи продлевается перед каждой отправкой вместо общего WriteTimeout сервера
+	write := func(chunk string) error {
+		if err := controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	if err := write(fmt.Sprintf("retry: %d\n\n", streamRetryMillis)); err != nil {
+		h.log.Error("Ошибка открытия потока событий", "error", err.Error())
+		return
+	}
+	for _, event := range subscription.Replay {
+		if err := write(formatSSEEvent(event)); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			if subscription.Skip(event) {
+				continue
+			}
+			if err := write(formatSSEEvent(event)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := write(": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// serveWebSocket отдает события текстовыми кадрами WebSocket, heartbeat отправляется кадрами ping
+func (h *StreamHandler) serveWebSocket(w http.ResponseWriter, r *http.Request, subscription *usecase.StreamSubscription) {
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		response.Error(w, h.log, errors.NewInternalError("Соединение не поддерживает WebSocket", err))
+		return
+	}
+
+	conn, err := acceptWebSocket(netConn, rw, r.Header.Get("Sec-WebSocket-Key"))
+	if err != nil {
+		h.log.Error("Ошибка перехода на WebSocket", "error", err.Error())
+		return
+	}
+	defer conn.Close(wsCloseGoingAway)
+
+	// Клиент обязан отвечать на ping, поэтому молчание дольше двух интервалов означает обрыв
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.ReadLoop(2 * streamHeartbeatInterval)
+	}()
+
+	send := func(event *entity.StreamEvent) error {
+		payload, err := json.Marshal(&streamMessage{
+			ID:        event.ID,
+			Type:      event.Type,
+			Data:      event.Data,
+			CreatedAt: event.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return conn.WriteFrame(wsOpText, payload)
+	}
+
+	for _, event := range subscription.Replay {
+		if err = send(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			if subscription.Skip(event) {
+				continue
+			}
+			if err = send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err = conn.WriteFrame(wsOpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// formatSSEEvent форматирует событие для text/event-stream.
+// Событие reset передается без id, чтобы клиент не сдвинул Last-Event-ID
+func formatSSEEvent(event *entity.StreamEvent) string {
+	id := ""
+	if event.ID > 0 {
+		id = "id: " + strconv.FormatInt(event.ID, 10) + "\n"
+	}
+	return id + "event: " + event.Type + "\ndata: " + string(event.Data) + "\n\n"
+}
+
+// parseLastEventID получает ID последнего полученного события из заголовка Last-Event-ID или параметра last_event_id
+func parseLastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.NewBadRequestError("Некорректный Last-Event-ID", err)
+	}
+
+	return id, nil
+}
@@ -1 +1,585 @@
 package handler
+
+import (
+	"encoding/json"
+	"manga-reader2/internal/api/middleware"
+	"manga-reader2/internal/api/response"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/usecase"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserHandler обработчик запросов для API пользователей
+type UserHandler struct {
+	userUseCase     usecase.UserUseCase
+	bookmarkUseCase usecase.BookmarkUseCase
+	historyUseCase  usecase.ReadingHistoryUseCase
+	log             logger.Logger
+}
+
+// NewUserHandler создает новый экземпляр UserHandler
+func NewUserHandler(
+	userUseCase usecase.UserUseCase,
+	bookmarkUseCase usecase.BookmarkUseCase,
+	historyUseCase usecase.ReadingHistoryUseCase,
+	log logger.Logger,
+) *UserHandler {
+	return &UserHandler{
+		userUseCase:     userUseCase,
+		bookmarkUseCase: bookmarkUseCase,
+		historyUseCase:  historyUseCase,
+		log:             log,
+	}
+}
+
+// refreshTokenRequest тело запроса на обновление токена
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Register обрабатывает запрос на регистрацию пользователя
+// @Summary      Регистрация
+// @Description  Зарегистрировать нового пользователя
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        user  body      entity.UserRegistration  true  "Данные для регистрации"
+// @Success      201   {object}  response.Response{data=entity.User}
+// @Failure      400   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500   {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /users/register [post]
+func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var reg entity.UserRegistration
+	if err := json.NewDecoder(r.Body).Decode(&reg); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	user, err := h.userUseCase.Register(r.Context(), &reg)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, user)
+}
+
+// Login обрабатывает запрос на вход пользователя
+// @Summary      Вход
+// @Description  Аутентифицировать пользователя по имени (или email) и паролю
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      entity.UserCredentials  true  "Учетные данные"
+// @Success      200          {object}  response.Response{data=entity.TokenPair}
+// @Failure      400          {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401          {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      429          {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500          {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /users/login [post]
+func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var cred entity.UserCredentials
+	if err := json.NewDecoder(r.Body).Decode(&cred); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	tokens, err := h.userUseCase.Login(r.Context(), &cred, clientIP(r))
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tokens)
+}
+
+// RefreshToken обрабатывает запрос на обновление пары токенов
+// @Summary      Обновить токены
+// @Description  Получить новую пару токенов по refresh token
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        token  body      refreshTokenRequest  true  "Refresh token"
+// @Success      200    {object}  response.Response{data=entity.TokenPair}
+// @Failure      400    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401    {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500    {object}  response.Response{error=errors.ErrorResponse}
+// @Router       /users/refresh [post]
+func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req refreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	if req.RefreshToken == "" {
+		response.Error(w, h.log, errors.NewValidationError("Не указан refresh token", nil))
+		return
+	}
+
+	tokens, err := h.userUseCase.RefreshToken(r.Context(), req.RefreshToken)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tokens)
+}
+
+// GetProfile обрабатывает запрос на получение профиля текущего пользователя
+// @Summary      Профиль
+// @Description  Получить профиль текущего пользователя
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=entity.User}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me [get]
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	user, err := h.userUseCase.GetProfile(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, user)
+}
+
+// UpdateProfile обрабатывает запрос на обновление профиля текущего пользователя
+// @Summary      Обновить профиль
+// @Description  Обновить имя пользователя и email текущего пользователя
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        user  body      entity.User  true  "Новые данные профиля"
+// @Success      200   {object}  response.Response{data=entity.User}
+// @Failure      400   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      409   {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500   {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me [put]
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var user entity.User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	user.ID = userID
+
+	updatedUser, err := h.userUseCase.UpdateProfile(r.Context(), &user)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, updatedUser)
+}
+
+// GetLanguages обрабатывает запрос на получение предпочитаемых языков глав
+// @Summary      Предпочитаемые языки
+// @Description  Получить языки глав текущего пользователя в порядке приоритета
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=entity.PreferredLanguages}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me/languages [get]
+func (h *UserHandler) GetLanguages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	languages, err := h.userUseCase.GetPreferredLanguages(r.Context(), userID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, languages)
+}
+
+// SetLanguages обрабатывает запрос на изменение предпочитаемых языков глав
+// @Summary      Изменить предпочитаемые языки
+// @Description  Задать языки глав в порядке приоритета. Списки глав манги показывают переводы на этих языках,
+// @Description  если они есть. Пустой список отключает фильтрацию
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        languages  body      entity.PreferredLanguages  true  "Коды языков"
+// @Success      200        {object}  response.Response{data=entity.PreferredLanguages}
+// @Failure      400        {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401        {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500        {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/me/languages [put]
+func (h *UserHandler) SetLanguages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		response.Unauthorized(w, h.log, "Требуется авторизация")
+		return
+	}
+
+	var input entity.PreferredLanguages
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	languages, err := h.userUseCase.SetPreferredLanguages(r.Context(), userID, input.Languages)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, languages)
+}
+
+// Logout обрабатывает запрос на выход пользователя
+// @Summary      Выход
+// @Description  Завершить сессию текущего пользователя
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      204  {object}  nil
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/logout [post]
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	// Токены не хранятся на сервере, клиенту достаточно удалить их у себя
+	response.NoContent(w)
+}
+
+// UnlockUser обрабатывает запрос на снятие блокировки входа с аккаунта
+// @Summary      Разблокировать вход
+// @Description  Сбросить счетчик неудачных попыток входа и снять временную блокировку аккаунта
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID пользователя"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/{id}/unlock [post]
+func (h *UserHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+
+	if err = h.userUseCase.UnlockUser(r.Context(), id, adminID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// ListUsers обрабатывает запрос на получение списка пользователей
+// @Summary      Список пользователей
+// @Description  Получить список пользователей с поиском, фильтрацией и пагинацией
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        q               query     string  false  "Поиск по имени пользователя или email"
+// @Param        role            query     string  false  "Фильтр по роли"
+// @Param        status          query     string  false  "Фильтр по статусу (active, banned, deleted)"
+// @Param        created_after   query     string  false  "Зарегистрированы не раньше (RFC3339 или YYYY-MM-DD)"
+// @Param        created_before  query     string  false  "Зарегистрированы раньше (RFC3339 или YYYY-MM-DD)"
+// @Param        limit           query     int     false  "Лимит результатов"
+// @Param        offset          query     int     false  "Смещение результатов"
+// @Success      200  {object}  response.Response{data=[]entity.User}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users [get]
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := entity.UserFilter{
+		Query:  query.Get("q"),
+		Role:   query.Get("role"),
+		Status: query.Get("status"),
+		Limit:  20,
+	}
+
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 100 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseTimeParam(query.Get("created_after")); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный параметр created_after", err))
+		return
+	}
+	if filter.CreatedBefore, err = parseTimeParam(query.Get("created_before")); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный параметр created_before", err))
+		return
+	}
+
+	users, total, err := h.userUseCase.ListUsers(r.Context(), filter)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	meta := response.MetaPagination{
+		Total:       total,
+		PerPage:     filter.Limit,
+		CurrentPage: filter.Offset/filter.Limit + 1,
+		LastPage:    (total + filter.Limit - 1) / filter.Limit,
+	}
+
+	response.SuccessWithMeta(w, http.StatusOK, users, meta)
+}
+
+// GetUser обрабатывает запрос на получение пользователя по ID
+// @Summary      Получить пользователя
+// @Description  Получить данные пользователя, включая сведения о блокировке
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID пользователя"
+// @Success      200  {object}  response.Response{data=entity.User}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/{id} [get]
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	user, err := h.userUseCase.GetUser(r.Context(), id)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, user)
+}
+
+// UpdateUser обрабатывает запрос на изменение пользователя администратором
+// @Summary      Изменить пользователя
+// @Description  Изменить роль пользователя
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                     true  "ID пользователя"
+// @Param        user  body      entity.UserAdminUpdate  true  "Изменения"
+// @Success      200  {object}  response.Response{data=entity.User}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/{id} [put]
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var update entity.UserAdminUpdate
+	if err = json.NewDecoder(r.Body).Decode(&update); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+
+	user, err := h.userUseCase.UpdateUser(r.Context(), id, &update, adminID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, user)
+}
+
+// DeleteUser обрабатывает запрос на удаление пользователя
+// @Summary      Удалить пользователя
+// @Description  Удалить пользователя с анонимизацией персональных данных. Комментарии остаются от имени удаленного пользователя
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID пользователя"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/{id} [delete]
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+
+	if err = h.userUseCase.DeleteUser(r.Context(), id, adminID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// BanUser обрабатывает запрос на блокировку пользователя
+// @Summary      Заблокировать пользователя
+// @Description  Заблокировать пользователя с указанием причины до указанного времени или бессрочно
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int             true  "ID пользователя"
+// @Param        ban  body      entity.UserBan  true  "Параметры блокировки"
+// @Success      200  {object}  response.Response{data=entity.User}
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/{id}/ban [post]
+func (h *UserHandler) BanUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	var ban entity.UserBan
+	if err = json.NewDecoder(r.Body).Decode(&ban); err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Ошибка парсинга JSON", err))
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+
+	user, err := h.userUseCase.BanUser(r.Context(), id, &ban, adminID)
+	if err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, user)
+}
+
+// UnbanUser обрабатывает запрос на снятие блокировки пользователя
+// @Summary      Разблокировать пользователя
+// @Description  Снять блокировку, установленную администратором
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID пользователя"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      401  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      403  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      404  {object}  response.Response{error=errors.ErrorResponse}
+// @Failure      500  {object}  response.Response{error=errors.ErrorResponse}
+// @Security     Bearer
+// @Router       /users/{id}/ban [delete]
+func (h *UserHandler) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Error(w, h.log, errors.NewBadRequestError("Некорректный ID", err))
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+
+	if err = h.userUseCase.UnbanUser(r.Context(), id, adminID); err != nil {
+		response.Error(w, h.log, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// parseTimeParam разбирает параметр запроса в формате RFC3339 или YYYY-MM-DD; пустая строка — нет значения
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// clientIP возвращает IP-адрес клиента без порта. RemoteAddr содержит адрес соединения
+// или проверенный middleware.RealIP адрес от доверенного прокси
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return host
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"manga-reader2/internal/common/errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID используется для вычисления Sec-WebSocket-Accept (RFC 6455)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Коды операций кадров WebSocket
+const (
+	wsOpText  byte = 0x1
+	wsOpClose byte = 0x8
+	wsOpPing  byte = 0x9
+	wsOpPong  byte = 0xA
+)
+
+// wsMaxFramePayload ограничивает размер входящего кадра: клиент потока присылает только служебные кадры
+const wsMaxFramePayload = 4096
+
+// wsCloseGoingAway код закрытия при остановке сервера или разрыве подписки
+const wsCloseGoingAway = 1001
+
+// wsConn минимальное серверное WebSocket-соединение: отправка текстовых кадров и обработка служебных.
+// Запись защищена мьютексом, чтобы ответы на ping из читающей горутины не перемешивались с событиями
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	mu   sync.Mutex
+}
+
+// isWebSocketUpgrade проверяет, запрошен ли переход на WebSocket
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, value := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateWebSocketHandshake проверяет заголовки запроса на переход до захвата соединения,
+// чтобы ошибку можно было вернуть обычным HTTP-ответом
+func validateWebSocketHandshake(r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return errors.NewBadRequestError("Переход на WebSocket возможен только для GET", nil)
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return errors.NewBadRequestError("Поддерживается только WebSocket версии 13", nil)
+	}
+	if r.Header.Get("Sec-WebSocket-Key") == "" {
+		return errors.NewBadRequestError("Отсутствует заголовок Sec-WebSocket-Key", nil)
+	}
+	return nil
+}
+
+// acceptWebSocket завершает рукопожатие WebSocket на захваченном соединении.
+// При ошибке соединение закрывается
+func acceptWebSocket(conn net.Conn, rw *bufio.ReadWriter, key string) (*wsConn, error) {
+	// Сбрасываем таймауты HTTP-сервера: дальше соединение живет по своим правилам
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	accept := base64.StdEncoding.EncodeToString(hash[:])
+
+	err := conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err == nil {
+		_, err = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + accept + "\r\n\r\n")
+	}
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// WriteFrame отправляет нефрагментированный кадр. Кадры сервера не маскируются
+func (c *wsConn) WriteFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch {
+	case len(payload) < 126:
+		header[1] = byte(len(payload))
+	case len(payload) <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(len(payload)))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(len(payload)))
+	}
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// Close отправляет кадр закрытия с указанным кодом и закрывает соединение
+func (c *wsConn) Close(code uint16) {
+	_ = c.WriteFrame(wsOpClose, binary.BigEndian.AppendUint16(nil, code))
+	c.conn.Close()
+}
+
+// ReadLoop читает кадры клиента до ошибки или кадра закрытия: отвечает на ping и подтверждает закрытие.
+// Соединение считается оборванным, если от клиента нет ни одного кадра (в том числе pong) дольше idleTimeout
+func (c *wsConn) ReadLoop(idleTimeout time.Duration) {
+	for {
+		if err := c.conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+			return
+		}
+
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err = c.WriteFrame(wsOpPong, payload); err != nil {
+				return
+			}
+		case wsOpClose:
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			_ = c.WriteFrame(wsOpClose, payload)
+			return
+		}
+	}
+}
+
+// readFrame читает один кадр клиента и снимает маску с данных
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxFramePayload {
+		return 0, nil, errors.NewBadRequestError("Слишком большой кадр WebSocket", nil)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
@@ -5,7 +5,9 @@ import (
 	"manga-reader2/internal/api/response"
 	"manga-reader2/internal/common/errors"
 	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
 	"manga-reader2/internal/infrastructure/auth"
+	"manga-reader2/internal/usecase"
 	"net/http"
 	"strings"
 )
@@ -20,12 +22,42 @@ const (
 	UserRoleKey ContextKey = "user_role"
 	// UsernameKey ключ для имени пользователя в контексте
 	UsernameKey ContextKey = "username"
+	// UserPermissionsKey ключ для разрешений роли пользователя в контексте
+	UserPermissionsKey ContextKey = "user_permissions"
+	// APIKeyScopesKey ключ для областей действия API ключа в контексте (только при входе по ключу)
+	APIKeyScopesKey ContextKey = "api_key_scopes"
 )
 
-// Authentication middleware для проверки JWT токена
-func Authentication(jwtService *auth.JWTService, log logger.Logger) func(next http.Handler) http.Handler {
+// Authentication middleware для проверки JWT токена или персонального API ключа из заголовка X-API-Key.
+// Запросы удаленных и заблокированных пользователей отклоняются, даже если токен еще действителен
+func Authentication(
+	jwtService *auth.JWTService,
+	apiKeyUseCase usecase.APIKeyUseCase,
+	userUseCase usecase.UserUseCase,
+	log logger.Logger,
+) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+				user, key, err := apiKeyUseCase.Authenticate(r.Context(), apiKey)
+				if err != nil {
+					response.Error(w, log, err)
+					return
+				}
+
+				actor, err := userUseCase.CheckAccess(r.Context(), user.ID)
+				if err != nil {
+					response.Error(w, log, err)
+					return
+				}
+
+				ctx := withActor(r.Context(), actor, user.Username)
+				ctx = context.WithValue(ctx, APIKeyScopesKey, key.Scopes)
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
 				response.Error(w, log, errors.NewUnauthorizedError("Отсутствует заголовок Authorization", nil))
@@ -44,15 +76,66 @@ func Authentication(jwtService *auth.JWTService, log logger.Logger) func(next ht
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
-			ctx = context.WithValue(ctx, UsernameKey, claims.Username)
+			// Роль и разрешения в токене могли устареть, поэтому берутся текущие
+			actor, err := userUseCase.CheckAccess(r.Context(), claims.UserID)
+			if err != nil {
+				response.Error(w, log, err)
+				return
+			}
+
+			ctx := withActor(r.Context(), actor, claims.Username)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// withActor сохраняет пользователя, его роль и разрешения в контексте запроса
+func withActor(ctx context.Context, actor *entity.Actor, username string) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, actor.UserID)
+	ctx = context.WithValue(ctx, UserRoleKey, actor.Role)
+	ctx = context.WithValue(ctx, UsernameKey, username)
+	ctx = context.WithValue(ctx, UserPermissionsKey, actor.Permissions)
+	return usecase.WithActor(ctx, actor)
+}
+
+// OptionalAuthentication middleware для публичных маршрутов: без учетных данных запрос проходит анонимно,
+// а переданные токен или API ключ проверяются так же, как в Authentication
+func OptionalAuthentication(
+	jwtService *auth.JWTService,
+	apiKeyUseCase usecase.APIKeyUseCase,
+	userUseCase usecase.UserUseCase,
+	log logger.Logger,
+) func(next http.Handler) http.Handler {
+	authenticate := Authentication(jwtService, apiKeyUseCase, userUseCase, log)
+
+	return func(next http.Handler) http.Handler {
+		authenticated := authenticate(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" && r.Header.Get("X-API-Key") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TokenFromQuery middleware переносит токен из параметра access_token в заголовок Authorization.
+// Нужен для потоковых маршрутов: браузерные EventSource и WebSocket не умеют передавать заголовки
+func TokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("access_token")
+		if token != "" && r.Header.Get("Authorization") == "" && r.Header.Get("X-API-Key") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // RequireRole middleware для проверки роли пользователя
 func RequireRole(roles ...string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -80,3 +163,60 @@ func RequireRole(roles ...string) func(next http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequirePermission middleware для проверки разрешений роли пользователя.
+// Запрос проходит, если у роли есть хотя бы одно из указанных разрешений
+func RequirePermission(permissions ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userPermissions, ok := r.Context().Value(UserPermissionsKey).([]string)
+			if !ok {
+				response.Unauthorized(w, nil, "Требуется авторизация")
+				return
+			}
+
+			for _, permission := range permissions {
+				for _, userPermission := range userPermissions {
+					if permission == userPermission {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+
+			response.Forbidden(w, nil, "Отказано в доступе")
+		})
+	}
+}
+
+// RequireScope middleware для проверки областей действия API ключа.
+// Запросы с JWT токеном проходят без ограничений; запрос по API ключу проходит,
+// если ключу разрешена хотя бы одна из указанных областей. Без аргументов запрещает вход по ключу.
+func RequireScope(scopes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyScopes, isAPIKey := r.Context().Value(APIKeyScopesKey).([]string)
+			if !isAPIKey {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, scope := range scopes {
+				for _, keyScope := range keyScopes {
+					if scope == keyScope {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+
+			response.Forbidden(w, nil, "Область действия API ключа не позволяет выполнить запрос")
+		})
+	}
+}
+
+// GetUserID возвращает ID аутентифицированного пользователя из контекста
+func GetUserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(UserIDKey).(int64)
+	return userID, ok
+}
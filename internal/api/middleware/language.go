@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"manga-reader2/internal/usecase"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxLanguages ограничивает число языков, принимаемых из Accept-Language
+const maxLanguages = 10
+
+// Language middleware определяет языки клиента для выбора переводов.
+// Параметр ?lang= имеет приоритет над заголовком Accept-Language
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var languages []string
+		if lang := usecase.NormalizeLanguage(r.URL.Query().Get("lang")); lang != "" {
+			languages = append(languages, lang)
+		}
+		languages = append(languages, parseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+
+		if len(languages) > 0 {
+			r = r.WithContext(usecase.WithLanguages(r.Context(), languages))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseAcceptLanguage возвращает языки из заголовка Accept-Language по убыванию веса q.
+// Шаблон * и языки с весом 0 пропускаются
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		language string
+		q        float64
+	}
+
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		language := usecase.NormalizeLanguage(tag)
+		if language == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		items = append(items, weighted{language: language, q: q})
+		if len(items) == maxLanguages {
+			break
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].q > items[j].q })
+
+	languages := make([]string, len(items))
+	for i, item := range items {
+		languages[i] = item.language
+	}
+	return languages
+}
@@ -89,3 +89,9 @@ func (w *WrapResponseWriter) Write(b []byte) (int, error) {
 	w.bytesWritten += n
 	return n, err
 }
+
+// Unwrap возвращает исходный http.ResponseWriter, чтобы http.ResponseController
+// мог использовать Flush и Hijack для потоковых ответов
+func (w *WrapResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP middleware подставляет в RemoteAddr адрес клиента из X-Forwarded-For или X-Real-IP.
+// Заголовкам доверяет только если запрос пришел от прокси из trustedProxies (IP или CIDR):
+// иначе клиент мог бы подставить любой адрес и обойти ограничения по IP.
+// Без доверенных прокси RemoteAddr не изменяется
+func RealIP(trustedProxies []string) (func(next http.Handler) http.Handler, error) {
+	trusted, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedClientIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// parseTrustedProxies разбирает список доверенных прокси; одиночный IP считается сетью из одного адреса
+func parseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("некорректный адрес доверенного прокси %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная сеть доверенных прокси %q: %w", value, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// forwardedClientIP возвращает адрес клиента из заголовков прокси или пустую строку, если им нельзя доверять.
+// X-Forwarded-For просматривается справа налево: первый адрес не из доверенных прокси и есть клиент,
+// адреса левее него мог подставить сам клиент
+func forwardedClientIP(r *http.Request, trusted []*net.IPNet) string {
+	if len(trusted) == 0 {
+		return ""
+	}
+
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !isTrustedProxy(net.ParseIP(peer), trusted) {
+		return ""
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				return ""
+			}
+			if !isTrustedProxy(ip, trusted) || i == 0 {
+				return ip.String()
+			}
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// isTrustedProxy проверяет, входит ли адрес в одну из доверенных сетей
+func isTrustedProxy(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
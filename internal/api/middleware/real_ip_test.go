@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "без доверенных прокси заголовки игнорируются",
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1", "X-Real-IP": "2.2.2.2"},
+			want:       "203.0.113.7:5000",
+		},
+		{
+			name:       "запрос не от доверенного прокси",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1"},
+			want:       "203.0.113.7:5000",
+		},
+		{
+			name:       "клиент за доверенным прокси",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.4"},
+			want:       "198.51.100.4",
+		},
+		{
+			name:       "адрес, подставленный клиентом левее, не используется",
+			trusted:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.4"},
+			want:       "198.51.100.4",
+		},
+		{
+			name:       "цепочка доверенных прокси пропускается",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.4, 10.0.0.9"},
+			want:       "198.51.100.4",
+		},
+		{
+			name:       "X-Real-IP от доверенного прокси",
+			trusted:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.4"},
+			want:       "198.51.100.4",
+		},
+		{
+			name:       "некорректный X-Forwarded-For",
+			trusted:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:5000",
+			headers:    map[string]string{"X-Forwarded-For": "not-an-ip"},
+			want:       "10.0.0.2:5000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			realIP, err := RealIP(tt.trusted)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			var got string
+			handler := realIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("RemoteAddr %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealIPInvalidProxy(t *testing.T) {
+	if _, err := RealIP([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("ожидалась ошибка для некорректной сети")
+	}
+	if _, err := RealIP([]string{"proxy.local"}); err == nil {
+		t.Error("ожидалась ошибка для некорректного адреса")
+	}
+}
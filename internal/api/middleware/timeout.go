@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Timeout middleware ограничивает время обработки запроса.
+// Долгоживущие потоковые маршруты из streamPaths обслуживаются без ограничения
+func Timeout(timeout time.Duration, streamPaths ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := middleware.Timeout(timeout)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, path := range streamPaths {
+				if r.URL.Path == path {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
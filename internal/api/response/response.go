@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 
 	stderrors "errors" // Стандартный пакет errors с псевдонимом
@@ -68,6 +69,11 @@ func Error(w http.ResponseWriter, log logger.Logger, err error) {
 	var statusCode int
 	var errorResp interface{}
 
+	// Middleware без логгера (RequireRole, RequireScope) передают nil
+	if log == nil {
+		log = logger.NewLoggerWithOutput("error", io.Discard)
+	}
+
 	var appErr *errors.AppError
 	if stderrors.As(err, &appErr) {
 		statusCode = appErr.StatusCode
@@ -1,12 +1,13 @@
 package router
 
 import (
+	"context"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
 	"manga-reader2/internal/api/handler"
 	customMiddleware "manga-reader2/internal/api/middleware"
 	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
 	"manga-reader2/internal/domain/repository"
 	"manga-reader2/internal/infrastructure/auth"
 	"manga-reader2/internal/infrastructure/db"
@@ -14,39 +15,134 @@ import (
 	"manga-reader2/internal/infrastructure/repository/redis"
 	"manga-reader2/internal/usecase"
 	"net/http"
+	"sync"
 )
 
-// SetupRoutes настраивает все маршруты приложения
+// SetupRoutes настраивает все маршруты приложения и запускает фоновые обработчики,
+// которые работают до отмены ctx. Отмена streamsCtx закрывает потоковые соединения
+// и должна происходить в начале остановки сервера, иначе они задержат его завершение.
+// Возвращает группу фоновых обработчиков: до закрытия БД нужно дождаться, пока они допишут накопленные данные
 func SetupRoutes(
+	ctx context.Context,
+	streamsCtx context.Context,
 	r *chi.Mux,
 	postgresDB *db.PostgresDB,
 	redisClient *db.RedisClient,
 	jwtService *auth.JWTService,
+	oidcProviders []*auth.OIDCProvider,
 	log logger.Logger,
-) {
+) *sync.WaitGroup {
 	mangaRepo := postgres.NewMangaRepository(postgresDB.GetDB(), log)
 	chapterRepo := postgres.NewChapterRepository(postgresDB.GetDB(), log)
 	pageRepo := postgres.NewPageRepository(postgresDB.GetDB(), log)
 	userRepo := postgres.NewUserRepository(postgresDB.GetDB(), log)
+	userIdentityRepo := postgres.NewUserIdentityRepository(postgresDB.GetDB(), log)
+	apiKeyRepo := postgres.NewAPIKeyRepository(postgresDB.GetDB(), log)
+	roleRepo := postgres.NewRoleRepository(postgresDB.GetDB(), log)
+	bookmarkRepo := postgres.NewBookmarkRepository(postgresDB.GetDB(), log)
+	historyRepo := postgres.NewReadingHistoryRepository(postgresDB.GetDB(), log)
+	progressRepo := postgres.NewReadingProgressRepository(postgresDB.GetDB(), log)
+	libraryRepo := postgres.NewLibraryRepository(postgresDB.GetDB(), log)
+	chapterReadRepo := postgres.NewChapterReadRepository(postgresDB.GetDB(), log)
+	followRepo := postgres.NewFollowRepository(postgresDB.GetDB(), log)
+	notificationRepo := postgres.NewNotificationRepository(postgresDB.GetDB(), log)
+	commentRepo := postgres.NewCommentRepository(postgresDB.GetDB(), log)
+	moderationRepo := postgres.NewModerationRepository(postgresDB.GetDB(), log)
+	ratingRepo := postgres.NewRatingRepository(postgresDB.GetDB(), log)
+	reviewRepo := postgres.NewReviewRepository(postgresDB.GetDB(), log)
+	recommendationRepo := postgres.NewRecommendationRepository(postgresDB.GetDB(), log)
+	relationRepo := postgres.NewMangaRelationRepository(postgresDB.GetDB(), log)
+	creatorRepo := postgres.NewCreatorRepository(postgresDB.GetDB(), log)
+	groupRepo := postgres.NewScanlationGroupRepository(postgresDB.GetDB(), log)
 
 	cacheRepo := redis.NewCacheRepository(redisClient, log)
 	analyticsRepo := redis.NewAnalyticsRepository(redisClient, log)
+	streamRepo := redis.NewStreamRepository(redisClient, log)
 
-	mangaUseCase := usecase.NewMangaUseCase(mangaRepo, cacheRepo, analyticsRepo, log)
-	chapterUseCase := usecase.NewChapterUseCase(chapterRepo, mangaRepo, cacheRepo, analyticsRepo, log)
-	pageUseCase := usecase.NewPageUseCase(pageRepo, chapterRepo, cacheRepo, analyticsRepo, log)
-	userUseCase := usecase.NewUserUseCase(userRepo, jwtService, log)
+	historyUseCase := usecase.NewReadingHistoryUseCase(historyRepo, log)
+	streamUseCase := usecase.NewStreamUseCase(streamRepo, log)
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, streamUseCase, log)
+	mangaUseCase := usecase.NewMangaUseCase(mangaRepo, chapterRepo, pageRepo, relationRepo, creatorRepo, userRepo, cacheRepo, analyticsRepo, log)
+	chapterUseCase := usecase.NewChapterUseCase(chapterRepo, mangaRepo, pageRepo, cacheRepo, analyticsRepo, historyUseCase, notificationUseCase, log)
+	pageUseCase := usecase.NewPageUseCase(pageRepo, chapterRepo, mangaRepo, cacheRepo, analyticsRepo, historyUseCase, jwtService, log)
+	readerUseCase := usecase.NewReaderUseCase(chapterRepo, mangaRepo, pageRepo, cacheRepo, analyticsRepo, historyUseCase, jwtService, log)
+	userUseCase := usecase.NewUserUseCase(userRepo, roleRepo, cacheRepo, jwtService, log)
+	oidcUseCase := usecase.NewOIDCUseCase(oidcProviders, userRepo, userIdentityRepo, roleRepo, cacheRepo, jwtService, log)
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, userRepo, roleRepo, cacheRepo, log)
+	roleUseCase := usecase.NewRoleUseCase(roleRepo, mangaRepo, userRepo, cacheRepo, log)
+	bookmarkUseCase := usecase.NewBookmarkUseCase(bookmarkRepo, mangaRepo, chapterRepo, log)
+	progressUseCase := usecase.NewReadingProgressUseCase(progressRepo, chapterRepo, mangaRepo, historyUseCase, log)
+	libraryUseCase := usecase.NewLibraryUseCase(libraryRepo, mangaRepo, log)
+	chapterReadUseCase := usecase.NewChapterReadUseCase(chapterReadRepo, mangaRepo, chapterRepo, log)
+	followUseCase := usecase.NewFollowUseCase(followRepo, mangaRepo, log)
+	commentUseCase := usecase.NewCommentUseCase(commentRepo, moderationRepo, mangaRepo, chapterRepo, cacheRepo, log)
+	moderationUseCase := usecase.NewModerationUseCase(moderationRepo, commentRepo, userRepo, roleRepo, cacheRepo, log)
+	ratingUseCase := usecase.NewRatingUseCase(ratingRepo, cacheRepo, log)
+	reviewUseCase := usecase.NewReviewUseCase(reviewRepo, mangaRepo, cacheRepo, log)
 	analyticsUseCase := usecase.NewAnalyticsUseCase(analyticsRepo, mangaRepo, chapterRepo, log)
+	recommendationUseCase := usecase.NewRecommendationUseCase(recommendationRepo, analyticsRepo, cacheRepo, log)
+	creatorUseCase := usecase.NewCreatorUseCase(creatorRepo, cacheRepo, log)
+	groupUseCase := usecase.NewScanlationGroupUseCase(groupRepo, cacheRepo, log)
+
+	workers := &sync.WaitGroup{}
+
+	// Фоновая запись истории чтения
+	startWorker(workers, ctx, historyUseCase.Run)
+
+	// Фоновая рассылка уведомлений подписчикам
+	startWorker(workers, ctx, notificationUseCase.Run)
+
+	// Периодический пересчет байесовских оценок манги
+	startWorker(workers, ctx, ratingUseCase.Run)
+
+	// Периодический пересчет похожести манги для рекомендаций
+	startWorker(workers, ctx, recommendationUseCase.Run)
 
-	mangaHandler := handler.NewMangaHandler(mangaUseCase, log)
+	// Публикация запланированных глав
+	startWorker(workers, ctx, chapterUseCase.Run)
+
+	// Раздача событий реального времени; останавливается в начале завершения сервера, чтобы закрыть потоковые соединения
+	startWorker(workers, streamsCtx, streamUseCase.Run)
+
+	mangaHandler := handler.NewMangaHandler(mangaUseCase, chapterReadUseCase, log)
 	chapterHandler := handler.NewChapterHandler(chapterUseCase, log)
 	pageHandler := handler.NewPageHandler(pageUseCase, log)
-	userHandler := handler.NewUserHandler(userUseCase, log)
+	readerHandler := handler.NewReaderHandler(readerUseCase, log)
+	userHandler := handler.NewUserHandler(userUseCase, bookmarkUseCase, historyUseCase, log)
+	oidcHandler := handler.NewOIDCHandler(oidcUseCase, log)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUseCase, log)
+	roleHandler := handler.NewRoleHandler(roleUseCase, log)
+	progressHandler := handler.NewReadingProgressHandler(progressUseCase, log)
+	libraryHandler := handler.NewLibraryHandler(libraryUseCase, log)
+	chapterReadHandler := handler.NewChapterReadHandler(chapterReadUseCase, log)
+	followHandler := handler.NewFollowHandler(followUseCase, log)
+	notificationHandler := handler.NewNotificationHandler(notificationUseCase, log)
+	streamHandler := handler.NewStreamHandler(streamUseCase, log)
+	commentHandler := handler.NewCommentHandler(commentUseCase, log)
+	moderationHandler := handler.NewModerationHandler(moderationUseCase, log)
+	ratingHandler := handler.NewRatingHandler(ratingUseCase, log)
+	reviewHandler := handler.NewReviewHandler(reviewUseCase, log)
 	analyticsHandler := handler.NewAnalyticsHandler(analyticsUseCase, log)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationUseCase, log)
+	creatorHandler := handler.NewCreatorHandler(creatorUseCase, log)
+	groupHandler := handler.NewScanlationGroupHandler(groupUseCase, log)
+
+	authMiddleware := customMiddleware.Authentication(jwtService, apiKeyUseCase, userUseCase, log)
+	optionalAuthMiddleware := customMiddleware.OptionalAuthentication(jwtService, apiKeyUseCase, userUseCase, log)
 
-	authMiddleware := customMiddleware.Authentication(jwtService, log)
+	// Проверка разрешений роли пользователя
+	mangaWrite := customMiddleware.RequirePermission(entity.PermissionMangaWrite)
+	chapterUpload := customMiddleware.RequirePermission(entity.PermissionChapterUpload)
+	userManage := customMiddleware.RequirePermission(entity.PermissionUserManage)
+	analyticsManage := customMiddleware.RequirePermission(entity.PermissionAnalyticsManage)
+	commentModerate := customMiddleware.RequirePermission(entity.PermissionCommentModerate)
 
-	adminMiddleware := customMiddleware.RequireRole("admin")
+	// Ограничения для запросов по персональным API ключам
+	readScope := customMiddleware.RequireScope(entity.APIKeyScopeRead)
+	writeScope := customMiddleware.RequireScope(entity.APIKeyScopeWrite)
+	uploadScope := customMiddleware.RequireScope(entity.APIKeyScopeUpload, entity.APIKeyScopeWrite)
+	adminScope := customMiddleware.RequireScope(entity.APIKeyScopeAdmin)
+	noAPIKey := customMiddleware.RequireScope()
 
 	// Общие маршруты
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -62,33 +158,96 @@ func SetupRoutes(
 			r.Post("/login", userHandler.Login)
 			r.Post("/refresh", userHandler.RefreshToken)
 
+			// Вход через внешних OpenID Connect провайдеров
+			r.Get("/oidc/{provider}/login", oidcHandler.Login)
+			r.Get("/oidc/{provider}/callback", oidcHandler.Callback)
+
 			// Маршруты, требующие аутентификации
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware)
 
-				r.Get("/me", userHandler.GetProfile)
-				r.Put("/me", userHandler.UpdateProfile)
+				r.With(readScope).Get("/me", userHandler.GetProfile)
+				r.With(writeScope).Put("/me", userHandler.UpdateProfile)
 				r.Post("/logout", userHandler.Logout)
 
+				// Привязка внешнего провайдера доступна только из пользовательской сессии
+				r.With(noAPIKey).Post("/oidc/{provider}/link", oidcHandler.Link)
+
+				// Предпочитаемые языки глав
+				r.With(readScope).Get("/me/languages", userHandler.GetLanguages)
+				r.With(writeScope).Put("/me/languages", userHandler.SetLanguages)
+
+				// Персональные рекомендации
+				r.With(readScope).Get("/me/recommendations", recommendationHandler.List)
+
 				// Закладки
-				r.Get("/bookmarks", userHandler.GetBookmarks)
-				r.Post("/bookmarks", userHandler.AddBookmark)
-				r.Delete("/bookmarks/{mangaID}", userHandler.RemoveBookmark)
+				r.With(readScope).Get("/bookmarks", userHandler.GetBookmarks)
+				r.With(writeScope).Post("/bookmarks", userHandler.AddBookmark)
+				r.With(writeScope).Delete("/bookmarks/{mangaID}", userHandler.RemoveBookmark)
 
 				// История чтения
-				r.Get("/history", userHandler.GetReadingHistory)
-				r.Delete("/history/{id}", userHandler.RemoveFromHistory)
+				r.With(readScope).Get("/history", userHandler.GetReadingHistory)
+				r.With(readScope).Get("/history/continue", userHandler.ContinueReading)
+				r.With(writeScope).Delete("/history", userHandler.ClearHistory)
+				r.With(writeScope).Delete("/history/{id}", userHandler.RemoveFromHistory)
+
+				// Синхронизация прогресса чтения между устройствами
+				r.With(readScope).Get("/progress", progressHandler.List)
+				r.With(writeScope).Post("/progress/sync", progressHandler.Sync)
+				r.With(readScope).Get("/progress/{mangaID}", progressHandler.Get)
+				r.With(writeScope).Put("/progress/{mangaID}", progressHandler.Update)
+
+				// Счетчики непрочитанных глав
+				r.With(readScope).Get("/unread-counts", chapterReadHandler.UnreadCounts)
+
+				// Подписки и уведомления
+				r.With(readScope).Get("/follows", followHandler.List)
+				r.With(readScope).Get("/notifications", notificationHandler.List)
+				r.With(readScope).Get("/notifications/unread-count", notificationHandler.UnreadCount)
+				r.With(writeScope).Post("/notifications/read-all", notificationHandler.MarkAllRead)
+				r.With(writeScope).Put("/notifications/{id}/read", notificationHandler.MarkRead)
+
+				// Библиотека и пользовательские полки
+				r.Route("/library", func(r chi.Router) {
+					r.With(readScope).Get("/", libraryHandler.List)
+					r.With(readScope).Get("/stats", libraryHandler.Stats)
+					r.With(readScope).Get("/export", libraryHandler.Export)
+					r.With(writeScope).Post("/import", libraryHandler.Import)
+
+					r.With(readScope).Get("/shelves", libraryHandler.ListShelves)
+					r.With(writeScope).Post("/shelves", libraryHandler.CreateShelf)
+					r.With(writeScope).Put("/shelves/{id}", libraryHandler.RenameShelf)
+					r.With(writeScope).Delete("/shelves/{id}", libraryHandler.DeleteShelf)
+					r.With(writeScope).Put("/shelves/{id}/manga/{mangaID}", libraryHandler.AddToShelf)
+					r.With(writeScope).Delete("/shelves/{id}/manga/{mangaID}", libraryHandler.RemoveFromShelf)
+
+					r.With(writeScope).Put("/{mangaID}", libraryHandler.Set)
+					r.With(writeScope).Delete("/{mangaID}", libraryHandler.Remove)
+				})
+
+				// Персональные API ключи управляются только по JWT токену
+				r.Group(func(r chi.Router) {
+					r.Use(noAPIKey)
+
+					r.Get("/me/api-keys", apiKeyHandler.List)
+					r.Post("/me/api-keys", apiKeyHandler.Create)
+					r.Delete("/me/api-keys/{id}", apiKeyHandler.Revoke)
+				})
 			})
 
 			// Маршруты для администраторов
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware)
-				r.Use(adminMiddleware)
+				r.Use(userManage)
+				r.Use(adminScope)
 
 				r.Get("/", userHandler.ListUsers)
 				r.Get("/{id}", userHandler.GetUser)
 				r.Put("/{id}", userHandler.UpdateUser)
 				r.Delete("/{id}", userHandler.DeleteUser)
+				r.Post("/{id}/unlock", userHandler.UnlockUser)
+				r.Post("/{id}/ban", userHandler.BanUser)
+				r.Delete("/{id}/ban", userHandler.UnbanUser)
 			})
 		})
 
@@ -97,52 +256,215 @@ func SetupRoutes(
 			r.Get("/", mangaHandler.List)
 			r.Get("/popular", mangaHandler.GetPopular)
 			r.Get("/{id}", mangaHandler.GetByID)
-			r.Get("/{id}/chapters", mangaHandler.GetChapters)
+			// Аутентифицированный пользователь получает отметки о прочтении глав
+			r.With(optionalAuthMiddleware).Get("/{id}/chapters", mangaHandler.GetChapters)
+			r.With(optionalAuthMiddleware).Get("/{id}/volumes", mangaHandler.GetVolumes)
+
+			// Отметки о прочтении диапазона глав
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(writeScope)
+
+				r.Post("/{id}/chapters/read", chapterReadHandler.MarkRangeRead)
+				r.Post("/{id}/chapters/unread", chapterReadHandler.MarkRangeUnread)
+			})
+
+			// Подписка на новые главы
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+
+				r.With(readScope).Get("/{id}/follow", followHandler.Status)
+				r.With(writeScope).Put("/{id}/follow", followHandler.Follow)
+				r.With(writeScope).Delete("/{id}/follow", followHandler.Unfollow)
+			})
+
+			// Комментарии к манге; аутентифицированный пользователь получает свои оценки
+			r.With(optionalAuthMiddleware).Get("/{id}/comments", commentHandler.ListMangaComments)
+			r.With(authMiddleware, writeScope).Post("/{id}/comments", commentHandler.CreateMangaComment)
+
+			// Оценки и рецензии
+			r.With(optionalAuthMiddleware).Get("/{id}/rating", ratingHandler.Summary)
+			r.With(authMiddleware, writeScope).Put("/{id}/rating", ratingHandler.Rate)
+			r.With(authMiddleware, writeScope).Delete("/{id}/rating", ratingHandler.Remove)
+			r.With(optionalAuthMiddleware).Get("/{id}/reviews", reviewHandler.ListMangaReviews)
+			r.With(authMiddleware, writeScope).Post("/{id}/reviews", reviewHandler.Create)
 
 			// Маршруты для администраторов
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware)
-				r.Use(adminMiddleware)
+				r.Use(mangaWrite)
+				r.Use(writeScope)
 
 				r.Post("/", mangaHandler.Create)
 				r.Put("/{id}", mangaHandler.Update)
 				r.Delete("/{id}", mangaHandler.Delete)
+
+				// Связи с другой мангой
+				r.Put("/{id}/relations", mangaHandler.SetRelation)
+				r.Delete("/{id}/relations/{relatedID}", mangaHandler.RemoveRelation)
+
+				// Названия и обложки томов
+				r.Put("/{id}/volumes/{number}", mangaHandler.SetVolume)
+			})
+
+			// Назначение загрузчиков манги
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(userManage)
+				r.Use(adminScope)
+
+				r.Post("/{id}/uploaders", roleHandler.AssignUploader)
+				r.Delete("/{id}/uploaders/{userID}", roleHandler.UnassignUploader)
 			})
 		})
 
 		// Маршруты для глав
 		r.Route("/chapters", func(r chi.Router) {
-			r.Get("/{id}", chapterHandler.GetByID)
-			r.Get("/{id}/pages", chapterHandler.GetPages)
+			// Чтение аутентифицированным пользователем попадает в его историю
+			r.With(optionalAuthMiddleware).Get("/{id}", chapterHandler.GetByID)
+			// Неопубликованные главы и главы в раннем доступе читают только пользователи с правами
+			r.With(optionalAuthMiddleware).Get("/{id}/pages", chapterHandler.GetPages)
+			// Все данные для открытия главы в читалке одним запросом
+			r.With(optionalAuthMiddleware).Get("/{id}/reader", readerHandler.Get)
+
+			// Отметка о прочтении главы
+			r.With(authMiddleware, writeScope).Put("/{id}/read", chapterReadHandler.MarkRead)
+			r.With(authMiddleware, writeScope).Delete("/{id}/read", chapterReadHandler.MarkUnread)
+
+			// Комментарии к главе
+			r.With(optionalAuthMiddleware).Get("/{id}/comments", commentHandler.ListChapterComments)
+			r.With(authMiddleware, writeScope).Post("/{id}/comments", commentHandler.CreateChapterComment)
 
 			// Маршруты для администраторов
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware)
-				r.Use(adminMiddleware)
+				r.Use(chapterUpload)
 
-				r.Post("/", chapterHandler.Create)
-				r.Put("/{id}", chapterHandler.Update)
-				r.Delete("/{id}", chapterHandler.Delete)
+				r.With(uploadScope).Post("/", chapterHandler.Create)
+				r.With(writeScope).Put("/{id}", chapterHandler.Update)
+				r.With(writeScope).Delete("/{id}", chapterHandler.Delete)
+
+				// Перенумерация, вставка и замена страниц выполняются одной транзакцией
+				r.With(writeScope).Put("/{id}/pages/order", pageHandler.Reorder)
+				r.With(uploadScope).Post("/{id}/pages/insert", pageHandler.Insert)
+				r.With(uploadScope).Put("/{id}/pages", pageHandler.ReplaceAll)
 			})
 		})
 
 		// Маршруты для страниц
 		r.Route("/pages", func(r chi.Router) {
-			r.Get("/{id}", pageHandler.GetByID)
-			r.Get("/{id}/image", pageHandler.ServeImage)
+			r.With(optionalAuthMiddleware).Get("/{id}", pageHandler.GetByID)
+			// Изображения загружаются тегом <img>, поэтому для глав в раннем доступе читалка выдает подписанные ссылки
+			r.With(optionalAuthMiddleware).Get("/{id}/image", pageHandler.ServeImage)
 
 			// Маршруты для администраторов
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware)
-				r.Use(adminMiddleware)
+				r.Use(chapterUpload)
 
-				r.Post("/", pageHandler.Create)
-				r.Post("/upload", pageHandler.UploadImage)
-				r.Put("/{id}", pageHandler.Update)
-				r.Delete("/{id}", pageHandler.Delete)
+				r.With(uploadScope).Post("/", pageHandler.Create)
+				r.With(uploadScope).Post("/upload", pageHandler.UploadImage)
+				r.With(writeScope).Put("/{id}", pageHandler.Update)
+				r.With(writeScope).Delete("/{id}", pageHandler.Delete)
 			})
 		})
 
+		// Комментарии и ветки ответов
+		r.Route("/comments", func(r chi.Router) {
+			// Модераторы видят текст скрытых комментариев
+			r.Group(func(r chi.Router) {
+				r.Use(optionalAuthMiddleware)
+
+				r.Get("/{id}", commentHandler.GetByID)
+				r.Get("/{id}/replies", commentHandler.ListReplies)
+				r.Get("/{id}/history", commentHandler.History)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(writeScope)
+
+				r.Post("/{id}/replies", commentHandler.Reply)
+				r.Put("/{id}", commentHandler.Update)
+				r.Delete("/{id}", commentHandler.Delete)
+				r.Put("/{id}/vote", commentHandler.Vote)
+				r.Post("/{id}/reports", commentHandler.Report)
+			})
+		})
+
+		// Рецензии
+		r.Route("/reviews", func(r chi.Router) {
+			r.With(optionalAuthMiddleware).Get("/{id}", reviewHandler.GetByID)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(writeScope)
+
+				r.Put("/{id}", reviewHandler.Update)
+				r.Delete("/{id}", reviewHandler.Delete)
+				r.Put("/{id}/vote", reviewHandler.Vote)
+			})
+		})
+
+		// Авторы и художники
+		r.Route("/creators", func(r chi.Router) {
+			r.Get("/", creatorHandler.List)
+			r.Get("/{id}", creatorHandler.Get)
+
+			// Маршруты для администраторов
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(mangaWrite)
+				r.Use(writeScope)
+
+				r.Post("/", creatorHandler.Create)
+				r.Put("/{id}", creatorHandler.Update)
+				r.Post("/{id}/merge", creatorHandler.Merge)
+			})
+		})
+
+		// Группы переводчиков и их релизы
+		r.Route("/groups", func(r chi.Router) {
+			r.Get("/", groupHandler.List)
+			r.Get("/{id}", groupHandler.Get)
+			r.Get("/{id}/chapters", groupHandler.ListReleases)
+
+			// Маршруты для администраторов
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(mangaWrite)
+				r.Use(writeScope)
+
+				r.Post("/", groupHandler.Create)
+				r.Put("/{id}", groupHandler.Update)
+			})
+		})
+
+		// Модерация комментариев
+		r.Route("/moderation", func(r chi.Router) {
+			r.Use(authMiddleware)
+			r.Use(commentModerate)
+			r.Use(adminScope)
+
+			r.Get("/queue", moderationHandler.Queue)
+			r.Post("/comments/{id}/actions", moderationHandler.Act)
+			r.Get("/log", moderationHandler.Log)
+		})
+
+		// Управление ролями и разрешениями
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(authMiddleware)
+			r.Use(userManage)
+			r.Use(adminScope)
+
+			r.Get("/", roleHandler.List)
+			r.Put("/{name}/permissions", roleHandler.UpdatePermissions)
+		})
+
+		// Поток событий реального времени (SSE или WebSocket).
+		// Токен можно передать параметром access_token: EventSource не умеет отправлять заголовки
+		r.With(customMiddleware.TokenFromQuery, authMiddleware, readScope).Get("/stream", streamHandler.Stream)
+
 		// Маршруты для аналитики
 		r.Route("/analytics", func(r chi.Router) {
 			r.Get("/manga/top", analyticsHandler.GetTopManga)
@@ -151,7 +473,8 @@ func SetupRoutes(
 			// Маршруты для администраторов
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware)
-				r.Use(adminMiddleware)
+				r.Use(analyticsManage)
+				r.Use(adminScope)
 
 				r.Post("/reset/daily", analyticsHandler.ResetDailyStats)
 				r.Post("/reset/weekly", analyticsHandler.ResetWeeklyStats)
@@ -163,6 +486,19 @@ func SetupRoutes(
 
 	// Маршрут для Swagger UI
 	r.Get("/swagger/*", http.StripPrefix("/swagger/", http.FileServer(http.Dir("./docs/swagger"))).ServeHTTP)
+
+	return workers
+}
+
+// startWorker запускает фоновый обработчик от имени системного пользователя и учитывает его в группе до завершения
+func startWorker(workers *sync.WaitGroup, ctx context.Context, run func(ctx context.Context)) {
+	ctx = usecase.WithActor(ctx, entity.SystemActor())
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		run(ctx)
+	}()
 }
 
 // Эти функции-заглушки будут заменены на реальные реализации позже
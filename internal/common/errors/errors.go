@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ErrorCode представляет код ошибки
@@ -19,6 +20,7 @@ const (
 	ErrorCodeNotFound     ErrorCode = "NOT_FOUND"
 	ErrorCodeConflict     ErrorCode = "CONFLICT"
 	ErrorCodeValidation   ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeRateLimited  ErrorCode = "RATE_LIMITED"
 
 	// Ошибки базы данных
 	ErrorCodeDatabase ErrorCode = "DATABASE_ERROR"
@@ -29,9 +31,11 @@ const (
 	ErrorCodePageNotFound    ErrorCode = "PAGE_NOT_FOUND"
 
 	// Ошибки пользователей
-	ErrorCodeUserNotFound ErrorCode = "USER_NOT_FOUND"
-	ErrorCodeUserExists   ErrorCode = "USER_ALREADY_EXISTS"
-	ErrorCodeInvalidCreds ErrorCode = "INVALID_CREDENTIALS"
+	ErrorCodeUserNotFound  ErrorCode = "USER_NOT_FOUND"
+	ErrorCodeUserExists    ErrorCode = "USER_ALREADY_EXISTS"
+	ErrorCodeInvalidCreds  ErrorCode = "INVALID_CREDENTIALS"
+	ErrorCodeAccountLocked ErrorCode = "ACCOUNT_LOCKED"
+	ErrorCodeUserBanned    ErrorCode = "USER_BANNED"
 
 	// Ошибки JWT
 	ErrorCodeJWTInvalid ErrorCode = "JWT_INVALID"
@@ -198,6 +202,47 @@ func NewInvalidCredentialsError() *AppError {
 	}
 }
 
+// NewAccountLockedError создает ошибку "аккаунт временно заблокирован"
+func NewAccountLockedError(retryAfter time.Duration) *AppError {
+	return &AppError{
+		Code:    ErrorCodeAccountLocked,
+		Message: "Слишком много неудачных попыток входа, повторите попытку позже",
+		Details: map[string]interface{}{
+			"retry_after": int64(retryAfter.Seconds()),
+		},
+		StatusCode: http.StatusTooManyRequests,
+	}
+}
+
+// NewRateLimitError создает ошибку "слишком много запросов" с временем до следующей попытки
+func NewRateLimitError(msg string, retryAfter time.Duration) *AppError {
+	return &AppError{
+		Code:    ErrorCodeRateLimited,
+		Message: msg,
+		Details: map[string]interface{}{
+			"retry_after": int64(retryAfter.Seconds()),
+		},
+		StatusCode: http.StatusTooManyRequests,
+	}
+}
+
+// NewUserBannedError создает ошибку "пользователь заблокирован администратором"
+func NewUserBannedError(reason string, until *time.Time) *AppError {
+	details := map[string]interface{}{
+		"reason": reason,
+	}
+	if until != nil {
+		details["until"] = until.UTC().Format(time.RFC3339)
+	}
+
+	return &AppError{
+		Code:       ErrorCodeUserBanned,
+		Message:    "Пользователь заблокирован",
+		Details:    details,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
 // NewJWTInvalidError создает ошибку "недействительный JWT токен"
 func NewJWTInvalidError(err error) *AppError {
 	return &AppError{
@@ -261,5 +306,6 @@ func IsUnauthorizedError(err error) bool {
 
 // IsForbiddenError проверяет, является ли ошибка ошибкой доступа
 func IsForbiddenError(err error) bool {
-	return IsErrorCode(err, ErrorCodeForbidden)
+	return IsErrorCode(err, ErrorCodeForbidden) ||
+		IsErrorCode(err, ErrorCodeUserBanned)
 }
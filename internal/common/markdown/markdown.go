@@ -0,0 +1,131 @@
+// Package markdown преобразует пользовательский Markdown в безопасный HTML.
+//
+// Поддерживается подмножество синтаксиса для комментариев: абзацы и переносы строк, **жирный**, *курсив*,
+// ~~зачеркнутый~~, `код`, блоки кода ```, цитаты >, списки -/*, ссылки [текст](https://...)
+// и спойлеры ||текст|| или >!текст!<. Весь исходный текст экранируется до разметки,
+// поэтому HTML из ввода никогда не попадает в результат, а ссылки допускаются только http(s).
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	codeSpanPattern    = regexp.MustCompile("`([^`\n]+)`")
+	linkPattern        = regexp.MustCompile("\\[([^\\]\n]+)\\]\\((https?://[^\\s)\x00]+)\\)")
+	boldPattern        = regexp.MustCompile(`\*\*([^\n]+?)\*\*`)
+	italicPattern      = regexp.MustCompile(`\*([^*\s][^*\n]*?)\*`)
+	strikePattern      = regexp.MustCompile(`~~([^\n]+?)~~`)
+	spoilerPattern     = regexp.MustCompile(`\|\|([^\n]+?)\|\|`)
+	redditSpoiler      = regexp.MustCompile(`&gt;!([^\n]+?)!&lt;`)
+	placeholderPattern = regexp.MustCompile("\x00(\\d+)\x00")
+	listItemPattern    = regexp.MustCompile(`^\s*[-*]\s+`)
+	quotePrefixPattern = regexp.MustCompile(`^\s*>\s?`)
+)
+
+// Render преобразует Markdown в безопасный HTML
+func Render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\x00", "")
+	lines := strings.Split(src, "\n")
+
+	var out strings.Builder
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+
+		case quotePrefixPattern.MatchString(line) && !strings.HasPrefix(trimmed, ">!"):
+			flush()
+			var quote []string
+			for ; i < len(lines) && quotePrefixPattern.MatchString(lines[i]) && !strings.HasPrefix(strings.TrimSpace(lines[i]), ">!"); i++ {
+				quote = append(quote, quotePrefixPattern.ReplaceAllString(lines[i], ""))
+			}
+			i--
+			out.WriteString("<blockquote>" + renderInline(strings.Join(quote, "\n")) + "</blockquote>")
+
+		case listItemPattern.MatchString(line) && !strings.HasPrefix(trimmed, "**"):
+			flush()
+			out.WriteString("<ul>")
+			for ; i < len(lines) && listItemPattern.MatchString(lines[i]) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "**"); i++ {
+				out.WriteString("<li>" + renderInline(listItemPattern.ReplaceAllString(lines[i], "")) + "</li>")
+			}
+			i--
+			out.WriteString("</ul>")
+
+		case trimmed == "":
+			flush()
+
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+
+	return out.String()
+}
+
+// renderInline экранирует текст и применяет строчную разметку.
+// Готовые `код` и ссылки выносятся в заглушки, чтобы следующие шаблоны не видели сгенерированную
+// разметку: иначе маркеры внутри адреса ссылки превращались бы в теги внутри атрибута href
+func renderInline(text string) string {
+	var fragments []string
+	hold := func(fragment string) string {
+		fragments = append(fragments, fragment)
+		return fmt.Sprintf("\x00%d\x00", len(fragments)-1)
+	}
+	restore := func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+			index, err := strconv.Atoi(strings.Trim(match, "\x00"))
+			if err != nil || index >= len(fragments) {
+				return ""
+			}
+			return fragments[index]
+		})
+	}
+
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return hold("<code>" + html.EscapeString(match[1:len(match)-1]) + "</code>")
+	})
+
+	text = html.EscapeString(text)
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := linkPattern.FindStringSubmatch(match)
+		label := restore(renderEmphasis(parts[1]))
+		return hold(`<a href="` + parts[2] + `" rel="nofollow noopener noreferrer" target="_blank">` + label + `</a>`)
+	})
+	text = renderEmphasis(text)
+	text = strings.ReplaceAll(text, "\n", "<br>")
+
+	return restore(text)
+}
+
+// renderEmphasis применяет выделение текста: жирный, курсив, зачеркивание и спойлеры
+func renderEmphasis(text string) string {
+	text = boldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	text = italicPattern.ReplaceAllString(text, "<em>$1</em>")
+	text = strikePattern.ReplaceAllString(text, "<del>$1</del>")
+	text = spoilerPattern.ReplaceAllString(text, `<span class="spoiler">$1</span>`)
+	return redditSpoiler.ReplaceAllString(text, `<span class="spoiler">$1</span>`)
+}
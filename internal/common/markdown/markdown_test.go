@@ -0,0 +1,112 @@
+package markdown
+
+import "testing"
+
+const linkAttrs = `rel="nofollow noopener noreferrer" target="_blank"`
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "обычный текст",
+			src:  "hello",
+			want: "<p>hello</p>",
+		},
+		{
+			name: "HTML из ввода экранируется",
+			src:  "<script>alert(1)</script>",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		},
+		{
+			name: "перенос строки внутри абзаца",
+			src:  "a\nb\n\nc",
+			want: "<p>a<br>b</p><p>c</p>",
+		},
+		{
+			name: "выделение текста",
+			src:  "**b** *i* ~~s~~",
+			want: "<p><strong>b</strong> <em>i</em> <del>s</del></p>",
+		},
+		{
+			name: "вложенные маркеры",
+			src:  "**bold *it* x** ||**s**||",
+			want: `<p><strong>bold <em>it</em> x</strong> <span class="spoiler"><strong>s</strong></span></p>`,
+		},
+		{
+			name: "спойлер в стиле reddit",
+			src:  ">!s!<",
+			want: `<p><span class="spoiler">s</span></p>`,
+		},
+		{
+			name: "ссылка",
+			src:  "[x](https://e.com/a)",
+			want: `<p><a href="https://e.com/a" ` + linkAttrs + `>x</a></p>`,
+		},
+		{
+			name: "выделение в тексте ссылки",
+			src:  "[**b**](https://e.com)",
+			want: `<p><a href="https://e.com" ` + linkAttrs + `><strong>b</strong></a></p>`,
+		},
+		{
+			name: "маркеры спойлера в адресе ссылки не становятся тегами",
+			src:  "[x](https://e.com/||a||)",
+			want: `<p><a href="https://e.com/||a||" ` + linkAttrs + `>x</a></p>`,
+		},
+		{
+			name: "маркеры выделения в адресе ссылки не становятся тегами",
+			src:  "**[x](https://e.com/**)",
+			want: `<p>**<a href="https://e.com/**" ` + linkAttrs + `>x</a></p>`,
+		},
+		{
+			name: "кавычка не закрывает атрибут href",
+			src:  `[x](https://e.com/"onmouseover="alert(1))`,
+			want: `<p><a href="https://e.com/&#34;onmouseover=&#34;alert(1" ` + linkAttrs + `>x</a>)</p>`,
+		},
+		{
+			name: "ссылки javascript не допускаются",
+			src:  "[x](javascript:alert(1))",
+			want: "<p>[x](javascript:alert(1))</p>",
+		},
+		{
+			name: "код в адресе ссылки не попадает в href",
+			src:  "[x](https://e.com/`c`)",
+			want: "<p>[x](https://e.com/<code>c</code>)</p>",
+		},
+		{
+			name: "код в тексте ссылки",
+			src:  "[`c`](https://e.com)",
+			want: `<p><a href="https://e.com" ` + linkAttrs + `><code>c</code></a></p>`,
+		},
+		{
+			name: "разметка внутри кода не применяется",
+			src:  "`**x** <b>` and **y**",
+			want: "<p><code>**x** &lt;b&gt;</code> and <strong>y</strong></p>",
+		},
+		{
+			name: "блок кода",
+			src:  "```\n<b>**x**</b>\n```",
+			want: "<pre><code>&lt;b&gt;**x**&lt;/b&gt;</code></pre>",
+		},
+		{
+			name: "список и цитата",
+			src:  "- a\n- *b*\n> q",
+			want: "<ul><li>a</li><li><em>b</em></li></ul><blockquote>q</blockquote>",
+		},
+		{
+			name: "нулевые байты из ввода не подменяют заглушки",
+			src:  "`c` a\x000\x00",
+			want: "<p><code>c</code> a0</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src); got != tt.want {
+				t.Errorf("Render(%q)\n got: %s\nwant: %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -25,3 +25,14 @@ const (
 	StatsPeriodMonthly StatsPeriod = "monthly"
 	StatsPeriodAllTime StatsPeriod = "all_time"
 )
+
+// StatsPeriods содержит все периоды статистики
+var StatsPeriods = []StatsPeriod{StatsPeriodDaily, StatsPeriodWeekly, StatsPeriodMonthly, StatsPeriodAllTime}
+
+// AnalyticsTotals представляет суммарное число просмотров за период
+type AnalyticsTotals struct {
+	Period       StatsPeriod `json:"period"`
+	MangaViews   int64       `json:"manga_views"`
+	ChapterViews int64       `json:"chapter_views"`
+	PageViews    int64       `json:"page_views"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Области действия API ключей
+const (
+	APIKeyScopeRead   = "read"   // чтение данных
+	APIKeyScopeWrite  = "write"  // изменение данных пользователя и каталога
+	APIKeyScopeUpload = "upload" // загрузка глав и страниц
+	APIKeyScopeAdmin  = "admin"  // администрирование
+)
+
+// APIKeyScopes содержит все допустимые области действия API ключей
+var APIKeyScopes = []string{APIKeyScopeRead, APIKeyScopeWrite, APIKeyScopeUpload, APIKeyScopeAdmin}
+
+// APIKey представляет персональный API ключ пользователя
+type APIKey struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"` // Не возвращаем в JSON
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// HasScope проверяет, разрешена ли ключу указанная область действия
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyCreate представляет данные для создания API ключа
+type APIKeyCreate struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyWithSecret представляет только что созданный ключ; сам ключ показывается один раз
+type APIKeyWithSecret struct {
+	APIKey
+	Key string `json:"key"`
+}
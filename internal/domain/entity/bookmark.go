@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// Варианты сортировки закладок
+const (
+	BookmarkSortAdded   = "added"   // по времени добавления закладки
+	BookmarkSortUpdated = "updated" // по времени последнего обновления манги
+)
+
+// Bookmark представляет закладку пользователя на мангу
+type Bookmark struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	MangaID   int64     `json:"manga_id" db:"manga_id"`
+	ChapterID *int64    `json:"chapter_id,omitempty" db:"chapter_id"` // Последняя прочитанная глава
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BookmarkWithManga представляет закладку с данными манги и количеством непрочитанных глав
+type BookmarkWithManga struct {
+	Bookmark
+	MangaTitle          string    `json:"manga_title" db:"manga_title"`
+	MangaCoverImage     string    `json:"manga_cover_image,omitempty" db:"manga_cover_image"`
+	MangaStatus         string    `json:"manga_status" db:"manga_status"`
+	ChapterNumber       *float64  `json:"chapter_number,omitempty" db:"chapter_number"`
+	LatestChapterNumber *float64  `json:"latest_chapter_number,omitempty" db:"latest_chapter_number"`
+	UnreadChapters      int       `json:"unread_chapters" db:"unread_chapters"` // Главы с номером больше последней прочитанной
+	MangaUpdatedAt      time.Time `json:"manga_updated_at" db:"manga_updated_at"`
+}
+
+// BookmarkCreate представляет данные для добавления закладки
+type BookmarkCreate struct {
+	MangaID   int64  `json:"manga_id"`
+	ChapterID *int64 `json:"chapter_id,omitempty"`
+}
+
+// BookmarkFilter представляет параметры получения списка закладок
+type BookmarkFilter struct {
+	Sort   string `json:"sort,omitempty"` // added, updated
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
@@ -2,18 +2,81 @@ package entity
 
 import "time"
 
+// DefaultChapterLanguage язык главы, если он не указан при загрузке
+const DefaultChapterLanguage = "ru"
+
+// Статусы публикации главы
+const (
+	ChapterStatusDraft     = "draft"     // видна только загрузчикам
+	ChapterStatusScheduled = "scheduled" // будет опубликована в PublishAt
+	ChapterStatusPublished = "published"
+)
+
+// ChapterStatuses содержит допустимые статусы публикации главы
+var ChapterStatuses = []string{ChapterStatusDraft, ChapterStatusScheduled, ChapterStatusPublished}
+
 // Chapter представляет главу манги
 type Chapter struct {
-	ID        int64     `json:"id" db:"id"`
-	MangaID   int64     `json:"manga_id" db:"manga_id"`
-	Number    float64   `json:"number" db:"number"` // Используем float для поддержки глав типа 1.5
-	Title     string    `json:"title" db:"title"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID               int64      `json:"id" db:"id"`
+	MangaID          int64      `json:"manga_id" db:"manga_id"`
+	Number           float64    `json:"number" db:"number"` // Используем float для поддержки глав типа 1.5
+	Title            string     `json:"title" db:"title"`
+	Volume           *int       `json:"volume,omitempty" db:"volume"` // номер тома; nil — глава вне томов
+	VolumeTitle      string     `json:"volume_title,omitempty" db:"volume_title"`
+	Language         string     `json:"language" db:"language"` // язык перевода: ru, en, ja
+	GroupID          *int64     `json:"group_id,omitempty" db:"group_id"`
+	GroupName        string     `json:"group_name,omitempty" db:"group_name"`
+	Status           string     `json:"status" db:"status"`
+	PublishAt        *time.Time `json:"publish_at,omitempty" db:"publish_at"` // время запланированной публикации
+	PublishedAt      *time.Time `json:"published_at,omitempty" db:"published_at"`
+	EarlyAccessUntil *time.Time `json:"early_access_until,omitempty" db:"early_access_until"` // до этого времени главу читает только ранний доступ
+	CommentCount     int64      `json:"comment_count" db:"comment_count"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // ChapterWithStats представляет главу со статистикой
 type ChapterWithStats struct {
 	Chapter
-	Views int64 `json:"views"`
+	PageCount int   `json:"page_count"`
+	Views     int64 `json:"views"`
+}
+
+// ChapterFilter представляет фильтры списка глав манги
+type ChapterFilter struct {
+	Languages    []string `json:"languages,omitempty"`     // пустой список — все языки
+	AllLanguages bool     `json:"all_languages,omitempty"` // не применять предпочитаемые языки пользователя
+}
+
+// Volume представляет том манги с его главами
+type Volume struct {
+	Number     *int       `json:"number"` // nil — главы без тома, всегда последние
+	Title      string     `json:"title,omitempty"`
+	CoverImage string     `json:"cover_image,omitempty"`
+	Chapters   []*Chapter `json:"chapters"`
+}
+
+// VolumeInfo представляет название и обложку тома
+type VolumeInfo struct {
+	MangaID    int64     `json:"manga_id" db:"manga_id"`
+	Number     int       `json:"number" db:"number"`
+	Title      string    `json:"title" db:"title"`
+	CoverImage string    `json:"cover_image" db:"cover_image"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// VolumeInput представляет данные для изменения тома
+type VolumeInput struct {
+	Title      string `json:"title"`
+	CoverImage string `json:"cover_image"`
+}
+
+// IsPublished проверяет, опубликована ли глава
+func (c *Chapter) IsPublished() bool {
+	return c.Status == ChapterStatusPublished
+}
+
+// InEarlyAccess проверяет, действует ли ранний доступ к главе в указанный момент
+func (c *Chapter) InEarlyAccess(now time.Time) bool {
+	return c.EarlyAccessUntil != nil && c.EarlyAccessUntil.After(now)
 }
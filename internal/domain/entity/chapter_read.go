@@ -0,0 +1,28 @@
+package entity
+
+// ChapterWithReadState представляет главу со статистикой и отметкой о прочтении текущим пользователем
+type ChapterWithReadState struct {
+	ChapterWithStats
+	Read bool `json:"read"`
+}
+
+// ChapterReadSummary представляет количество прочитанных и непрочитанных глав манги; переводы одной главы считаются одной главой
+type ChapterReadSummary struct {
+	Total  int `json:"total"`
+	Read   int `json:"read"`
+	Unread int `json:"unread"`
+}
+
+// ChapterRange представляет диапазон номеров глав включительно.
+// Без from — все главы до to, без обеих границ — все главы манги
+type ChapterRange struct {
+	From *float64 `json:"from,omitempty"`
+	To   *float64 `json:"to,omitempty"`
+}
+
+// ChapterReadResult представляет итог изменения отметок о прочтении
+type ChapterReadResult struct {
+	MangaID int64 `json:"manga_id"`
+	Changed int   `json:"changed"` // Количество глав, у которых изменилась отметка
+	Unread  int   `json:"unread"`  // Непрочитанных глав манги после изменения
+}
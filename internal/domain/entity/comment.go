@@ -0,0 +1,79 @@
+package entity
+
+import "time"
+
+// Порядок сортировки комментариев
+const (
+	CommentSortNewest = "newest"
+	CommentSortOldest = "oldest"
+	CommentSortTop    = "top" // по рейтингу, затем по количеству ответов
+)
+
+// CommentSorts содержит допустимые варианты сортировки комментариев
+var CommentSorts = []string{CommentSortNewest, CommentSortOldest, CommentSortTop}
+
+// Comment представляет комментарий к манге или главе.
+// Комментарий относится ровно к одному объекту; ответы наследуют объект родителя
+type Comment struct {
+	ID          int64      `json:"id" db:"id"`
+	UserID      int64      `json:"user_id" db:"user_id"`
+	MangaID     *int64     `json:"manga_id,omitempty" db:"manga_id"`
+	ChapterID   *int64     `json:"chapter_id,omitempty" db:"chapter_id"`
+	ParentID    *int64     `json:"parent_id,omitempty" db:"parent_id"`
+	Content     string     `json:"content" db:"content"`           // исходный Markdown
+	ContentHTML string     `json:"content_html" db:"content_html"` // очищенный HTML для отображения
+	Spoiler     bool       `json:"spoiler" db:"spoiler"`           // весь комментарий скрыт как спойлер
+	ReplyCount  int        `json:"reply_count" db:"reply_count"`
+	Upvotes     int        `json:"upvotes" db:"upvotes"`
+	Downvotes   int        `json:"downvotes" db:"downvotes"`
+	Score       int        `json:"score" db:"score"`
+	ReportCount int        `json:"-" db:"report_count"` // открытые жалобы, видны только модераторам в очереди
+	EditedAt    *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	HiddenAt    *time.Time `json:"hidden_at,omitempty" db:"hidden_at"` // скрыт модератором или по жалобам
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CommentWithUser представляет комментарий с данными автора
+type CommentWithUser struct {
+	Comment
+	Username string `json:"username" db:"username"`
+	MyVote   *int   `json:"my_vote,omitempty" db:"-"` // голос текущего пользователя: 1 или -1
+}
+
+// CommentCreate представляет данные для создания комментария или ответа
+type CommentCreate struct {
+	MangaID   *int64 `json:"-"`
+	ChapterID *int64 `json:"-"`
+	ParentID  *int64 `json:"-"`
+	Content   string `json:"content"`
+	Spoiler   bool   `json:"spoiler"`
+}
+
+// CommentUpdate представляет данные для редактирования комментария
+type CommentUpdate struct {
+	Content string `json:"content"`
+	Spoiler *bool  `json:"spoiler,omitempty"`
+}
+
+// CommentFilter представляет параметры получения комментариев.
+// Без ParentID возвращаются комментарии верхнего уровня объекта, с ParentID — ответы на комментарий
+type CommentFilter struct {
+	MangaID       *int64 `json:"-"`
+	ChapterID     *int64 `json:"-"`
+	ParentID      *int64 `json:"-"`
+	IncludeHidden bool   `json:"-"` // для модераторов: скрытые комментарии выдаются с текстом
+	Sort          string `json:"sort,omitempty"`
+	Limit         int    `json:"limit,omitempty"`
+	Offset        int    `json:"offset,omitempty"`
+}
+
+// CommentEdit представляет предыдущую версию комментария в истории правок
+type CommentEdit struct {
+	ID        int64     `json:"id" db:"id"`
+	CommentID int64     `json:"comment_id" db:"comment_id"`
+	Content   string    `json:"content" db:"content"`
+	EditedBy  *int64    `json:"edited_by,omitempty" db:"edited_by"`
+	EditedAt  time.Time `json:"edited_at" db:"edited_at"`
+}
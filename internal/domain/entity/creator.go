@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// Роли автора в манге
+const (
+	CreatorRoleAuthor = "author" // автор истории
+	CreatorRoleArtist = "artist" // художник
+)
+
+// CreatorRoles содержит допустимые роли автора в манге
+var CreatorRoles = []string{CreatorRoleAuthor, CreatorRoleArtist}
+
+// Creator представляет автора или художника
+type Creator struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Bio       string    `json:"bio" db:"bio"`
+	Aliases   []string  `json:"aliases" db:"-"` // варианты написания имени, включая основное
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreatorWork представляет мангу в библиографии автора
+type CreatorWork struct {
+	MangaID       int64     `json:"manga_id" db:"manga_id"`
+	Title         string    `json:"title" db:"title"`
+	CoverImage    string    `json:"cover_image,omitempty" db:"cover_image"`
+	Status        string    `json:"status" db:"status"`
+	BayesianScore float64   `json:"bayesian_score" db:"bayesian_score"`
+	Roles         []string  `json:"roles" db:"-"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreatorDetails представляет автора с библиографией
+type CreatorDetails struct {
+	Creator
+	Works []*CreatorWork `json:"works"`
+}
+
+// CreatorInput представляет данные для создания или изменения автора.
+// Основное имя всегда добавляется в варианты написания
+type CreatorInput struct {
+	Name    string   `json:"name"`
+	Bio     string   `json:"bio"`
+	Aliases []string `json:"aliases"`
+}
+
+// CreatorMergeInput представляет запрос на объединение дубликата с автором
+type CreatorMergeInput struct {
+	SourceID int64 `json:"source_id"` // дубликат, который будет удален
+}
+
+// CreatorFilter представляет параметры поиска авторов
+type CreatorFilter struct {
+	Query  string `json:"query,omitempty"` // поиск по любому варианту имени
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// MangaCredit представляет участие автора в манге.
+// При сохранении манги автор задается ID либо именем: имя сопоставляется с существующим автором
+// по любому варианту написания, а при отсутствии совпадений создается новый автор
+type MangaCredit struct {
+	CreatorID int64  `json:"id" db:"creator_id"`
+	Name      string `json:"name" db:"name"`
+	Role      string `json:"role" db:"role"`
+}
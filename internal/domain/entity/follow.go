@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// MangaFollow представляет подписку пользователя на мангу
+type MangaFollow struct {
+	UserID    int64     `json:"user_id" db:"user_id"`
+	MangaID   int64     `json:"manga_id" db:"manga_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// MangaFollowWithManga представляет подписку с данными манги
+type MangaFollowWithManga struct {
+	MangaFollow
+	MangaTitle      string `json:"manga_title" db:"manga_title"`
+	MangaCoverImage string `json:"manga_cover_image,omitempty" db:"manga_cover_image"`
+	MangaStatus     string `json:"manga_status" db:"manga_status"`
+}
+
+// FollowFilter представляет параметры получения списка подписок
+type FollowFilter struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+// FollowStatus представляет состояние подписки текущего пользователя на мангу
+type FollowStatus struct {
+	MangaID   int64 `json:"manga_id"`
+	Following bool  `json:"following"`
+}
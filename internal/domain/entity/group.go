@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// ScanlationGroup представляет группу переводчиков
+type ScanlationGroup struct {
+	ID           int64     `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`
+	Description  string    `json:"description" db:"description"`
+	Website      string    `json:"website,omitempty" db:"website"`
+	ChapterCount int64     `json:"chapter_count" db:"chapter_count"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ScanlationGroupInput представляет данные для создания или изменения группы
+type ScanlationGroupInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+}
+
+// ScanlationGroupFilter представляет параметры поиска групп
+type ScanlationGroupFilter struct {
+	Query  string `json:"query,omitempty"` // часть названия
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// GroupRelease представляет главу, выпущенную группой
+type GroupRelease struct {
+	ChapterID  int64     `json:"chapter_id" db:"chapter_id"`
+	MangaID    int64     `json:"manga_id" db:"manga_id"`
+	MangaTitle string    `json:"manga_title" db:"manga_title"`
+	Number     float64   `json:"number" db:"number"`
+	Title      string    `json:"title" db:"title"`
+	Language   string    `json:"language" db:"language"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// PreferredLanguages представляет предпочитаемые языки глав пользователя в порядке приоритета
+type PreferredLanguages struct {
+	Languages []string `json:"languages"`
+}
@@ -0,0 +1,105 @@
+package entity
+
+import "time"
+
+// Статусы чтения манги в библиотеке
+const (
+	LibraryStatusReading   = "reading"
+	LibraryStatusPlanned   = "planned"
+	LibraryStatusCompleted = "completed"
+	LibraryStatusDropped   = "dropped"
+)
+
+// LibraryStatuses список допустимых статусов чтения
+var LibraryStatuses = []string{
+	LibraryStatusReading,
+	LibraryStatusPlanned,
+	LibraryStatusCompleted,
+	LibraryStatusDropped,
+}
+
+// Варианты сортировки библиотеки
+const (
+	LibrarySortUpdated = "updated" // по времени изменения записи
+	LibrarySortAdded   = "added"   // по времени добавления в библиотеку
+	LibrarySortTitle   = "title"   // по названию манги
+	LibrarySortScore   = "score"   // по личной оценке
+)
+
+// LibraryEntry представляет мангу в библиотеке пользователя
+type LibraryEntry struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"user_id" db:"user_id"`
+	MangaID    int64      `json:"manga_id" db:"manga_id"`
+	Status     string     `json:"status" db:"status"`         // reading, planned, completed, dropped
+	Score      *int       `json:"score,omitempty" db:"score"` // Личная оценка от 1 до 10
+	Notes      string     `json:"notes" db:"notes"`
+	StartedAt  *time.Time `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	MALID      *int64     `json:"mal_id,omitempty" db:"mal_id"` // ID манги на MyAnimeList из импортированного файла
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// LibraryEntryWithManga представляет запись библиотеки с данными манги и полками
+type LibraryEntryWithManga struct {
+	LibraryEntry
+	MangaTitle      string  `json:"manga_title" db:"manga_title"`
+	MangaCoverImage string  `json:"manga_cover_image,omitempty" db:"manga_cover_image"`
+	MangaStatus     string  `json:"manga_status" db:"manga_status"`
+	TotalChapters   int     `json:"total_chapters" db:"total_chapters"`
+	ChaptersRead    int     `json:"chapters_read" db:"chapters_read"`
+	ShelfIDs        []int64 `json:"shelf_ids"`
+}
+
+// LibraryEntryUpsert представляет данные для добавления или замены записи библиотеки
+type LibraryEntryUpsert struct {
+	Status string `json:"status"`
+	Score  *int   `json:"score,omitempty"`
+	Notes  string `json:"notes"`
+}
+
+// LibraryFilter представляет параметры получения библиотеки
+type LibraryFilter struct {
+	Status  string `json:"status,omitempty"`
+	ShelfID *int64 `json:"shelf_id,omitempty"`
+	Query   string `json:"q,omitempty"` // Поиск по названию манги
+	Sort    string `json:"sort,omitempty"`
+	Limit   int    `json:"limit,omitempty"` // 0 — без ограничения
+	Offset  int    `json:"offset,omitempty"`
+}
+
+// LibraryStats представляет количество записей библиотеки по статусам
+type LibraryStats struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// LibraryShelf представляет пользовательскую полку
+type LibraryShelf struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Name       string    `json:"name" db:"name"`
+	EntryCount int       `json:"entry_count" db:"entry_count"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// LibraryShelfCreate представляет данные для создания или переименования полки
+type LibraryShelfCreate struct {
+	Name string `json:"name"`
+}
+
+// LibraryImportItem представляет запись, разобранную из файла импорта
+type LibraryImportItem struct {
+	Entry     LibraryEntry
+	Shelves   []string
+	Overwrite bool // Заменять ли существующую запись
+}
+
+// LibraryImportResult представляет итог импорта библиотеки
+type LibraryImportResult struct {
+	Imported  int      `json:"imported"`
+	Updated   int      `json:"updated"`
+	Skipped   int      `json:"skipped"`
+	Unmatched []string `json:"unmatched"` // Названия, для которых не найдена манга в каталоге
+}
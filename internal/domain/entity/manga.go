@@ -2,25 +2,55 @@ package entity
 
 import "time"
 
+// Порядок сортировки каталога манги
+const (
+	MangaSortUpdated = "updated" // по времени обновления
+	MangaSortScore   = "score"   // по байесовской оценке
+	MangaSortAverage = "average" // по средней оценке
+	MangaSortRatings = "ratings" // по количеству оценок
+)
+
+// MangaSorts содержит допустимые варианты сортировки каталога
+var MangaSorts = []string{MangaSortUpdated, MangaSortScore, MangaSortAverage, MangaSortRatings}
+
 // Manga представляет сущность манги
 type Manga struct {
-	ID          int64     `json:"id" db:"id"`
-	Title       string    `json:"title" db:"title"`
-	Description string    `json:"description" db:"description"`
-	CoverImage  string    `json:"cover_image,omitempty" db:"cover_image"`
-	Status      string    `json:"status" db:"status"` // ongoing, completed, hiatus
-	Author      string    `json:"author" db:"author"`
-	Artist      string    `json:"artist,omitempty" db:"artist"`
-	Genres      []string  `json:"genres,omitempty"` // Связь многие-ко-многим
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID             int64             `json:"id" db:"id"`
+	Title          string            `json:"title" db:"title"`
+	Description    string            `json:"description" db:"description"` // на языке запроса, если есть перевод
+	CoverImage     string            `json:"cover_image,omitempty" db:"cover_image"`
+	Status         string            `json:"status" db:"status"` // ongoing, completed, hiatus
+	Author         string            `json:"author" db:"author"`
+	Artist         string            `json:"artist,omitempty" db:"artist"`
+	MALID          *int64            `json:"mal_id,omitempty" db:"mal_id"` // ID манги на MyAnimeList для экспорта библиотек
+	Genres         []string          `json:"genres,omitempty"`             // Связь многие-ко-многим
+	Creators       []*MangaCredit    `json:"creators,omitempty" db:"-"`    // авторы и художники; Author и Artist — их имена через запятую
+	CommentCount   int64             `json:"comment_count" db:"comment_count"`
+	RatingAverage  float64           `json:"rating_average" db:"rating_average"`
+	BayesianScore  float64           `json:"bayesian_score" db:"bayesian_score"` // средняя оценка, сглаженная к среднему по каталогу
+	RatingCount    int64             `json:"rating_count" db:"rating_count"`
+	ReviewCount    int64             `json:"review_count" db:"review_count"`
+	Related        *MangaRelated     `json:"related,omitempty" db:"-"` // заполняется только для страницы манги
+	AltTitles      []*AltTitle       `json:"alt_titles,omitempty" db:"-"`
+	Descriptions   map[string]string `json:"descriptions,omitempty" db:"-"`    // описания по кодам языков
+	LocalizedTitle string            `json:"localized_title,omitempty" db:"-"` // альтернативное название на языке запроса
+	Language       string            `json:"language,omitempty" db:"-"`        // язык выбранного описания
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 // MangaFilter представляет фильтры для поиска манги
 type MangaFilter struct {
-	Title  string   `json:"title,omitempty"`
+	Title  string   `json:"title,omitempty"` // ищется в основном и альтернативных названиях
 	Genres []string `json:"genres,omitempty"`
 	Status string   `json:"status,omitempty"`
+	Sort   string   `json:"sort,omitempty"`
 	Limit  int      `json:"limit,omitempty"`
 	Offset int      `json:"offset,omitempty"`
 }
+
+// AltTitle представляет альтернативное название манги
+type AltTitle struct {
+	Title    string `json:"title" db:"title"`
+	Language string `json:"language" db:"language"` // код языка: ja, ja-ro (ромадзи), en, ru
+}
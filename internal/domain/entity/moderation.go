@@ -0,0 +1,119 @@
+package entity
+
+import "time"
+
+// Причины жалоб на комментарии
+const (
+	ReportReasonSpam     = "spam"
+	ReportReasonAbuse    = "abuse"
+	ReportReasonSpoiler  = "spoiler"
+	ReportReasonOfftopic = "offtopic"
+	ReportReasonOther    = "other"
+)
+
+// ReportReasons содержит допустимые причины жалоб
+var ReportReasons = []string{ReportReasonSpam, ReportReasonAbuse, ReportReasonSpoiler, ReportReasonOfftopic, ReportReasonOther}
+
+// Статусы жалоб
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"  // по жалобе приняты меры
+	ReportStatusDismissed = "dismissed" // жалоба отклонена
+)
+
+// Действия модераторов
+const (
+	ModerationActionHide     = "hide"
+	ModerationActionUnhide   = "unhide"
+	ModerationActionDelete   = "delete"
+	ModerationActionWarn     = "warn"
+	ModerationActionBan      = "ban"
+	ModerationActionDismiss  = "dismiss"
+	ModerationActionAutoHide = "auto_hide" // скрытие системой по числу жалоб
+)
+
+// ModerationActions содержит действия, доступные модератору вручную
+var ModerationActions = []string{
+	ModerationActionHide, ModerationActionUnhide, ModerationActionDelete,
+	ModerationActionWarn, ModerationActionBan, ModerationActionDismiss,
+}
+
+// ModerationLogActions содержит все действия, которые могут встретиться в журнале модерации
+var ModerationLogActions = []string{
+	ModerationActionHide, ModerationActionUnhide, ModerationActionDelete,
+	ModerationActionWarn, ModerationActionBan, ModerationActionDismiss, ModerationActionAutoHide,
+}
+
+// CommentVote представляет голос за комментарий: 1, -1 или 0 для отмены голоса
+type CommentVote struct {
+	Value int `json:"value"`
+}
+
+// CommentVoteResult представляет рейтинг комментария после голосования
+type CommentVoteResult struct {
+	CommentID int64 `json:"comment_id" db:"id"`
+	Upvotes   int   `json:"upvotes" db:"upvotes"`
+	Downvotes int   `json:"downvotes" db:"downvotes"`
+	Score     int   `json:"score" db:"score"`
+	MyVote    int   `json:"my_vote" db:"-"`
+}
+
+// CommentReport представляет жалобу пользователя на комментарий
+type CommentReport struct {
+	ID         int64      `json:"id" db:"id"`
+	CommentID  int64      `json:"comment_id" db:"comment_id"`
+	ReporterID int64      `json:"reporter_id" db:"reporter_id"`
+	Reason     string     `json:"reason" db:"reason"`
+	Details    string     `json:"details,omitempty" db:"details"`
+	Status     string     `json:"status" db:"status"`
+	ResolvedBy *int64     `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CommentReportCreate представляет данные жалобы на комментарий
+type CommentReportCreate struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details,omitempty"`
+}
+
+// ModerationQueueItem представляет комментарий с открытыми жалобами в очереди модерации
+type ModerationQueueItem struct {
+	CommentWithUser
+	OpenReports    int       `json:"open_reports" db:"open_reports"`
+	Reasons        []string  `json:"reasons" db:"-"`
+	LastReportedAt time.Time `json:"last_reported_at" db:"last_reported_at"`
+}
+
+// ModerationQueueFilter представляет параметры очереди модерации
+type ModerationQueueFilter struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+// ModerationActionRequest представляет действие модератора над комментарием
+type ModerationActionRequest struct {
+	Action   string     `json:"action"` // hide, unhide, delete, warn, ban, dismiss
+	Reason   string     `json:"reason"`
+	BanUntil *time.Time `json:"ban_until,omitempty"` // для ban: не указано — бессрочно
+}
+
+// ModerationLogEntry представляет запись журнала действий модераторов
+type ModerationLogEntry struct {
+	ID           int64     `json:"id" db:"id"`
+	ModeratorID  *int64    `json:"moderator_id,omitempty" db:"moderator_id"` // nil — автоматическое действие
+	Action       string    `json:"action" db:"action"`
+	CommentID    *int64    `json:"comment_id,omitempty" db:"comment_id"`
+	TargetUserID *int64    `json:"target_user_id,omitempty" db:"target_user_id"`
+	Reason       string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ModerationLogFilter представляет параметры получения журнала модерации
+type ModerationLogFilter struct {
+	ModeratorID  *int64 `json:"moderator_id,omitempty"`
+	TargetUserID *int64 `json:"target_user_id,omitempty"`
+	Action       string `json:"action,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+	Offset       int    `json:"offset,omitempty"`
+}
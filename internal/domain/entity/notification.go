@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+// Типы уведомлений
+const (
+	NotificationTypeNewChapter     = "new_chapter"
+	NotificationTypeCommentWarning = "comment_warning" // предупреждение модератора автору комментария
+)
+
+// Notification представляет уведомление пользователя внутри приложения
+type Notification struct {
+	ID        int64      `json:"id" db:"id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	Type      string     `json:"type" db:"type"` // new_chapter, comment_warning
+	MangaID   int64      `json:"manga_id" db:"manga_id"`
+	ChapterID *int64     `json:"chapter_id,omitempty" db:"chapter_id"`
+	CommentID *int64     `json:"comment_id,omitempty" db:"comment_id"`
+	Message   string     `json:"message,omitempty" db:"message"` // для comment_warning: причина предупреждения
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NotificationItem представляет уведомление с данными манги и главы
+type NotificationItem struct {
+	Notification
+	MangaTitle      string   `json:"manga_title" db:"manga_title"`
+	MangaCoverImage string   `json:"manga_cover_image,omitempty" db:"manga_cover_image"`
+	ChapterNumber   *float64 `json:"chapter_number,omitempty" db:"chapter_number"`
+	ChapterTitle    *string  `json:"chapter_title,omitempty" db:"chapter_title"`
+}
+
+// NotificationFilter представляет параметры получения уведомлений
+type NotificationFilter struct {
+	UnreadOnly bool `json:"unread_only,omitempty"`
+	Limit      int  `json:"limit,omitempty"`
+	Offset     int  `json:"offset,omitempty"`
+}
+
+// NotificationUnreadCount представляет количество непрочитанных уведомлений
+type NotificationUnreadCount struct {
+	Unread int `json:"unread"`
+}
+
+// NotificationMarkResult представляет количество уведомлений, отмеченных прочитанными
+type NotificationMarkResult struct {
+	Updated int `json:"updated"`
+}
+
+// NotificationJob представляет задание рассылки уведомлений подписчикам манги.
+// Рассылка идет пакетами по возрастанию user_id, CursorUserID — последний обработанный подписчик
+type NotificationJob struct {
+	ID           int64      `json:"id" db:"id"`
+	Type         string     `json:"type" db:"type"`
+	MangaID      int64      `json:"manga_id" db:"manga_id"`
+	ChapterID    *int64     `json:"chapter_id,omitempty" db:"chapter_id"`
+	CursorUserID int64      `json:"cursor_user_id" db:"cursor_user_id"`
+	Notified     int        `json:"notified" db:"notified"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
@@ -11,3 +11,14 @@ type Page struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// PageOrderInput представляет новый порядок страниц главы
+type PageOrderInput struct {
+	PageIDs []int64 `json:"page_ids"` // все страницы главы в новом порядке
+}
+
+// PageImage представляет загружаемое изображение страницы
+type PageImage struct {
+	Filename string
+	Data     []byte
+}
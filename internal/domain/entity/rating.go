@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// Границы оценки манги
+const (
+	RatingMinScore = 1
+	RatingMaxScore = 10
+)
+
+// MangaRating представляет оценку манги пользователем
+type MangaRating struct {
+	UserID    int64     `json:"user_id" db:"user_id"`
+	MangaID   int64     `json:"manga_id" db:"manga_id"`
+	Score     int       `json:"score" db:"score"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MangaRatingInput представляет оценку, которую ставит пользователь
+type MangaRatingInput struct {
+	Score int `json:"score"` // от 1 до 10
+}
+
+// RatingBucket представляет количество оценок с одним значением
+type RatingBucket struct {
+	Score int   `json:"score"`
+	Count int64 `json:"count"`
+}
+
+// MangaRatingSummary представляет сводку оценок манги с гистограммой
+type MangaRatingSummary struct {
+	MangaID       int64          `json:"manga_id"`
+	Average       float64        `json:"average"`
+	BayesianScore float64        `json:"bayesian_score"`
+	Count         int64          `json:"count"`
+	Histogram     []RatingBucket `json:"histogram"` // по одной строке на каждую оценку от 1 до 10
+	MyScore       *int           `json:"my_score,omitempty"`
+}
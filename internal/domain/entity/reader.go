@@ -0,0 +1,31 @@
+package entity
+
+// ReaderManga представляет краткие данные манги для читалки
+type ReaderManga struct {
+	ID         int64  `json:"id"`
+	Title      string `json:"title"`
+	CoverImage string `json:"cover_image,omitempty"`
+	Status     string `json:"status"`
+}
+
+// ReaderPage представляет страницу главы для читалки
+type ReaderPage struct {
+	ID       int64  `json:"id"`
+	Number   int    `json:"number"`
+	ImageURL string `json:"image_url"`
+}
+
+// ReaderBootstrap содержит все, что нужно читалке для открытия главы, в одном ответе
+type ReaderBootstrap struct {
+	Chapter       *Chapter      `json:"chapter"`
+	Manga         *ReaderManga  `json:"manga"`
+	Pages         []*ReaderPage `json:"pages"`
+	PrevChapterID *int64        `json:"prev_chapter_id"` // предыдущая глава на том же языке; nil — первая
+	NextChapterID *int64        `json:"next_chapter_id"` // следующая глава на том же языке; nil — последняя
+}
+
+// PageImageSignature представляет подпись ссылки на изображение страницы главы с ограниченным доступом
+type PageImageSignature struct {
+	ExpiresAt int64  // Unix-время окончания действия ссылки
+	Signature string // HMAC ссылки в hex
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// ReadingHistoryEntry представляет запись истории чтения: последнее открытие главы пользователем
+type ReadingHistoryEntry struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     int64     `json:"user_id" db:"user_id"`
+	MangaID    int64     `json:"manga_id" db:"manga_id"`
+	ChapterID  int64     `json:"chapter_id" db:"chapter_id"`
+	PageNumber *int      `json:"page_number,omitempty" db:"page_number"` // Последняя открытая страница главы
+	ReadAt     time.Time `json:"read_at" db:"read_at"`
+}
+
+// ReadingHistoryItem представляет запись истории чтения с данными манги и главы
+type ReadingHistoryItem struct {
+	ReadingHistoryEntry
+	MangaTitle      string  `json:"manga_title" db:"manga_title"`
+	MangaCoverImage string  `json:"manga_cover_image,omitempty" db:"manga_cover_image"`
+	ChapterNumber   float64 `json:"chapter_number" db:"chapter_number"`
+	ChapterTitle    string  `json:"chapter_title" db:"chapter_title"`
+}
+
+// ReadingHistoryFilter представляет параметры получения истории чтения
+type ReadingHistoryFilter struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
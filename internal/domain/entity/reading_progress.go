@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Политики разрешения конфликтов при синхронизации прогресса чтения
+const (
+	ProgressPolicyLatestWrite  = "latest_write"  // побеждает изменение с более поздним временем на устройстве
+	ProgressPolicyFurthestRead = "furthest_read" // побеждает позиция дальше по манге (глава, страница, прокрутка)
+)
+
+// ReadingProgress представляет текущую позицию чтения манги пользователем
+type ReadingProgress struct {
+	UserID          int64     `json:"user_id" db:"user_id"`
+	MangaID         int64     `json:"manga_id" db:"manga_id"`
+	ChapterID       int64     `json:"chapter_id" db:"chapter_id"`
+	ChapterNumber   float64   `json:"chapter_number" db:"chapter_number"`
+	PageNumber      int       `json:"page_number" db:"page_number"`
+	ScrollOffset    float64   `json:"scroll_offset" db:"scroll_offset"` // Доля прокрутки страницы от 0 до 1
+	DeviceID        string    `json:"device_id" db:"device_id"`
+	ClientUpdatedAt time.Time `json:"client_timestamp" db:"client_updated_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProgressUpdate представляет позицию чтения, присланную устройством
+type ProgressUpdate struct {
+	MangaID         int64     `json:"manga_id,omitempty"` // Используется в пакетной синхронизации
+	ChapterID       int64     `json:"chapter_id"`
+	PageNumber      int       `json:"page_number"`
+	ScrollOffset    float64   `json:"scroll_offset"`
+	DeviceID        string    `json:"device_id"`
+	ClientUpdatedAt time.Time `json:"client_timestamp"`
+	Policy          string    `json:"policy,omitempty"` // latest_write (по умолчанию) или furthest_read
+}
+
+// ProgressSyncRequest представляет пакет позиций, накопленных устройством без сети
+type ProgressSyncRequest struct {
+	Policy string            `json:"policy,omitempty"` // Политика для элементов без собственной политики
+	Items  []*ProgressUpdate `json:"items"`
+}
+
+// ProgressSyncResult представляет результат синхронизации позиции чтения манги
+type ProgressSyncResult struct {
+	MangaID  int64            `json:"manga_id"`
+	Applied  bool             `json:"applied"`            // false — на сервере осталась позиция, победившая по политике
+	Progress *ReadingProgress `json:"progress,omitempty"` // Актуальная позиция на сервере
+	Error    string           `json:"error,omitempty"`
+}
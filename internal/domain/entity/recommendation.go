@@ -0,0 +1,15 @@
+package entity
+
+// Причины попадания манги в рекомендации
+const (
+	RecommendationReasonCoReading = "co_reading" // ее читают читатели прочитанной пользователем манги
+	RecommendationReasonGenre     = "genre"      // совпадает с предпочитаемыми жанрами пользователя
+	RecommendationReasonPopular   = "popular"    // популярная манга для пользователей без истории чтения
+)
+
+// Recommendation представляет мангу в персональной подборке пользователя
+type Recommendation struct {
+	Manga
+	Score  float64 `json:"score" db:"score"` // итоговая оценка релевантности от 0 до 1
+	Reason string  `json:"reason" db:"reason"`
+}
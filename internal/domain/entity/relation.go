@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+// Типы связей между мангой
+const (
+	MangaRelationSequel       = "sequel"        // продолжение
+	MangaRelationPrequel      = "prequel"       // предыстория
+	MangaRelationSpinOff      = "spin_off"      // спин-офф
+	MangaRelationParentStory  = "parent_story"  // основная история спин-оффа
+	MangaRelationAdaptation   = "adaptation"    // адаптация (например, манга по ранобэ)
+	MangaRelationSource       = "source"        // первоисточник адаптации
+	MangaRelationSameUniverse = "same_universe" // общая вселенная
+)
+
+// MangaRelationTypes содержит допустимые типы связей
+var MangaRelationTypes = []string{
+	MangaRelationSequel, MangaRelationPrequel, MangaRelationSpinOff, MangaRelationParentStory,
+	MangaRelationAdaptation, MangaRelationSource, MangaRelationSameUniverse,
+}
+
+// MangaRelationInverse сопоставляет типу связи тип обратной связи: если B — продолжение A, то A — предыстория B
+var MangaRelationInverse = map[string]string{
+	MangaRelationSequel:       MangaRelationPrequel,
+	MangaRelationPrequel:      MangaRelationSequel,
+	MangaRelationSpinOff:      MangaRelationParentStory,
+	MangaRelationParentStory:  MangaRelationSpinOff,
+	MangaRelationAdaptation:   MangaRelationSource,
+	MangaRelationSource:       MangaRelationAdaptation,
+	MangaRelationSameUniverse: MangaRelationSameUniverse,
+}
+
+// MangaRelation представляет связанную мангу: Type описывает, чем связанная манга является для исходной
+type MangaRelation struct {
+	MangaID        int64     `json:"-" db:"manga_id"`
+	RelatedMangaID int64     `json:"manga_id" db:"related_manga_id"`
+	Type           string    `json:"type" db:"relation_type"`
+	Title          string    `json:"title" db:"title"`
+	CoverImage     string    `json:"cover_image,omitempty" db:"cover_image"`
+	Status         string    `json:"status" db:"status"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// MangaRelationInput представляет данные для создания или изменения связи
+type MangaRelationInput struct {
+	RelatedMangaID int64  `json:"related_manga_id"`
+	Type           string `json:"type"`
+}
+
+// SimilarManga представляет мангу, похожую по жанрам или авторам
+type SimilarManga struct {
+	MangaID      int64   `json:"manga_id" db:"manga_id"`
+	Title        string  `json:"title" db:"title"`
+	CoverImage   string  `json:"cover_image,omitempty" db:"cover_image"`
+	Status       string  `json:"status" db:"status"`
+	SharedGenres int     `json:"shared_genres" db:"shared_genres"`
+	SameCreator  bool    `json:"same_creator" db:"same_creator"` // совпадает автор или художник
+	Score        float64 `json:"score" db:"score"`
+}
+
+// MangaRelated представляет раздел связанной манги на странице манги
+type MangaRelated struct {
+	Relations []*MangaRelation `json:"relations"`
+	Similar   []*SimilarManga  `json:"similar"`
+}
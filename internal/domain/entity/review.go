@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// Порядок сортировки рецензий
+const (
+	ReviewSortHelpful = "helpful" // по числу отметок "полезно"
+	ReviewSortNewest  = "newest"
+)
+
+// ReviewSorts содержит допустимые варианты сортировки рецензий
+var ReviewSorts = []string{ReviewSortHelpful, ReviewSortNewest}
+
+// Review представляет рецензию пользователя на мангу
+type Review struct {
+	ID             int64     `json:"id" db:"id"`
+	MangaID        int64     `json:"manga_id" db:"manga_id"`
+	UserID         int64     `json:"user_id" db:"user_id"`
+	Title          string    `json:"title" db:"title"`
+	Content        string    `json:"content" db:"content"`           // исходный Markdown
+	ContentHTML    string    `json:"content_html" db:"content_html"` // очищенный HTML для отображения
+	Spoiler        bool      `json:"spoiler" db:"spoiler"`
+	HelpfulCount   int       `json:"helpful_count" db:"helpful_count"`
+	UnhelpfulCount int       `json:"unhelpful_count" db:"unhelpful_count"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ReviewWithUser представляет рецензию с автором и его оценкой манги
+type ReviewWithUser struct {
+	Review
+	Username string `json:"username" db:"username"`
+	Rating   *int   `json:"rating,omitempty" db:"rating"` // оценка манги автором рецензии
+	MyVote   *int   `json:"my_vote,omitempty" db:"-"`     // голос текущего пользователя: 1 или -1
+}
+
+// ReviewInput представляет данные для создания или редактирования рецензии
+type ReviewInput struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Spoiler bool   `json:"spoiler"`
+}
+
+// ReviewFilter представляет параметры получения рецензий манги
+type ReviewFilter struct {
+	MangaID int64  `json:"-"`
+	Sort    string `json:"sort,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+}
+
+// ReviewVote представляет оценку полезности рецензии: 1 — полезно, -1 — бесполезно, 0 — снять оценку
+type ReviewVote struct {
+	Value int `json:"value"`
+}
+
+// ReviewVoteResult представляет счетчики полезности рецензии после голосования
+type ReviewVoteResult struct {
+	ReviewID       int64 `json:"review_id" db:"id"`
+	HelpfulCount   int   `json:"helpful_count" db:"helpful_count"`
+	UnhelpfulCount int   `json:"unhelpful_count" db:"unhelpful_count"`
+	MyVote         int   `json:"my_vote" db:"-"`
+}
@@ -0,0 +1,64 @@
+package entity
+
+// Роли пользователей
+const (
+	RoleReader    = "reader"
+	RoleSupporter = "supporter" // читатель с ранним доступом к главам
+	RoleUploader  = "uploader"
+	RoleModerator = "moderator"
+	RoleEditor    = "editor"
+	RoleAdmin     = "admin"
+)
+
+// Разрешения
+const (
+	PermissionMangaWrite         = "manga:write"
+	PermissionChapterUpload      = "chapter:upload"
+	PermissionCommentModerate    = "comment:moderate"
+	PermissionUserManage         = "user:manage"
+	PermissionAnalyticsManage    = "analytics:manage"
+	PermissionChapterEarlyAccess = "chapter:early_access"
+)
+
+// Role представляет роль с набором разрешений
+type Role struct {
+	Name        string   `json:"name" db:"name"`
+	Description string   `json:"description" db:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// Actor описывает пользователя, от имени которого выполняется операция
+type Actor struct {
+	UserID      int64    `json:"user_id"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// roleSystem роль внутренних операций; пользователям не назначается
+const roleSystem = "system"
+
+// SystemActor возвращает пользователя для внутренних операций без HTTP-запроса (фоновые обработчики).
+// Проверки доступа без пользователя в контексте запрещают операцию, поэтому такие вызовы выполняются от его имени
+func SystemActor() *Actor {
+	return &Actor{
+		Role: roleSystem,
+		Permissions: []string{
+			PermissionMangaWrite,
+			PermissionChapterUpload,
+			PermissionCommentModerate,
+			PermissionUserManage,
+			PermissionAnalyticsManage,
+			PermissionChapterEarlyAccess,
+		},
+	}
+}
+
+// HasPermission проверяет наличие разрешения у пользователя
+func (a *Actor) HasPermission(permission string) bool {
+	for _, p := range a.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы событий потока реального времени
+const (
+	StreamEventNotification    = "notification"
+	StreamEventChapterReleased = "chapter_released"
+	// StreamEventReset сообщает клиенту, что часть событий после Last-Event-ID уже недоступна
+	// и состояние нужно перезапросить через обычное API
+	StreamEventReset = "reset"
+)
+
+// StreamEvent представляет событие, доставляемое пользователям через SSE/WebSocket.
+// Идентификатор монотонно растет в пределах всех экземпляров приложения и используется для возобновления потока
+type StreamEvent struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	UserIDs   []int64         `json:"user_ids,omitempty"` // получатели; пусто — все авторизованные пользователи
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// IsFor проверяет, адресовано ли событие пользователю
+func (e *StreamEvent) IsFor(userID int64) bool {
+	if len(e.UserIDs) == 0 {
+		return true
+	}
+	for _, id := range e.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamNotificationData представляет данные события о новом уведомлении
+type StreamNotificationData struct {
+	Type      string `json:"type"` // тип уведомления, например new_chapter
+	MangaID   int64  `json:"manga_id"`
+	ChapterID *int64 `json:"chapter_id,omitempty"`
+}
+
+// StreamChapterData представляет данные события о выходе главы
+type StreamChapterData struct {
+	MangaID   int64   `json:"manga_id"`
+	ChapterID int64   `json:"chapter_id"`
+	Number    float64 `json:"number"`
+	Title     string  `json:"title,omitempty"`
+}
@@ -2,15 +2,53 @@ package entity
 
 import "time"
 
+// DeletedUserName отображаемое имя удаленного пользователя
+const DeletedUserName = "deleted user"
+
+// Статусы пользователей для фильтрации
+const (
+	UserStatusActive  = "active"
+	UserStatusBanned  = "banned"
+	UserStatusDeleted = "deleted"
+)
+
 // User представляет пользователя системы
 type User struct {
-	ID        int64     `json:"id" db:"id"`
-	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password_hash"` // Не возвращаем в JSON
-	Role      string    `json:"role" db:"role"`       // user, admin
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID            int64      `json:"id" db:"id"`
+	Username      string     `json:"username" db:"username"`
+	Email         string     `json:"email" db:"email"`
+	EmailVerified bool       `json:"email_verified" db:"email_verified"`
+	Password      string     `json:"-" db:"password_hash"`         // Не возвращаем в JSON
+	Role          string     `json:"role" db:"role"`               // reader, uploader, moderator, editor, admin
+	Permissions   []string   `json:"permissions,omitempty" db:"-"` // Заполняется при выдаче токенов
+	BanReason     string     `json:"ban_reason,omitempty" db:"ban_reason"`
+	BannedAt      *time.Time `json:"banned_at,omitempty" db:"banned_at"`
+	BannedUntil   *time.Time `json:"banned_until,omitempty" db:"banned_until"` // nil при BannedAt != nil — бессрочно
+	BannedBy      *int64     `json:"banned_by,omitempty" db:"banned_by"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsBanned проверяет, действует ли блокировка пользователя в указанный момент
+func (u *User) IsBanned(now time.Time) bool {
+	if u.BannedAt == nil {
+		return false
+	}
+	return u.BannedUntil == nil || u.BannedUntil.After(now)
+}
+
+// IsDeleted проверяет, удален ли пользователь
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// DisplayName возвращает имя пользователя для показа другим пользователям
+func (u *User) DisplayName() string {
+	if u.IsDeleted() {
+		return DeletedUserName
+	}
+	return u.Username
 }
 
 // UserCredentials представляет учетные данные для авторизации
@@ -25,3 +63,25 @@ type UserRegistration struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
 }
+
+// UserFilter представляет фильтры для поиска пользователей администратором
+type UserFilter struct {
+	Query         string     `json:"query,omitempty"` // Подстрока имени пользователя или email
+	Role          string     `json:"role,omitempty"`
+	Status        string     `json:"status,omitempty"` // active, banned, deleted; по умолчанию все, кроме удаленных
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	Limit         int        `json:"limit,omitempty"`
+	Offset        int        `json:"offset,omitempty"`
+}
+
+// UserAdminUpdate представляет изменения пользователя, доступные администратору
+type UserAdminUpdate struct {
+	Role string `json:"role"`
+}
+
+// UserBan представляет параметры блокировки пользователя
+type UserBan struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"` // Не указано — бессрочная блокировка
+}
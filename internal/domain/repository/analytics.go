@@ -13,9 +13,12 @@ type AnalyticsRepository interface {
 
 	GetMangaViews(ctx context.Context, mangaID int64) (int64, error)
 	GetChapterViews(ctx context.Context, chapterID int64) (int64, error)
+	GetChapterViewsBatch(ctx context.Context, chapterIDs []int64) (map[int64]int64, error)
 
 	GetTopManga(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.MangaStat, error)
 	GetTopChapters(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.ChapterStat, error)
 
+	GetTotals(ctx context.Context, period entity.StatsPeriod) (*entity.AnalyticsTotals, error)
+
 	ResetStats(ctx context.Context, period entity.StatsPeriod) error
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// APIKeyRepository определяет интерфейс для репозитория API ключей
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entity.APIKey) (int64, error)
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	ListByUser(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+	Revoke(ctx context.Context, id, userID int64) error
+	UpdateLastUsed(ctx context.Context, id int64) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// BookmarkRepository определяет интерфейс для репозитория закладок
+type BookmarkRepository interface {
+	Upsert(ctx context.Context, bookmark *entity.Bookmark) error
+	ListByUser(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]*entity.BookmarkWithManga, int, error)
+	Delete(ctx context.Context, userID, mangaID int64) error
+}
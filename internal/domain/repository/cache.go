@@ -12,10 +12,14 @@ type CacheRepository interface {
 	Set(ctx context.Context, key, value string, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	TTL(ctx context.Context, key string) (time.Duration, error)
 
 	// Операции со счетчиками
 	Incr(ctx context.Context, key string) (int64, error)
 	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+	// IncrWithTTL увеличивает счетчик и задает срок жизни, если он еще не задан
+	IncrWithTTL(ctx context.Context, key string, expiration time.Duration) (int64, error)
 
 	// Операции с отсортированными множествами (для рейтингов)
 	ZAdd(ctx context.Context, key string, score float64, member string) error
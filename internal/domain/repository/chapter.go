@@ -13,4 +13,10 @@ type ChapterRepository interface {
 	Update(ctx context.Context, chapter *entity.Chapter) error
 	Delete(ctx context.Context, id int64) error
 	DeleteByMangaID(ctx context.Context, mangaID int64) error
+	// PublishDue публикует запланированные главы, время публикации которых наступило
+	PublishDue(ctx context.Context) ([]*entity.Chapter, error)
+
+	// Тома
+	ListVolumes(ctx context.Context, mangaID int64) ([]*entity.VolumeInfo, error)
+	SetVolume(ctx context.Context, mangaID int64, number int, input entity.VolumeInput) (*entity.VolumeInfo, error)
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// ChapterReadRepository определяет интерфейс для репозитория прочитанных глав
+type ChapterReadRepository interface {
+	SetRead(ctx context.Context, userID int64, chapter *entity.Chapter, read bool) (bool, error)
+	SetRangeRead(ctx context.Context, userID, mangaID int64, rng entity.ChapterRange, read bool) (int, error)
+	ListReadChapterIDs(ctx context.Context, userID, mangaID int64) ([]int64, error)
+	CountUnread(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]int, error)
+}
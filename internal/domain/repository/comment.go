@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// CommentRepository определяет интерфейс для репозитория комментариев
+type CommentRepository interface {
+	Create(ctx context.Context, comment *entity.Comment) error
+	GetByID(ctx context.Context, id int64) (*entity.CommentWithUser, error)
+	List(ctx context.Context, filter entity.CommentFilter) ([]*entity.CommentWithUser, int, error)
+	Update(ctx context.Context, comment *entity.Comment, editedBy int64) error
+	Delete(ctx context.Context, id int64) error
+	SetHidden(ctx context.Context, id int64, hidden bool) (bool, error)
+
+	// Голоса
+	Vote(ctx context.Context, userID, commentID int64, value int) (*entity.CommentVoteResult, error)
+	ListUserVotes(ctx context.Context, userID int64, commentIDs []int64) (map[int64]int, error)
+
+	// История правок
+	ListEdits(ctx context.Context, commentID int64) ([]*entity.CommentEdit, error)
+}
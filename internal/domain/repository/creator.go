@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// CreatorRepository определяет интерфейс для репозитория авторов и их участия в манге
+type CreatorRepository interface {
+	Create(ctx context.Context, input entity.CreatorInput) (int64, error)
+	GetByID(ctx context.Context, id int64) (*entity.Creator, error)
+	List(ctx context.Context, filter entity.CreatorFilter) ([]*entity.Creator, int, error)
+	// Update изменяет автора и обновляет текстовые author/artist его манги
+	Update(ctx context.Context, id int64, input entity.CreatorInput) error
+	// Merge переносит участие в манге и варианты имени из sourceID в targetID и удаляет sourceID
+	Merge(ctx context.Context, targetID, sourceID int64) error
+
+	ListWorks(ctx context.Context, creatorID int64) ([]*entity.CreatorWork, error)
+	ListMangaCredits(ctx context.Context, mangaID int64) ([]*entity.MangaCredit, error)
+	// SetMangaCredits заменяет авторов манги. Авторы без ID ищутся по имени или создаются
+	SetMangaCredits(ctx context.Context, mangaID int64, credits []*entity.MangaCredit) error
+}
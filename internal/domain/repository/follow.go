@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// FollowRepository определяет интерфейс для репозитория подписок на мангу
+type FollowRepository interface {
+	Follow(ctx context.Context, follow *entity.MangaFollow) error
+	Unfollow(ctx context.Context, userID, mangaID int64) error
+	IsFollowing(ctx context.Context, userID, mangaID int64) (bool, error)
+	ListByUser(ctx context.Context, userID int64, filter entity.FollowFilter) ([]*entity.MangaFollowWithManga, int, error)
+}
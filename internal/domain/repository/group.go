@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// ScanlationGroupRepository определяет интерфейс для репозитория групп переводчиков
+type ScanlationGroupRepository interface {
+	Create(ctx context.Context, input entity.ScanlationGroupInput) (int64, error)
+	GetByID(ctx context.Context, id int64) (*entity.ScanlationGroup, error)
+	List(ctx context.Context, filter entity.ScanlationGroupFilter) ([]*entity.ScanlationGroup, int, error)
+	Update(ctx context.Context, id int64, input entity.ScanlationGroupInput) error
+	// ListReleases возвращает главы группы, начиная с последних
+	ListReleases(ctx context.Context, groupID int64, limit, offset int) ([]*entity.GroupRelease, int, error)
+	// ListChapterIDs возвращает ID всех глав группы, включая неопубликованные, по ID манги
+	ListChapterIDs(ctx context.Context, groupID int64) (map[int64][]int64, error)
+}
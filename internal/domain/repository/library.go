@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// LibraryRepository определяет интерфейс для репозитория библиотеки пользователя
+type LibraryRepository interface {
+	Get(ctx context.Context, userID, mangaID int64) (*entity.LibraryEntry, error)
+	Upsert(ctx context.Context, entry *entity.LibraryEntry) error
+	Delete(ctx context.Context, userID, mangaID int64) error
+	List(ctx context.Context, userID int64, filter entity.LibraryFilter) ([]*entity.LibraryEntryWithManga, int, error)
+	CountByStatus(ctx context.Context, userID int64) (map[string]int, error)
+
+	// Полки
+	ListShelves(ctx context.Context, userID int64) ([]*entity.LibraryShelf, error)
+	CreateShelf(ctx context.Context, shelf *entity.LibraryShelf) error
+	RenameShelf(ctx context.Context, shelf *entity.LibraryShelf) error
+	DeleteShelf(ctx context.Context, userID, shelfID int64) error
+	AddToShelf(ctx context.Context, userID, shelfID, mangaID int64) error
+	RemoveFromShelf(ctx context.Context, userID, shelfID, mangaID int64) error
+
+	// Импорт
+	MatchMangaByTitles(ctx context.Context, titles []string) (map[string]int64, error)
+	MatchMangaByMALIDs(ctx context.Context, malIDs []int64) (map[int64]int64, error)
+	Import(ctx context.Context, userID int64, items []*entity.LibraryImportItem) (imported, updated int, err error)
+}
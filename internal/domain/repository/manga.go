@@ -18,4 +18,9 @@ type MangaRepository interface {
 	AddGenreToManga(ctx context.Context, mangaID int64, genre string) error
 	RemoveGenreFromManga(ctx context.Context, mangaID int64, genre string) error
 	GetGenresForManga(ctx context.Context, mangaID int64) ([]string, error)
+
+	// Назначения загрузчиков
+	AssignUploader(ctx context.Context, mangaID, userID int64) error
+	UnassignUploader(ctx context.Context, mangaID, userID int64) error
+	IsUploaderAssigned(ctx context.Context, mangaID, userID int64) (bool, error)
 }
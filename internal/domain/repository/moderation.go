@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// ModerationRepository определяет интерфейс для репозитория жалоб и журнала модерации
+type ModerationRepository interface {
+	// Жалобы
+	CreateReport(ctx context.Context, report *entity.CommentReport) (int, error)
+	ListQueue(ctx context.Context, filter entity.ModerationQueueFilter) ([]*entity.ModerationQueueItem, int, error)
+	Apply(ctx context.Context, entry *entity.ModerationLogEntry, ban *entity.UserBan, reportStatus string) (bool, int, error)
+
+	// Журнал действий
+	LogAction(ctx context.Context, entry *entity.ModerationLogEntry) error
+	ListLog(ctx context.Context, filter entity.ModerationLogFilter) ([]*entity.ModerationLogEntry, int, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// NotificationRepository определяет интерфейс для репозитория уведомлений
+type NotificationRepository interface {
+	List(ctx context.Context, userID int64, filter entity.NotificationFilter) ([]*entity.NotificationItem, int, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int, error)
+
+	// Рассылка подписчикам
+	CreateJob(ctx context.Context, job *entity.NotificationJob) error
+	ProcessJobBatch(ctx context.Context, batchSize int) (*entity.NotificationJob, []int64, error)
+}
@@ -10,7 +10,11 @@ type PageRepository interface {
 	Create(ctx context.Context, page *entity.Page) (int64, error)
 	GetByID(ctx context.Context, id int64) (*entity.Page, error)
 	ListByChapter(ctx context.Context, chapterID int64) ([]*entity.Page, error)
+	CountByChapters(ctx context.Context, chapterIDs []int64) (map[int64]int, error)
 	Update(ctx context.Context, page *entity.Page) error
 	Delete(ctx context.Context, id int64) error
 	DeleteByChapterID(ctx context.Context, chapterID int64) error
+	Reorder(ctx context.Context, chapterID int64, pageIDs []int64) error
+	Insert(ctx context.Context, page *entity.Page) error
+	ReplaceAll(ctx context.Context, chapterID int64, imagePaths []string) ([]string, error)
 }
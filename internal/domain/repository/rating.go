@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// RatingRepository определяет интерфейс для репозитория оценок манги
+type RatingRepository interface {
+	Rate(ctx context.Context, userID, mangaID int64, score int) error
+	Remove(ctx context.Context, userID, mangaID int64) error
+	Get(ctx context.Context, userID, mangaID int64) (*entity.MangaRating, error)
+	Summary(ctx context.Context, mangaID int64) (*entity.MangaRatingSummary, error)
+
+	// Периодический пересчет среднего по каталогу и байесовских оценок
+	RefreshScores(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// ReadingHistoryRepository определяет интерфейс для репозитория истории чтения
+type ReadingHistoryRepository interface {
+	Upsert(ctx context.Context, entry *entity.ReadingHistoryEntry) error
+	ListByUser(ctx context.Context, userID int64, filter entity.ReadingHistoryFilter) ([]*entity.ReadingHistoryItem, int, error)
+	ListLatestPerManga(ctx context.Context, userID int64, limit int) ([]*entity.ReadingHistoryItem, error)
+	Delete(ctx context.Context, id, userID int64) error
+	DeleteAllByUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+	"time"
+)
+
+// ReadingProgressRepository определяет интерфейс для репозитория прогресса чтения
+type ReadingProgressRepository interface {
+	// Save сохраняет позицию, если она побеждает текущую по политике; возвращает, была ли позиция применена
+	Save(ctx context.Context, progress *entity.ReadingProgress, policy string) (bool, error)
+	Get(ctx context.Context, userID, mangaID int64) (*entity.ReadingProgress, error)
+	ListByUser(ctx context.Context, userID int64, since *time.Time) ([]*entity.ReadingProgress, error)
+}
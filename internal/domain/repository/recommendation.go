@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+	"time"
+)
+
+// RecommendationRepository определяет интерфейс для репозитория рекомендаций
+type RecommendationRepository interface {
+	// ListForUser подбирает мангу по похожести на прочитанную и жанровым предпочтениям,
+	// исключая уже прочитанную и добавленную в библиотеку
+	ListForUser(ctx context.Context, userID int64, limit int) ([]*entity.Recommendation, error)
+	// ListPopularForUser возвращает непрочитанную мангу в порядке popularIDs, затем по байесовской оценке
+	ListPopularForUser(ctx context.Context, userID int64, popularIDs []int64, limit int) ([]*entity.Recommendation, error)
+
+	// Периодический пересчет таблицы похожести. Пересчет пропускается, если таблица
+	// обновлялась после staleBefore (например, другим экземпляром приложения)
+	RebuildSimilarity(ctx context.Context, staleBefore time.Time) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// MangaRelationRepository определяет интерфейс для репозитория связей и похожей манги
+type MangaRelationRepository interface {
+	// Set создает или изменяет связь сразу в обе стороны: relatedID является relationType для mangaID,
+	// а mangaID — inverseType для relatedID
+	Set(ctx context.Context, mangaID, relatedID int64, relationType, inverseType string, createdBy int64) error
+	Delete(ctx context.Context, mangaID, relatedID int64) error
+	ListRelations(ctx context.Context, mangaID int64) ([]*entity.MangaRelation, error)
+
+	// ListSimilar подбирает мангу по пересечению жанров и совпадению автора или художника,
+	// исключая уже связанную вручную
+	ListSimilar(ctx context.Context, mangaID int64, limit int) ([]*entity.SimilarManga, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// ReviewRepository определяет интерфейс для репозитория рецензий
+type ReviewRepository interface {
+	Create(ctx context.Context, review *entity.Review) error
+	GetByID(ctx context.Context, id int64) (*entity.ReviewWithUser, error)
+	List(ctx context.Context, filter entity.ReviewFilter) ([]*entity.ReviewWithUser, int, error)
+	Update(ctx context.Context, review *entity.Review) error
+	Delete(ctx context.Context, id int64) error
+
+	// Оценки полезности
+	Vote(ctx context.Context, userID, reviewID int64, value int) (*entity.ReviewVoteResult, error)
+	ListUserVotes(ctx context.Context, userID int64, reviewIDs []int64) (map[int64]int, error)
+}
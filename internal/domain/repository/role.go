@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// RoleRepository определяет интерфейс для репозитория ролей и разрешений
+type RoleRepository interface {
+	List(ctx context.Context) ([]*entity.Role, error)
+	Exists(ctx context.Context, role string) (bool, error)
+	GetPermissions(ctx context.Context, role string) ([]string, error)
+	SetPermissions(ctx context.Context, role string, permissions []string) error
+	ListPermissions(ctx context.Context) ([]string, error)
+}
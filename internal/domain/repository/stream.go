@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// StreamRepository определяет интерфейс шины событий реального времени между экземплярами приложения
+type StreamRepository interface {
+	// Publish присваивает событию очередной ID, сохраняет его в журнале для возобновления и рассылает подписчикам
+	Publish(ctx context.Context, event *entity.StreamEvent) error
+	// Subscribe возвращает канал событий всех экземпляров; канал закрывается при отмене контекста
+	Subscribe(ctx context.Context) (<-chan *entity.StreamEvent, error)
+	// Since возвращает сохраненные события с ID больше afterID по возрастанию
+	// и признак того, что часть событий после afterID уже вытеснена из журнала
+	Since(ctx context.Context, afterID int64, limit int) ([]*entity.StreamEvent, bool, error)
+}
@@ -13,4 +13,14 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id int64) error
+
+	// Администрирование
+	List(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int, error)
+	Ban(ctx context.Context, id int64, ban *entity.UserBan, bannedBy int64) error
+	Unban(ctx context.Context, id int64) error
+	Anonymize(ctx context.Context, id int64) error
+
+	// Настройки
+	GetPreferredLanguages(ctx context.Context, id int64) ([]string, error)
+	SetPreferredLanguages(ctx context.Context, id int64, languages []string) error
 }
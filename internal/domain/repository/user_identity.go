@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"manga-reader2/internal/domain/entity"
+)
+
+// UserIdentityRepository определяет интерфейс для репозитория внешних учетных записей
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *entity.UserIdentity) (int64, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	ListByUser(ctx context.Context, userID int64) ([]*entity.UserIdentity, error)
+}
@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -20,9 +23,10 @@ type JWTService struct {
 
 // Claims содержит данные, которые будут сохранены в токене
 type Claims struct {
-	UserID   int64  `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID      int64    `json:"user_id"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -63,9 +67,10 @@ func (s *JWTService) GenerateTokenPair(user *entity.User) (*entity.TokenPair, er
 func (s *JWTService) GenerateAccessToken(user *entity.User) (string, error) {
 	expirationTime := time.Now().Add(s.accessExpires)
 	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
+		UserID:      user.ID,
+		Username:    user.Username,
+		Role:        user.Role,
+		Permissions: user.Permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -154,3 +159,31 @@ func (s *JWTService) RefreshTokens(refreshToken string, user *entity.User) (*ent
 
 	return s.GenerateTokenPair(user)
 }
+
+// SignPageImage подписывает ссылку на изображение страницы до момента expiresAt.
+// Подписанная ссылка заменяет токен доступа в query: тег <img> не умеет отправлять заголовки,
+// а токен в адресе попадал бы в журналы и историю браузера
+func (s *JWTService) SignPageImage(pageID int64, expiresAt time.Time) string {
+	return hex.EncodeToString(s.pageImageMAC(pageID, expiresAt.Unix()))
+}
+
+// VerifyPageImage проверяет подпись ссылки на изображение страницы и срок ее действия
+func (s *JWTService) VerifyPageImage(pageID, expiresAt int64, signature string) bool {
+	if time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	mac, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(mac, s.pageImageMAC(pageID, expiresAt))
+}
+
+// pageImageMAC вычисляет HMAC ссылки на изображение страницы на секрете access token
+func (s *JWTService) pageImageMAC(pageID, expiresAt int64) []byte {
+	mac := hmac.New(sha256.New, []byte(s.accessSecret))
+	fmt.Fprintf(mac, "page-image:%d:%d", pageID, expiresAt)
+	return mac.Sum(nil)
+}
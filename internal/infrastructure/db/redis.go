@@ -97,6 +97,11 @@ func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Du
 	return r.client.Expire(ctx, key, expiration).Result()
 }
 
+// TTL возвращает оставшееся время жизни ключа
+func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.client.TTL(ctx, key).Result()
+}
+
 // ZAdd добавляет элемент в отсортированное множество
 func (r *RedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
 	z := redis.Z{
@@ -111,6 +116,12 @@ func (r *RedisClient) ZIncrBy(ctx context.Context, key string, increment float64
 	return r.client.ZIncrBy(ctx, key, increment, member).Result()
 }
 
+// ZScore возвращает score элемента отсортированного множества.
+// Для отсутствующего элемента возвращается ошибка redis.Nil
+func (r *RedisClient) ZScore(ctx context.Context, key, member string) (float64, error) {
+	return r.client.ZScore(ctx, key, member).Result()
+}
+
 // ZRevRange возвращает элементы из отсортированного множества в обратном порядке
 func (r *RedisClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
 	return r.client.ZRevRange(ctx, key, start, stop).Result()
@@ -121,6 +132,28 @@ func (r *RedisClient) ZRevRangeWithScores(ctx context.Context, key string, start
 	return r.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
 }
 
+// ZRangeWithScores возвращает элементы с их оценками из отсортированного множества по возрастанию
+func (r *RedisClient) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	return r.client.ZRangeWithScores(ctx, key, start, stop).Result()
+}
+
+// ZRangeByScore возвращает не более count элементов с оценкой в диапазоне [min, max] по возрастанию.
+// Границы задаются в формате Redis, например "(10" для исключающей границы и "+inf"
+func (r *RedisClient) ZRangeByScore(ctx context.Context, key, min, max string, count int64) ([]string, error) {
+	return r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max, Count: count}).Result()
+}
+
+// ZRemRangeByRank удаляет элементы отсортированного множества в диапазоне позиций
+func (r *RedisClient) ZRemRangeByRank(ctx context.Context, key string, start, stop int64) error {
+	return r.client.ZRemRangeByRank(ctx, key, start, stop).Err()
+}
+
+// Pipelined выполняет команды, добавленные в fn, одним обменом с сервером
+func (r *RedisClient) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) error {
+	_, err := r.client.Pipelined(ctx, fn)
+	return err
+}
+
 // Scan сканирует ключи по шаблону
 func (r *RedisClient) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
 	return r.client.Scan(ctx, cursor, match, count).Result()
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// APIKeyRepository реализация интерфейса repository.APIKeyRepository для PostgreSQL
+type APIKeyRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewAPIKeyRepository создает новый экземпляр APIKeyRepository
+func NewAPIKeyRepository(db *sqlx.DB, log logger.Logger) repository.APIKeyRepository {
+	return &APIKeyRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+// Create сохраняет новый API ключ
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) (int64, error) {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+
+	if err != nil {
+		r.log.Error("Ошибка создания API ключа", "error", err.Error(), "user_id", key.UserID)
+		return 0, errors.NewDatabaseError("Ошибка создания API ключа", err)
+	}
+
+	return key.ID, nil
+}
+
+// GetByHash получает API ключ по хешу
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	key, err := scanAPIKey(r.db.QueryRowxContext(ctx, query, keyHash))
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("API ключ не найден", nil)
+		}
+		r.log.Error("Ошибка получения API ключа", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка получения API ключа", err)
+	}
+
+	return key, nil
+}
+
+// ListByUser получает список API ключей пользователя
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryxContext(ctx, query, userID)
+	if err != nil {
+		r.log.Error("Ошибка получения списка API ключей", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения списка API ключей", err)
+	}
+	defer rows.Close()
+
+	keys := []*entity.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			r.log.Error("Ошибка сканирования API ключа", "error", err.Error(), "user_id", userID)
+			return nil, errors.NewDatabaseError("Ошибка получения списка API ключей", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Error("Ошибка получения списка API ключей", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения списка API ключей", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke отзывает API ключ пользователя
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, userID int64) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		r.log.Error("Ошибка отзыва API ключа", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка отзыва API ключа", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества обновленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка отзыва API ключа", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("API ключ не найден", nil)
+	}
+
+	return nil
+}
+
+// UpdateLastUsed обновляет время последнего использования API ключа
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id int64) error {
+	query := "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1"
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		r.log.Error("Ошибка обновления времени использования API ключа", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка обновления API ключа", err)
+	}
+
+	return nil
+}
+
+// rowScanner общий интерфейс для *sqlx.Row и *sqlx.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAPIKey сканирует строку результата в entity.APIKey
+func scanAPIKey(row rowScanner) (*entity.APIKey, error) {
+	var key entity.APIKey
+	var scopes pq.StringArray
+
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = []string(scopes)
+
+	return &key, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// BookmarkRepository реализация интерфейса repository.BookmarkRepository для PostgreSQL
+type BookmarkRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewBookmarkRepository создает новый экземпляр BookmarkRepository
+func NewBookmarkRepository(db *sqlx.DB, log logger.Logger) repository.BookmarkRepository {
+	return &BookmarkRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Upsert добавляет закладку или обновляет последнюю прочитанную главу существующей
+func (r *BookmarkRepository) Upsert(ctx context.Context, bookmark *entity.Bookmark) error {
+	query := `
+		INSERT INTO bookmarks (user_id, manga_id, chapter_id, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (user_id, manga_id) DO UPDATE
+		SET chapter_id = EXCLUDED.chapter_id, updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, bookmark.UserID, bookmark.MangaID, bookmark.ChapterID).
+		Scan(&bookmark.ID, &bookmark.CreatedAt, &bookmark.UpdatedAt)
+	if err != nil {
+		r.log.Error("Ошибка сохранения закладки", "error", err.Error(), "user_id", bookmark.UserID, "manga_id", bookmark.MangaID)
+		return errors.NewDatabaseError("Ошибка сохранения закладки", err)
+	}
+
+	return nil
+}
+
+// ListByUser возвращает страницу закладок пользователя с данными манги и общее количество закладок
+func (r *BookmarkRepository) ListByUser(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]*entity.BookmarkWithManga, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM bookmarks WHERE user_id = $1", userID); err != nil {
+		r.log.Error("Ошибка подсчета закладок", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения закладок", err)
+	}
+
+	orderBy := "b.created_at DESC, b.id DESC"
+	if filter.Sort == entity.BookmarkSortUpdated {
+		orderBy = "manga_updated_at DESC, b.id DESC"
+	}
+
+	query := `
+		SELECT b.id, b.user_id, b.manga_id, b.chapter_id, b.created_at, b.updated_at,
+		       m.title AS manga_title,
+		       COALESCE(m.cover_image, '') AS manga_cover_image,
+		       m.status AS manga_status,
+		       c.number AS chapter_number,
+		       latest.max_number AS latest_chapter_number,
+		       (
+		           SELECT COUNT(DISTINCT ch.number) FROM chapters ch
+		           WHERE ch.manga_id = b.manga_id AND ch.status = 'published' AND (c.number IS NULL OR ch.number > c.number)
+		       ) AS unread_chapters,
+		       GREATEST(m.updated_at, COALESCE(latest.last_chapter_at, m.updated_at)) AS manga_updated_at
+		FROM bookmarks b
+		JOIN manga m ON m.id = b.manga_id
+		LEFT JOIN chapters c ON c.id = b.chapter_id
+		LEFT JOIN LATERAL (
+		    SELECT MAX(number) AS max_number, MAX(created_at) AS last_chapter_at
+		    FROM chapters
+		    WHERE manga_id = b.manga_id AND status = 'published'
+		) latest ON TRUE
+		WHERE b.user_id = $1
+		ORDER BY ` + orderBy + `
+		LIMIT $2 OFFSET $3
+	`
+
+	var bookmarks []*entity.BookmarkWithManga
+	if err := r.db.SelectContext(ctx, &bookmarks, query, userID, filter.Limit, filter.Offset); err != nil {
+		r.log.Error("Ошибка получения закладок", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения закладок", err)
+	}
+
+	return bookmarks, total, nil
+}
+
+// Delete удаляет закладку пользователя на мангу
+func (r *BookmarkRepository) Delete(ctx context.Context, userID, mangaID int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM bookmarks WHERE user_id = $1 AND manga_id = $2", userID, mangaID)
+	if err != nil {
+		r.log.Error("Ошибка удаления закладки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка удаления закладки", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества удаленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления закладки", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Закладка не найдена", nil)
+	}
+
+	return nil
+}
@@ -12,6 +12,29 @@ import (
 	"time"
 )
 
+// chapterColumns перечисляет поля главы вместе с названиями тома и группы
+const chapterColumns = `
+	c.id, c.manga_id, c.number, c.title, c.volume, COALESCE(v.title, '') AS volume_title,
+	c.language, c.group_id, COALESCE(g.name, '') AS group_name,
+	c.status, c.publish_at, c.published_at, c.early_access_until,
+	c.comment_count, c.created_at, c.updated_at`
+
+// chapterJoins присоединяет к главе ее том и группу
+const chapterJoins = `
+	LEFT JOIN manga_volumes v ON v.manga_id = c.manga_id AND v.number = c.volume
+	LEFT JOIN scanlation_groups g ON g.id = c.group_id`
+
+// chapterErrorFor переводит нарушения ограничений главы в ошибки запроса
+func chapterErrorFor(err error) error {
+	if isUniqueViolation(err) {
+		return errors.NewConflictError("Глава с таким номером уже есть в этом переводе", err)
+	}
+	if isForeignKeyViolation(err) {
+		return errors.NewValidationError("Группа переводчиков не найдена", nil)
+	}
+	return nil
+}
+
 // ChapterRepository реализация интерфейса repository.ChapterRepository для PostgreSQL
 type ChapterRepository struct {
 	db  *sqlx.DB
@@ -29,8 +52,9 @@ func NewChapterRepository(db *sqlx.DB, log logger.Logger) repository.ChapterRepo
 // Create создает новую главу в базе данных
 func (r *ChapterRepository) Create(ctx context.Context, chapter *entity.Chapter) (int64, error) {
 	query := `
-		INSERT INTO chapters (manga_id, number, title, created_at, updated_at)
-		VALUES ($1, $2, $3, NOW(), NOW())
+		INSERT INTO chapters (manga_id, number, title, volume, language, group_id,
+		                      status, publish_at, published_at, early_access_until, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::varchar, $8, CASE WHEN $7::varchar = 'published' THEN NOW() END, $9, NOW(), NOW())
 		RETURNING id, created_at, updated_at
 	`
 
@@ -43,9 +67,18 @@ func (r *ChapterRepository) Create(ctx context.Context, chapter *entity.Chapter)
 		chapter.MangaID,
 		chapter.Number,
 		chapter.Title,
+		chapter.Volume,
+		chapter.Language,
+		chapter.GroupID,
+		chapter.Status,
+		chapter.PublishAt,
+		chapter.EarlyAccessUntil,
 	).Scan(&id, &createdAt, &updatedAt)
 
 	if err != nil {
+		if constraintErr := chapterErrorFor(err); constraintErr != nil {
+			return 0, constraintErr
+		}
 		r.log.Error("Ошибка создания главы", "error", err.Error())
 		return 0, errors.NewDatabaseError("Ошибка создания главы", err)
 	}
@@ -60,9 +93,9 @@ func (r *ChapterRepository) Create(ctx context.Context, chapter *entity.Chapter)
 // GetByID получает главу по идентификатору
 func (r *ChapterRepository) GetByID(ctx context.Context, id int64) (*entity.Chapter, error) {
 	query := `
-		SELECT id, manga_id, number, title, created_at, updated_at
-		FROM chapters
-		WHERE id = $1
+		SELECT ` + chapterColumns + `
+		FROM chapters c` + chapterJoins + `
+		WHERE c.id = $1
 	`
 
 	var chapter entity.Chapter
@@ -79,13 +112,14 @@ func (r *ChapterRepository) GetByID(ctx context.Context, id int64) (*entity.Chap
 	return &chapter, nil
 }
 
-// ListByManga получает список глав для манги
+// ListByManga получает список глав для манги во всех переводах.
+// Главы упорядочены по тому и номеру; главы без тома идут последними
 func (r *ChapterRepository) ListByManga(ctx context.Context, mangaID int64) ([]*entity.Chapter, error) {
 	query := `
-		SELECT id, manga_id, number, title, created_at, updated_at
-		FROM chapters
-		WHERE manga_id = $1
-		ORDER BY number
+		SELECT ` + chapterColumns + `
+		FROM chapters c` + chapterJoins + `
+		WHERE c.manga_id = $1
+		ORDER BY c.volume NULLS LAST, c.number, c.language, g.name NULLS FIRST, c.id
 	`
 
 	var chapters []*entity.Chapter
@@ -103,8 +137,11 @@ func (r *ChapterRepository) ListByManga(ctx context.Context, mangaID int64) ([]*
 func (r *ChapterRepository) Update(ctx context.Context, chapter *entity.Chapter) error {
 	query := `
 		UPDATE chapters 
-		SET number = $1, title = $2, updated_at = NOW()
-		WHERE id = $3
+		SET number = $1, title = $2, volume = $3, language = $4, group_id = $5,
+		    status = $6::varchar, publish_at = $7, early_access_until = $8,
+		    published_at = CASE WHEN $6::varchar = 'published' THEN COALESCE(published_at, NOW()) END,
+		    updated_at = NOW()
+		WHERE id = $9
 		RETURNING updated_at
 	`
 
@@ -114,10 +151,19 @@ func (r *ChapterRepository) Update(ctx context.Context, chapter *entity.Chapter)
 		query,
 		chapter.Number,
 		chapter.Title,
+		chapter.Volume,
+		chapter.Language,
+		chapter.GroupID,
+		chapter.Status,
+		chapter.PublishAt,
+		chapter.EarlyAccessUntil,
 		chapter.ID,
 	)
 
 	if err != nil {
+		if constraintErr := chapterErrorFor(err); constraintErr != nil {
+			return constraintErr
+		}
 		r.log.Error("Ошибка обновления главы", "error", err.Error(), "id", chapter.ID)
 		return errors.NewDatabaseError("Ошибка обновления главы", err)
 	}
@@ -172,3 +218,60 @@ func (r *ChapterRepository) DeleteByMangaID(ctx context.Context, mangaID int64)
 
 	return nil
 }
+
+// PublishDue публикует запланированные главы, время которых наступило, и возвращает их.
+// Каждая глава возвращается ровно одному вызову, даже если планировщик запущен на нескольких серверах
+func (r *ChapterRepository) PublishDue(ctx context.Context) ([]*entity.Chapter, error) {
+	query := `
+		UPDATE chapters
+		SET status = 'published', published_at = NOW(), updated_at = NOW()
+		WHERE status = 'scheduled' AND publish_at <= NOW()
+		RETURNING id, manga_id, number, title, volume, language, group_id,
+		          status, publish_at, published_at, early_access_until, comment_count, created_at, updated_at
+	`
+
+	chapters := []*entity.Chapter{}
+	if err := r.db.SelectContext(ctx, &chapters, query); err != nil {
+		r.log.Error("Ошибка публикации запланированных глав", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка публикации запланированных глав", err)
+	}
+
+	return chapters, nil
+}
+
+// ListVolumes возвращает названия и обложки томов манги
+func (r *ChapterRepository) ListVolumes(ctx context.Context, mangaID int64) ([]*entity.VolumeInfo, error) {
+	query := `
+		SELECT manga_id, number, title, cover_image, updated_at
+		FROM manga_volumes
+		WHERE manga_id = $1
+		ORDER BY number
+	`
+
+	volumes := []*entity.VolumeInfo{}
+	if err := r.db.SelectContext(ctx, &volumes, query, mangaID); err != nil {
+		r.log.Error("Ошибка получения томов", "error", err.Error(), "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения томов", err)
+	}
+
+	return volumes, nil
+}
+
+// SetVolume создает или изменяет название и обложку тома
+func (r *ChapterRepository) SetVolume(ctx context.Context, mangaID int64, number int, input entity.VolumeInput) (*entity.VolumeInfo, error) {
+	query := `
+		INSERT INTO manga_volumes (manga_id, number, title, cover_image, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (manga_id, number) DO UPDATE
+		SET title = EXCLUDED.title, cover_image = EXCLUDED.cover_image, updated_at = NOW()
+		RETURNING manga_id, number, title, cover_image, updated_at
+	`
+
+	volume := &entity.VolumeInfo{}
+	if err := r.db.GetContext(ctx, volume, query, mangaID, number, input.Title, input.CoverImage); err != nil {
+		r.log.Error("Ошибка сохранения тома", "error", err.Error(), "manga_id", mangaID, "number", number)
+		return nil, errors.NewDatabaseError("Ошибка сохранения тома", err)
+	}
+
+	return volume, nil
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// ChapterReadRepository реализация интерфейса repository.ChapterReadRepository для PostgreSQL
+type ChapterReadRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewChapterReadRepository создает новый экземпляр ChapterReadRepository
+func NewChapterReadRepository(db *sqlx.DB, log logger.Logger) repository.ChapterReadRepository {
+	return &ChapterReadRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// SetRead отмечает главу прочитанной или непрочитанной. Возвращает true, если отметка изменилась
+func (r *ChapterReadRepository) SetRead(ctx context.Context, userID int64, chapter *entity.Chapter, read bool) (bool, error) {
+	query := `
+		INSERT INTO chapter_reads (user_id, chapter_id, manga_id, read_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, chapter_id) DO NOTHING
+	`
+	args := []interface{}{userID, chapter.ID, chapter.MangaID}
+	if !read {
+		query = "DELETE FROM chapter_reads WHERE user_id = $1 AND chapter_id = $2"
+		args = args[:2]
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.log.Error("Ошибка изменения отметки о прочтении", "error", err.Error(), "user_id", userID, "chapter_id", chapter.ID)
+		return false, errors.NewDatabaseError("Ошибка изменения отметки о прочтении", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка изменения отметки о прочтении", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SetRangeRead отмечает прочитанными или непрочитанными главы манги с номерами в диапазоне.
+// Границы сравниваются как NUMERIC, чтобы главы вида 10.5 попадали в диапазон точно
+func (r *ChapterReadRepository) SetRangeRead(ctx context.Context, userID, mangaID int64, rng entity.ChapterRange, read bool) (int, error) {
+	query := `
+		INSERT INTO chapter_reads (user_id, chapter_id, manga_id, read_at)
+		SELECT $1, c.id, c.manga_id, NOW()
+		FROM chapters c
+		WHERE c.manga_id = $2 AND c.status = 'published'
+		  AND ($3::numeric IS NULL OR c.number >= $3::numeric)
+		  AND ($4::numeric IS NULL OR c.number <= $4::numeric)
+		ON CONFLICT (user_id, chapter_id) DO NOTHING
+	`
+	if !read {
+		query = `
+			DELETE FROM chapter_reads cr
+			USING chapters c
+			WHERE cr.chapter_id = c.id
+			  AND cr.user_id = $1
+			  AND c.manga_id = $2
+			  AND ($3::numeric IS NULL OR c.number >= $3::numeric)
+			  AND ($4::numeric IS NULL OR c.number <= $4::numeric)
+		`
+	}
+
+	result, err := r.db.ExecContext(ctx, query, userID, mangaID, rng.From, rng.To)
+	if err != nil {
+		r.log.Error("Ошибка изменения отметок о прочтении", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return 0, errors.NewDatabaseError("Ошибка изменения отметок о прочтении", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка изменения отметок о прочтении", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ListReadChapterIDs возвращает ID прочитанных пользователем глав манги
+func (r *ChapterReadRepository) ListReadChapterIDs(ctx context.Context, userID, mangaID int64) ([]int64, error) {
+	var ids []int64
+	query := "SELECT chapter_id FROM chapter_reads WHERE user_id = $1 AND manga_id = $2"
+	if err := r.db.SelectContext(ctx, &ids, query, userID, mangaID); err != nil {
+		r.log.Error("Ошибка получения прочитанных глав", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения прочитанных глав", err)
+	}
+
+	return ids, nil
+}
+
+// CountUnread возвращает количество непрочитанных пользователем глав по каждой манге.
+// Переводы одной главы считаются одной главой: она прочитана, если прочитан любой перевод.
+// Манга без глав в результат не попадает
+func (r *ChapterReadRepository) CountUnread(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(mangaIDs))
+	if len(mangaIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT manga_id, COUNT(*) FILTER (WHERE NOT read) AS unread
+		FROM (
+			SELECT c.manga_id, c.number, BOOL_OR(cr.chapter_id IS NOT NULL) AS read
+			FROM chapters c
+			LEFT JOIN chapter_reads cr ON cr.chapter_id = c.id AND cr.user_id = $1
+			WHERE c.manga_id = ANY($2) AND c.status = 'published'
+			GROUP BY c.manga_id, c.number
+		) numbers
+		GROUP BY manga_id
+	`
+
+	var rows []struct {
+		MangaID int64 `db:"manga_id"`
+		Unread  int   `db:"unread"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, userID, pq.Array(mangaIDs)); err != nil {
+		r.log.Error("Ошибка подсчета непрочитанных глав", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка подсчета непрочитанных глав", err)
+	}
+
+	for _, row := range rows {
+		counts[row.MangaID] = row.Unread
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,459 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+)
+
+// commentSortOrders сопоставляет сортировку комментариев с выражением ORDER BY
+var commentSortOrders = map[string]string{
+	entity.CommentSortNewest: "c.created_at DESC, c.id DESC",
+	entity.CommentSortOldest: "c.created_at ASC, c.id ASC",
+	entity.CommentSortTop:    "c.score DESC, c.reply_count DESC, c.created_at DESC, c.id DESC",
+}
+
+// commentColumns перечисляет поля комментария с автором для выборок
+const commentColumns = `
+	c.id, c.user_id, c.manga_id, c.chapter_id, c.parent_id, c.content, c.content_html, c.spoiler,
+	c.reply_count, c.upvotes, c.downvotes, c.score, c.report_count, c.edited_at, c.hidden_at, c.deleted_at,
+	c.created_at, c.updated_at, u.username`
+
+// CommentRepository реализация интерфейса repository.CommentRepository для PostgreSQL.
+// Счетчики ответов и комментариев манги/главы обновляются в той же транзакции, что и сам комментарий,
+// и учитывают только видимые комментарии: скрытие уменьшает их так же, как удаление
+type CommentRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewCommentRepository создает новый экземпляр CommentRepository
+func NewCommentRepository(db *sqlx.DB, log logger.Logger) repository.CommentRepository {
+	return &CommentRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create создает комментарий и увеличивает счетчики родителя и объекта
+func (r *CommentRepository) Create(ctx context.Context, comment *entity.Comment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка создания комментария", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO comments (user_id, manga_id, chapter_id, parent_id, content, content_html, spoiler, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowxContext(ctx, query,
+		comment.UserID, comment.MangaID, comment.ChapterID, comment.ParentID,
+		comment.Content, comment.ContentHTML, comment.Spoiler,
+	).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		r.log.Error("Ошибка создания комментария", "error", err.Error(), "user_id", comment.UserID)
+		return errors.NewDatabaseError("Ошибка создания комментария", err)
+	}
+
+	if err = adjustCommentCounters(ctx, tx, r.log, comment, 1); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка создания комментария", err)
+	}
+
+	return nil
+}
+
+// GetByID получает комментарий с автором по идентификатору
+func (r *CommentRepository) GetByID(ctx context.Context, id int64) (*entity.CommentWithUser, error) {
+	query := "SELECT " + commentColumns + " FROM comments c JOIN users u ON u.id = c.user_id WHERE c.id = $1"
+
+	var comment entity.CommentWithUser
+	if err := r.db.GetContext(ctx, &comment, query, id); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Комментарий не найден", nil)
+		}
+		r.log.Error("Ошибка получения комментария", "error", err.Error(), "id", id)
+		return nil, errors.NewDatabaseError("Ошибка получения комментария", err)
+	}
+
+	return &comment, nil
+}
+
+// List возвращает страницу комментариев объекта или ответов на комментарий и их общее количество.
+// Удаленные и скрытые комментарии остаются в выдаче, только если на них есть ответы;
+// с IncludeHidden скрытые комментарии выдаются всегда
+func (r *CommentRepository) List(ctx context.Context, filter entity.CommentFilter) ([]*entity.CommentWithUser, int, error) {
+	where := []string{"((c.deleted_at IS NULL AND c.hidden_at IS NULL) OR c.reply_count > 0)"}
+	if filter.IncludeHidden {
+		where[0] = "(c.deleted_at IS NULL OR c.reply_count > 0)"
+	}
+	var args []interface{}
+
+	switch {
+	case filter.ParentID != nil:
+		args = append(args, *filter.ParentID)
+		where = append(where, "c.parent_id = $1")
+	case filter.MangaID != nil:
+		args = append(args, *filter.MangaID)
+		where = append(where, "c.parent_id IS NULL", "c.manga_id = $1")
+	case filter.ChapterID != nil:
+		args = append(args, *filter.ChapterID)
+		where = append(where, "c.parent_id IS NULL", "c.chapter_id = $1")
+	default:
+		return nil, 0, errors.NewValidationError("Не указан объект комментариев", nil)
+	}
+
+	whereClause := "WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM comments c "+whereClause, args...); err != nil {
+		r.log.Error("Ошибка подсчета комментариев", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения комментариев", err)
+	}
+
+	orderBy, ok := commentSortOrders[filter.Sort]
+	if !ok {
+		orderBy = commentSortOrders[entity.CommentSortNewest]
+	}
+
+	query := "SELECT " + commentColumns + `
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		` + whereClause + `
+		ORDER BY ` + orderBy + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var comments []*entity.CommentWithUser
+	if err := r.db.SelectContext(ctx, &comments, query, args...); err != nil {
+		r.log.Error("Ошибка получения комментариев", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения комментариев", err)
+	}
+
+	return comments, total, nil
+}
+
+// Update сохраняет предыдущую версию текста в историю правок и обновляет комментарий
+func (r *CommentRepository) Update(ctx context.Context, comment *entity.Comment, editedBy int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка редактирования комментария", err)
+	}
+	defer tx.Rollback()
+
+	historyQuery := `
+		INSERT INTO comment_edits (comment_id, content, edited_by, edited_at)
+		SELECT id, content, $2, NOW() FROM comments
+		WHERE id = $1 AND deleted_at IS NULL AND content <> $3
+	`
+	if _, err = tx.ExecContext(ctx, historyQuery, comment.ID, editedBy, comment.Content); err != nil {
+		r.log.Error("Ошибка сохранения истории правок", "error", err.Error(), "id", comment.ID)
+		return errors.NewDatabaseError("Ошибка редактирования комментария", err)
+	}
+
+	query := `
+		UPDATE comments
+		SET content = $2,
+		    content_html = $3,
+		    spoiler = $4,
+		    edited_at = CASE WHEN content <> $2 THEN NOW() ELSE edited_at END,
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING edited_at, updated_at
+	`
+	err = tx.QueryRowxContext(ctx, query, comment.ID, comment.Content, comment.ContentHTML, comment.Spoiler).
+		Scan(&comment.EditedAt, &comment.UpdatedAt)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Комментарий не найден", nil)
+		}
+		r.log.Error("Ошибка редактирования комментария", "error", err.Error(), "id", comment.ID)
+		return errors.NewDatabaseError("Ошибка редактирования комментария", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка редактирования комментария", err)
+	}
+
+	return nil
+}
+
+// Delete мягко удаляет комментарий: текст и история правок стираются, а место в ветке сохраняется для ответов.
+// Счетчики родителя и объекта уменьшаются, если комментарий не был скрыт ранее
+func (r *CommentRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления комментария", err)
+	}
+	defer tx.Rollback()
+
+	if err = deleteComment(ctx, tx, r.log, id); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления комментария", err)
+	}
+
+	return nil
+}
+
+// SetHidden скрывает комментарий или возвращает его в выдачу и пересчитывает счетчики.
+// Возвращает false, если комментарий уже находился в нужном состоянии
+func (r *CommentRepository) SetHidden(ctx context.Context, id int64, hidden bool) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка скрытия комментария", err)
+	}
+	defer tx.Rollback()
+
+	changed, err := setCommentHidden(ctx, tx, r.log, id, hidden)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка скрытия комментария", err)
+	}
+
+	return true, nil
+}
+
+// Vote сохраняет голос пользователя (1 или -1, 0 снимает голос) и инкрементально обновляет рейтинг.
+// Строка комментария блокируется, поэтому одновременные голоса не искажают счетчики
+func (r *CommentRepository) Vote(ctx context.Context, userID, commentID int64, value int) (*entity.CommentVoteResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка голосования", err)
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	lockQuery := "SELECT id FROM comments WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	if err = tx.GetContext(ctx, &locked, lockQuery, commentID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Комментарий не найден", nil)
+		}
+		r.log.Error("Ошибка блокировки комментария", "error", err.Error(), "comment_id", commentID)
+		return nil, errors.NewDatabaseError("Ошибка голосования", err)
+	}
+
+	var previous int
+	err = tx.GetContext(ctx, &previous, "SELECT value FROM comment_votes WHERE user_id = $1 AND comment_id = $2", userID, commentID)
+	if err != nil && !stderrors.Is(err, sql.ErrNoRows) {
+		r.log.Error("Ошибка получения голоса", "error", err.Error(), "user_id", userID, "comment_id", commentID)
+		return nil, errors.NewDatabaseError("Ошибка голосования", err)
+	}
+
+	if value == 0 {
+		_, err = tx.ExecContext(ctx, "DELETE FROM comment_votes WHERE user_id = $1 AND comment_id = $2", userID, commentID)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO comment_votes (user_id, comment_id, value, created_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (user_id, comment_id) DO UPDATE SET value = EXCLUDED.value, created_at = NOW()
+		`, userID, commentID, value)
+	}
+	if err != nil {
+		r.log.Error("Ошибка сохранения голоса", "error", err.Error(), "user_id", userID, "comment_id", commentID)
+		return nil, errors.NewDatabaseError("Ошибка голосования", err)
+	}
+
+	upDelta := voteCount(value, 1) - voteCount(previous, 1)
+	downDelta := voteCount(value, -1) - voteCount(previous, -1)
+
+	query := `
+		UPDATE comments
+		SET upvotes = upvotes + $2, downvotes = downvotes + $3, score = score + $2 - $3
+		WHERE id = $1
+		RETURNING id, upvotes, downvotes, score
+	`
+	result := &entity.CommentVoteResult{MyVote: value}
+	if err = tx.QueryRowxContext(ctx, query, commentID, upDelta, downDelta).StructScan(result); err != nil {
+		r.log.Error("Ошибка обновления рейтинга комментария", "error", err.Error(), "comment_id", commentID)
+		return nil, errors.NewDatabaseError("Ошибка голосования", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка голосования", err)
+	}
+
+	return result, nil
+}
+
+// ListUserVotes возвращает голоса пользователя за перечисленные комментарии
+func (r *CommentRepository) ListUserVotes(ctx context.Context, userID int64, commentIDs []int64) (map[int64]int, error) {
+	votes := make(map[int64]int)
+	if len(commentIDs) == 0 {
+		return votes, nil
+	}
+
+	query := "SELECT comment_id, value FROM comment_votes WHERE user_id = $1 AND comment_id = ANY($2)"
+	rows, err := r.db.QueryxContext(ctx, query, userID, pq.Array(commentIDs))
+	if err != nil {
+		r.log.Error("Ошибка получения голосов пользователя", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения голосов", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID int64
+		var value int
+		if err = rows.Scan(&commentID, &value); err != nil {
+			r.log.Error("Ошибка чтения голоса", "error", err.Error(), "user_id", userID)
+			return nil, errors.NewDatabaseError("Ошибка получения голосов", err)
+		}
+		votes[commentID] = value
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error("Ошибка получения голосов пользователя", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения голосов", err)
+	}
+
+	return votes, nil
+}
+
+// ListEdits возвращает историю правок комментария от старых версий к новым
+func (r *CommentRepository) ListEdits(ctx context.Context, commentID int64) ([]*entity.CommentEdit, error) {
+	query := `
+		SELECT id, comment_id, content, edited_by, edited_at
+		FROM comment_edits
+		WHERE comment_id = $1
+		ORDER BY edited_at ASC, id ASC
+	`
+
+	var edits []*entity.CommentEdit
+	if err := r.db.SelectContext(ctx, &edits, query, commentID); err != nil {
+		r.log.Error("Ошибка получения истории правок", "error", err.Error(), "comment_id", commentID)
+		return nil, errors.NewDatabaseError("Ошибка получения истории правок", err)
+	}
+
+	return edits, nil
+}
+
+// voteCount возвращает 1, если голос совпадает с направлением, иначе 0
+func voteCount(vote, direction int) int {
+	if vote == direction {
+		return 1
+	}
+	return 0
+}
+
+// adjustCommentCounters изменяет счетчик ответов родителя и счетчик комментариев манги или главы на delta
+func adjustCommentCounters(ctx context.Context, tx *sqlx.Tx, log logger.Logger, comment *entity.Comment, delta int) error {
+	var queries []string
+	var ids []int64
+
+	if comment.ParentID != nil {
+		queries = append(queries, "UPDATE comments SET reply_count = reply_count + $2 WHERE id = $1")
+		ids = append(ids, *comment.ParentID)
+	}
+	if comment.MangaID != nil {
+		queries = append(queries, "UPDATE manga SET comment_count = comment_count + $2 WHERE id = $1")
+		ids = append(ids, *comment.MangaID)
+	}
+	if comment.ChapterID != nil {
+		queries = append(queries, "UPDATE chapters SET comment_count = comment_count + $2 WHERE id = $1")
+		ids = append(ids, *comment.ChapterID)
+	}
+
+	for i, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, ids[i], delta); err != nil {
+			log.Error("Ошибка обновления счетчиков комментариев", "error", err.Error(), "comment_id", comment.ID)
+			return errors.NewDatabaseError("Ошибка обновления счетчиков комментариев", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteComment удаляет комментарий в транзакции tx: очищает текст, удаляет историю правок
+// и уменьшает счетчики, если комментарий не был скрыт
+func deleteComment(ctx context.Context, tx *sqlx.Tx, log logger.Logger, id int64) error {
+	query := `
+		UPDATE comments
+		SET content = '', content_html = '', spoiler = FALSE, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING manga_id, chapter_id, parent_id, hidden_at
+	`
+	comment := entity.Comment{ID: id}
+	err := tx.QueryRowxContext(ctx, query, id).Scan(&comment.MangaID, &comment.ChapterID, &comment.ParentID, &comment.HiddenAt)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Комментарий не найден", nil)
+		}
+		log.Error("Ошибка удаления комментария", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка удаления комментария", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM comment_edits WHERE comment_id = $1", id); err != nil {
+		log.Error("Ошибка удаления истории правок", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка удаления комментария", err)
+	}
+
+	if comment.HiddenAt == nil {
+		if err = adjustCommentCounters(ctx, tx, log, &comment, -1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setCommentHidden скрывает комментарий или возвращает его в выдачу в транзакции tx и пересчитывает счетчики.
+// Возвращает false, если комментарий уже находился в нужном состоянии
+func setCommentHidden(ctx context.Context, tx *sqlx.Tx, log logger.Logger, id int64, hidden bool) (bool, error) {
+	query := `
+		UPDATE comments
+		SET hidden_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL
+		RETURNING manga_id, chapter_id, parent_id
+	`
+	delta := -1
+	if !hidden {
+		query = `
+			UPDATE comments
+			SET hidden_at = NULL, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NOT NULL
+			RETURNING manga_id, chapter_id, parent_id
+		`
+		delta = 1
+	}
+
+	comment := entity.Comment{ID: id}
+	if err := tx.QueryRowxContext(ctx, query, id).Scan(&comment.MangaID, &comment.ChapterID, &comment.ParentID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		log.Error("Ошибка скрытия комментария", "error", err.Error(), "id", id, "hidden", hidden)
+		return false, errors.NewDatabaseError("Ошибка скрытия комментария", err)
+	}
+
+	if err := adjustCommentCounters(ctx, tx, log, &comment, delta); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
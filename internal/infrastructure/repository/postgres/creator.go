@@ -0,0 +1,422 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// creatorMangaTextUpdate пересчитывает текстовые author и artist манги по ее авторам
+const creatorMangaTextUpdate = `
+	UPDATE manga m
+	SET author = LEFT(COALESCE((
+	        SELECT string_agg(c.name, ', ' ORDER BY mc.position, c.name)
+	        FROM manga_creators mc JOIN creators c ON c.id = mc.creator_id
+	        WHERE mc.manga_id = m.id AND mc.role = 'author'
+	    ), ''), 100),
+	    artist = LEFT(COALESCE((
+	        SELECT string_agg(c.name, ', ' ORDER BY mc.position, c.name)
+	        FROM manga_creators mc JOIN creators c ON c.id = mc.creator_id
+	        WHERE mc.manga_id = m.id AND mc.role = 'artist'
+	    ), ''), 100)
+	WHERE m.id = ANY($1)
+`
+
+// CreatorRepository реализация интерфейса repository.CreatorRepository для PostgreSQL.
+// Имена сопоставляются по ключу creator_name_key (см. миграцию 000017), поэтому
+// варианты с другим порядком слов или регистром находят того же автора
+type CreatorRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewCreatorRepository создает новый экземпляр CreatorRepository
+func NewCreatorRepository(db *sqlx.DB, log logger.Logger) repository.CreatorRepository {
+	return &CreatorRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create создает автора с вариантами имени
+func (r *CreatorRepository) Create(ctx context.Context, input entity.CreatorInput) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка создания автора", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	query := "INSERT INTO creators (name, bio, created_at, updated_at) VALUES ($1, $2, NOW(), NOW()) RETURNING id"
+	if err = tx.GetContext(ctx, &id, query, input.Name, input.Bio); err != nil {
+		r.log.Error("Ошибка создания автора", "error", err.Error(), "name", input.Name)
+		return 0, errors.NewDatabaseError("Ошибка создания автора", err)
+	}
+
+	if err = r.replaceAliases(ctx, tx, id, input); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка создания автора", err)
+	}
+
+	return id, nil
+}
+
+// GetByID получает автора с вариантами имени
+func (r *CreatorRepository) GetByID(ctx context.Context, id int64) (*entity.Creator, error) {
+	creator := &entity.Creator{}
+	err := r.db.GetContext(ctx, creator, "SELECT id, name, bio, created_at, updated_at FROM creators WHERE id = $1", id)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Автор не найден", err)
+		}
+		r.log.Error("Ошибка получения автора", "error", err.Error(), "id", id)
+		return nil, errors.NewDatabaseError("Ошибка получения автора", err)
+	}
+
+	if err = r.fillAliases(ctx, []*entity.Creator{creator}); err != nil {
+		return nil, err
+	}
+
+	return creator, nil
+}
+
+// List ищет авторов по любому варианту имени
+func (r *CreatorRepository) List(ctx context.Context, filter entity.CreatorFilter) ([]*entity.Creator, int, error) {
+	where := "TRUE"
+	args := []interface{}{}
+	if filter.Query != "" {
+		where = "EXISTS (SELECT 1 FROM creator_aliases a WHERE a.creator_id = creators.id AND a.alias ILIKE $1)"
+		args = append(args, "%"+filter.Query+"%")
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM creators WHERE "+where, args...); err != nil {
+		r.log.Error("Ошибка подсчета авторов", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения авторов", err)
+	}
+
+	query := fmt.Sprintf("SELECT id, name, bio, created_at, updated_at FROM creators WHERE %s ORDER BY name, id LIMIT $%d OFFSET $%d",
+		where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	creators := []*entity.Creator{}
+	if err := r.db.SelectContext(ctx, &creators, query, args...); err != nil {
+		r.log.Error("Ошибка получения авторов", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения авторов", err)
+	}
+
+	if err := r.fillAliases(ctx, creators); err != nil {
+		return nil, 0, err
+	}
+
+	return creators, total, nil
+}
+
+// Update изменяет имя, описание и варианты имени автора
+func (r *CreatorRepository) Update(ctx context.Context, id int64, input entity.CreatorInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка обновления автора", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE creators SET name = $1, bio = $2, updated_at = NOW() WHERE id = $3", input.Name, input.Bio, id)
+	if err != nil {
+		r.log.Error("Ошибка обновления автора", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка обновления автора", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		if err != nil {
+			return errors.NewDatabaseError("Ошибка обновления автора", err)
+		}
+		return errors.NewNotFoundError("Автор не найден", nil)
+	}
+
+	if err = r.replaceAliases(ctx, tx, id, input); err != nil {
+		return err
+	}
+
+	if err = r.refreshMangaText(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка обновления автора", err)
+	}
+
+	return nil
+}
+
+// Merge объединяет дубликат sourceID с автором targetID. Описание дубликата сохраняется,
+// если у автора его нет
+func (r *CreatorRepository) Merge(ctx context.Context, targetID, sourceID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка объединения авторов", err)
+	}
+	defer tx.Rollback()
+
+	var found int
+	if err = tx.GetContext(ctx, &found, "SELECT COUNT(*) FROM (SELECT id FROM creators WHERE id IN ($1, $2) FOR UPDATE) c", targetID, sourceID); err != nil {
+		r.log.Error("Ошибка блокировки авторов", "error", err.Error(), "target_id", targetID, "source_id", sourceID)
+		return errors.NewDatabaseError("Ошибка объединения авторов", err)
+	}
+	if found != 2 {
+		return errors.NewNotFoundError("Автор не найден", nil)
+	}
+
+	statements := []string{
+		`INSERT INTO manga_creators (manga_id, creator_id, role, position)
+		 SELECT manga_id, $1, role, position FROM manga_creators WHERE creator_id = $2
+		 ON CONFLICT (manga_id, creator_id, role) DO NOTHING`,
+		`INSERT INTO creator_aliases (creator_id, alias, name_key)
+		 SELECT $1, alias, name_key FROM creator_aliases WHERE creator_id = $2
+		 ON CONFLICT (creator_id, alias) DO NOTHING`,
+		`UPDATE creators
+		 SET bio = CASE WHEN bio = '' THEN (SELECT bio FROM creators WHERE id = $2) ELSE bio END, updated_at = NOW()
+		 WHERE id = $1`,
+		`DELETE FROM creators WHERE id = $2 AND id <> $1`,
+	}
+	for _, statement := range statements {
+		if _, err = tx.ExecContext(ctx, statement, targetID, sourceID); err != nil {
+			r.log.Error("Ошибка объединения авторов", "error", err.Error(), "target_id", targetID, "source_id", sourceID)
+			return errors.NewDatabaseError("Ошибка объединения авторов", err)
+		}
+	}
+
+	if err = r.refreshMangaText(ctx, tx, targetID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка объединения авторов", err)
+	}
+
+	return nil
+}
+
+// creatorWorkRow представляет строку библиографии с ролями автора
+type creatorWorkRow struct {
+	entity.CreatorWork
+	Roles pq.StringArray `db:"roles"`
+}
+
+// ListWorks возвращает мангу автора с его ролями, начиная с новой
+func (r *CreatorRepository) ListWorks(ctx context.Context, creatorID int64) ([]*entity.CreatorWork, error) {
+	query := `
+		SELECT m.id AS manga_id, m.title, COALESCE(m.cover_image, '') AS cover_image, m.status,
+		       m.bayesian_score, m.created_at,
+		       array_agg(mc.role ORDER BY mc.role) AS roles
+		FROM manga_creators mc
+		JOIN manga m ON m.id = mc.manga_id
+		WHERE mc.creator_id = $1
+		GROUP BY m.id
+		ORDER BY m.created_at DESC, m.id DESC
+	`
+
+	var rows []*creatorWorkRow
+	if err := r.db.SelectContext(ctx, &rows, query, creatorID); err != nil {
+		r.log.Error("Ошибка получения библиографии автора", "error", err.Error(), "creator_id", creatorID)
+		return nil, errors.NewDatabaseError("Ошибка получения библиографии автора", err)
+	}
+
+	works := make([]*entity.CreatorWork, 0, len(rows))
+	for _, row := range rows {
+		work := row.CreatorWork
+		work.Roles = row.Roles
+		works = append(works, &work)
+	}
+
+	return works, nil
+}
+
+// ListMangaCredits возвращает авторов манги по ролям в заданном порядке
+func (r *CreatorRepository) ListMangaCredits(ctx context.Context, mangaID int64) ([]*entity.MangaCredit, error) {
+	query := `
+		SELECT mc.creator_id, c.name, mc.role
+		FROM manga_creators mc
+		JOIN creators c ON c.id = mc.creator_id
+		WHERE mc.manga_id = $1
+		ORDER BY mc.role, mc.position, c.name
+	`
+
+	credits := []*entity.MangaCredit{}
+	if err := r.db.SelectContext(ctx, &credits, query, mangaID); err != nil {
+		r.log.Error("Ошибка получения авторов манги", "error", err.Error(), "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения авторов манги", err)
+	}
+
+	return credits, nil
+}
+
+// SetMangaCredits заменяет авторов манги и обновляет ее текстовые author и artist
+func (r *CreatorRepository) SetMangaCredits(ctx context.Context, mangaID int64, credits []*entity.MangaCredit) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM manga_creators WHERE manga_id = $1", mangaID); err != nil {
+		r.log.Error("Ошибка удаления авторов манги", "error", err.Error(), "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+
+	positions := make(map[string]int)
+	for _, credit := range credits {
+		creatorID := credit.CreatorID
+		if creatorID == 0 {
+			if creatorID, err = r.resolveByName(ctx, tx, credit.Name); err != nil {
+				return err
+			}
+		}
+
+		query := `
+			INSERT INTO manga_creators (manga_id, creator_id, role, position)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (manga_id, creator_id, role) DO NOTHING
+		`
+		if _, err = tx.ExecContext(ctx, query, mangaID, creatorID, credit.Role, positions[credit.Role]); err != nil {
+			if isForeignKeyViolation(err) {
+				return errors.NewNotFoundError("Автор не найден", err)
+			}
+			r.log.Error("Ошибка сохранения автора манги", "error", err.Error(), "manga_id", mangaID, "creator_id", creatorID)
+			return errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+		}
+		positions[credit.Role]++
+	}
+
+	if _, err = tx.ExecContext(ctx, creatorMangaTextUpdate, pq.Int64Array{mangaID}); err != nil {
+		r.log.Error("Ошибка обновления авторов манги", "error", err.Error(), "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+
+	return nil
+}
+
+// resolveByName находит автора по любому варианту имени или создает нового.
+// Блокировка по ключу имени не дает параллельным запросам создать двух одинаковых авторов
+func (r *CreatorRepository) resolveByName(ctx context.Context, tx *sqlx.Tx, name string) (int64, error) {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(creator_name_key($1)))", name); err != nil {
+		r.log.Error("Ошибка блокировки имени автора", "error", err.Error(), "name", name)
+		return 0, errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+
+	var id int64
+	query := "SELECT creator_id FROM creator_aliases WHERE name_key = creator_name_key($1) ORDER BY creator_id LIMIT 1"
+	err := tx.GetContext(ctx, &id, query, name)
+	if err == nil {
+		return id, nil
+	}
+	if !stderrors.Is(err, sql.ErrNoRows) {
+		r.log.Error("Ошибка поиска автора по имени", "error", err.Error(), "name", name)
+		return 0, errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+
+	if err = tx.GetContext(ctx, &id, "INSERT INTO creators (name, created_at, updated_at) VALUES ($1, NOW(), NOW()) RETURNING id", name); err != nil {
+		r.log.Error("Ошибка создания автора", "error", err.Error(), "name", name)
+		return 0, errors.NewDatabaseError("Ошибка сохранения авторов манги", err)
+	}
+	if err = r.replaceAliases(ctx, tx, id, entity.CreatorInput{Name: name}); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// replaceAliases заменяет варианты имени автора; основное имя всегда входит в них
+func (r *CreatorRepository) replaceAliases(ctx context.Context, tx *sqlx.Tx, id int64, input entity.CreatorInput) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM creator_aliases WHERE creator_id = $1", id); err != nil {
+		r.log.Error("Ошибка удаления вариантов имени", "error", err.Error(), "creator_id", id)
+		return errors.NewDatabaseError("Ошибка сохранения вариантов имени", err)
+	}
+
+	query := `
+		INSERT INTO creator_aliases (creator_id, alias, name_key)
+		SELECT $1, alias, creator_name_key(alias) FROM unnest($2::text[]) AS alias
+		ON CONFLICT (creator_id, alias) DO NOTHING
+	`
+	aliases := append([]string{input.Name}, input.Aliases...)
+	if _, err := tx.ExecContext(ctx, query, id, pq.StringArray(aliases)); err != nil {
+		r.log.Error("Ошибка сохранения вариантов имени", "error", err.Error(), "creator_id", id)
+		return errors.NewDatabaseError("Ошибка сохранения вариантов имени", err)
+	}
+
+	return nil
+}
+
+// refreshMangaText обновляет текстовые author и artist всей манги автора
+func (r *CreatorRepository) refreshMangaText(ctx context.Context, tx *sqlx.Tx, creatorID int64) error {
+	var mangaIDs pq.Int64Array
+	if err := tx.GetContext(ctx, &mangaIDs, "SELECT COALESCE(array_agg(DISTINCT manga_id), '{}') FROM manga_creators WHERE creator_id = $1", creatorID); err != nil {
+		r.log.Error("Ошибка получения манги автора", "error", err.Error(), "creator_id", creatorID)
+		return errors.NewDatabaseError("Ошибка обновления манги автора", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, creatorMangaTextUpdate, mangaIDs); err != nil {
+		r.log.Error("Ошибка обновления манги автора", "error", err.Error(), "creator_id", creatorID)
+		return errors.NewDatabaseError("Ошибка обновления манги автора", err)
+	}
+
+	return nil
+}
+
+// fillAliases загружает варианты имени для списка авторов одним запросом
+func (r *CreatorRepository) fillAliases(ctx context.Context, creators []*entity.Creator) error {
+	if len(creators) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(creators))
+	byID := make(map[int64]*entity.Creator, len(creators))
+	for _, creator := range creators {
+		creator.Aliases = []string{}
+		ids = append(ids, creator.ID)
+		byID[creator.ID] = creator
+	}
+
+	var rows []struct {
+		CreatorID int64  `db:"creator_id"`
+		Alias     string `db:"alias"`
+	}
+	query := "SELECT creator_id, alias FROM creator_aliases WHERE creator_id = ANY($1) ORDER BY creator_id, alias"
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Int64Array(ids)); err != nil {
+		r.log.Error("Ошибка получения вариантов имени", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка получения вариантов имени", err)
+	}
+
+	for _, row := range rows {
+		creator := byID[row.CreatorID]
+		creator.Aliases = append(creator.Aliases, row.Alias)
+	}
+
+	return nil
+}
+
+// isForeignKeyViolation проверяет, является ли ошибка нарушением внешнего ключа
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == "23503"
+}
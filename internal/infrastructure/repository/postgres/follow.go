@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// FollowRepository реализация интерфейса repository.FollowRepository для PostgreSQL
+type FollowRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewFollowRepository создает новый экземпляр FollowRepository
+func NewFollowRepository(db *sqlx.DB, log logger.Logger) repository.FollowRepository {
+	return &FollowRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Follow подписывает пользователя на мангу; повторная подписка сохраняет исходное время
+func (r *FollowRepository) Follow(ctx context.Context, follow *entity.MangaFollow) error {
+	query := `
+		INSERT INTO manga_follows (user_id, manga_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, manga_id) DO UPDATE SET created_at = manga_follows.created_at
+		RETURNING created_at
+	`
+
+	if err := r.db.QueryRowxContext(ctx, query, follow.UserID, follow.MangaID).Scan(&follow.CreatedAt); err != nil {
+		r.log.Error("Ошибка подписки на мангу", "error", err.Error(), "user_id", follow.UserID, "manga_id", follow.MangaID)
+		return errors.NewDatabaseError("Ошибка подписки на мангу", err)
+	}
+
+	return nil
+}
+
+// Unfollow отменяет подписку пользователя на мангу
+func (r *FollowRepository) Unfollow(ctx context.Context, userID, mangaID int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM manga_follows WHERE user_id = $1 AND manga_id = $2", userID, mangaID)
+	if err != nil {
+		r.log.Error("Ошибка отмены подписки на мангу", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка отмены подписки на мангу", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества удаленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка отмены подписки на мангу", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Подписка не найдена", nil)
+	}
+
+	return nil
+}
+
+// IsFollowing проверяет, подписан ли пользователь на мангу
+func (r *FollowRepository) IsFollowing(ctx context.Context, userID, mangaID int64) (bool, error) {
+	var following bool
+	query := "SELECT EXISTS (SELECT 1 FROM manga_follows WHERE user_id = $1 AND manga_id = $2)"
+	if err := r.db.GetContext(ctx, &following, query, userID, mangaID); err != nil {
+		r.log.Error("Ошибка проверки подписки на мангу", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return false, errors.NewDatabaseError("Ошибка проверки подписки на мангу", err)
+	}
+
+	return following, nil
+}
+
+// ListByUser возвращает страницу подписок пользователя с данными манги и общее количество подписок
+func (r *FollowRepository) ListByUser(ctx context.Context, userID int64, filter entity.FollowFilter) ([]*entity.MangaFollowWithManga, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM manga_follows WHERE user_id = $1", userID); err != nil {
+		r.log.Error("Ошибка подсчета подписок", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения подписок", err)
+	}
+
+	query := `
+		SELECT f.user_id, f.manga_id, f.created_at,
+		       m.title AS manga_title,
+		       COALESCE(m.cover_image, '') AS manga_cover_image,
+		       m.status AS manga_status
+		FROM manga_follows f
+		JOIN manga m ON m.id = f.manga_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC, f.manga_id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var follows []*entity.MangaFollowWithManga
+	if err := r.db.SelectContext(ctx, &follows, query, userID, filter.Limit, filter.Offset); err != nil {
+		r.log.Error("Ошибка получения подписок", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения подписок", err)
+	}
+
+	return follows, total, nil
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// groupColumns перечисляет поля группы с числом ее глав
+const groupColumns = `
+	g.id, g.name, g.description, g.website, g.created_at, g.updated_at,
+	(SELECT COUNT(*) FROM chapters c WHERE c.group_id = g.id AND c.status = 'published') AS chapter_count`
+
+// ScanlationGroupRepository реализация интерфейса repository.ScanlationGroupRepository для PostgreSQL
+type ScanlationGroupRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewScanlationGroupRepository создает новый экземпляр ScanlationGroupRepository
+func NewScanlationGroupRepository(db *sqlx.DB, log logger.Logger) repository.ScanlationGroupRepository {
+	return &ScanlationGroupRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create создает группу переводчиков
+func (r *ScanlationGroupRepository) Create(ctx context.Context, input entity.ScanlationGroupInput) (int64, error) {
+	query := `
+		INSERT INTO scanlation_groups (name, description, website, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING id
+	`
+
+	var id int64
+	if err := r.db.QueryRowxContext(ctx, query, input.Name, input.Description, input.Website).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return 0, errors.NewConflictError("Группа с таким названием уже существует", err)
+		}
+		r.log.Error("Ошибка создания группы", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка создания группы", err)
+	}
+
+	return id, nil
+}
+
+// GetByID получает группу по идентификатору
+func (r *ScanlationGroupRepository) GetByID(ctx context.Context, id int64) (*entity.ScanlationGroup, error) {
+	query := "SELECT " + groupColumns + " FROM scanlation_groups g WHERE g.id = $1"
+
+	group := &entity.ScanlationGroup{}
+	if err := r.db.GetContext(ctx, group, query, id); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError(fmt.Sprintf("Группа с ID %d не найдена", id), nil)
+		}
+		r.log.Error("Ошибка получения группы", "error", err.Error(), "id", id)
+		return nil, errors.NewDatabaseError("Ошибка получения группы", err)
+	}
+
+	return group, nil
+}
+
+// List ищет группы по названию
+func (r *ScanlationGroupRepository) List(ctx context.Context, filter entity.ScanlationGroupFilter) ([]*entity.ScanlationGroup, int, error) {
+	where := "TRUE"
+	args := []interface{}{}
+	if filter.Query != "" {
+		where = "g.name ILIKE $1"
+		args = append(args, "%"+filter.Query+"%")
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM scanlation_groups g WHERE "+where, args...); err != nil {
+		r.log.Error("Ошибка подсчета групп", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения групп", err)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM scanlation_groups g WHERE %s ORDER BY g.name, g.id LIMIT $%d OFFSET $%d",
+		groupColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	groups := []*entity.ScanlationGroup{}
+	if err := r.db.SelectContext(ctx, &groups, query, args...); err != nil {
+		r.log.Error("Ошибка получения групп", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения групп", err)
+	}
+
+	return groups, total, nil
+}
+
+// Update изменяет группу
+func (r *ScanlationGroupRepository) Update(ctx context.Context, id int64, input entity.ScanlationGroupInput) error {
+	query := `
+		UPDATE scanlation_groups
+		SET name = $1, description = $2, website = $3, updated_at = NOW()
+		WHERE id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, input.Name, input.Description, input.Website, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Группа с таким названием уже существует", err)
+		}
+		r.log.Error("Ошибка обновления группы", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка обновления группы", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества обновленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка обновления группы", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError(fmt.Sprintf("Группа с ID %d не найдена", id), nil)
+	}
+
+	return nil
+}
+
+// ListReleases возвращает главы группы, начиная с последних
+func (r *ScanlationGroupRepository) ListReleases(ctx context.Context, groupID int64, limit, offset int) ([]*entity.GroupRelease, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM chapters WHERE group_id = $1 AND status = 'published'", groupID); err != nil {
+		r.log.Error("Ошибка подсчета глав группы", "error", err.Error(), "group_id", groupID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения глав группы", err)
+	}
+
+	query := `
+		SELECT c.id AS chapter_id, c.manga_id, m.title AS manga_title, c.number, c.title, c.language, c.published_at AS created_at
+		FROM chapters c
+		JOIN manga m ON m.id = c.manga_id
+		WHERE c.group_id = $1 AND c.status = 'published'
+		ORDER BY c.published_at DESC, c.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	releases := []*entity.GroupRelease{}
+	if err := r.db.SelectContext(ctx, &releases, query, groupID, limit, offset); err != nil {
+		r.log.Error("Ошибка получения глав группы", "error", err.Error(), "group_id", groupID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения глав группы", err)
+	}
+
+	return releases, total, nil
+}
+
+// ListChapterIDs возвращает ID всех глав группы, включая неопубликованные, по ID манги
+func (r *ScanlationGroupRepository) ListChapterIDs(ctx context.Context, groupID int64) (map[int64][]int64, error) {
+	var rows []struct {
+		ID      int64 `db:"id"`
+		MangaID int64 `db:"manga_id"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, "SELECT id, manga_id FROM chapters WHERE group_id = $1", groupID); err != nil {
+		r.log.Error("Ошибка получения глав группы", "error", err.Error(), "group_id", groupID)
+		return nil, errors.NewDatabaseError("Ошибка получения глав группы", err)
+	}
+
+	chapterIDs := make(map[int64][]int64)
+	for _, row := range rows {
+		chapterIDs[row.MangaID] = append(chapterIDs[row.MangaID], row.ID)
+	}
+
+	return chapterIDs, nil
+}
@@ -0,0 +1,513 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+)
+
+// LibraryRepository реализация интерфейса repository.LibraryRepository для PostgreSQL
+type LibraryRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewLibraryRepository создает новый экземпляр LibraryRepository
+func NewLibraryRepository(db *sqlx.DB, log logger.Logger) repository.LibraryRepository {
+	return &LibraryRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// librarySortOrders соответствие вариантов сортировки выражениям ORDER BY
+var librarySortOrders = map[string]string{
+	entity.LibrarySortUpdated: "e.updated_at DESC, e.id DESC",
+	entity.LibrarySortAdded:   "e.created_at DESC, e.id DESC",
+	entity.LibrarySortTitle:   "m.title ASC, e.id ASC",
+	entity.LibrarySortScore:   "e.score DESC NULLS LAST, e.updated_at DESC, e.id DESC",
+}
+
+// libraryEntryRow строка выборки библиотеки с массивом полок
+type libraryEntryRow struct {
+	entity.LibraryEntryWithManga
+	Shelves pq.Int64Array `db:"shelf_ids"`
+}
+
+// Get получает запись библиотеки пользователя по манге
+func (r *LibraryRepository) Get(ctx context.Context, userID, mangaID int64) (*entity.LibraryEntry, error) {
+	query := `
+		SELECT id, user_id, manga_id, status, score, notes, started_at, finished_at, mal_id, created_at, updated_at
+		FROM library_entries
+		WHERE user_id = $1 AND manga_id = $2
+	`
+
+	var entry entity.LibraryEntry
+	if err := r.db.GetContext(ctx, &entry, query, userID, mangaID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Манга не добавлена в библиотеку", nil)
+		}
+		r.log.Error("Ошибка получения записи библиотеки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения записи библиотеки", err)
+	}
+
+	return &entry, nil
+}
+
+// Upsert добавляет мангу в библиотеку или заменяет существующую запись
+func (r *LibraryRepository) Upsert(ctx context.Context, entry *entity.LibraryEntry) error {
+	query := `
+		INSERT INTO library_entries (user_id, manga_id, status, score, notes, started_at, finished_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (user_id, manga_id) DO UPDATE
+		SET status = EXCLUDED.status,
+		    score = EXCLUDED.score,
+		    notes = EXCLUDED.notes,
+		    started_at = EXCLUDED.started_at,
+		    finished_at = EXCLUDED.finished_at,
+		    updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		entry.UserID,
+		entry.MangaID,
+		entry.Status,
+		entry.Score,
+		entry.Notes,
+		entry.StartedAt,
+		entry.FinishedAt,
+	).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		r.log.Error("Ошибка сохранения записи библиотеки", "error", err.Error(), "user_id", entry.UserID, "manga_id", entry.MangaID)
+		return errors.NewDatabaseError("Ошибка сохранения записи библиотеки", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет мангу из библиотеки пользователя
+func (r *LibraryRepository) Delete(ctx context.Context, userID, mangaID int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM library_entries WHERE user_id = $1 AND manga_id = $2", userID, mangaID)
+	if err != nil {
+		r.log.Error("Ошибка удаления записи библиотеки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка удаления записи библиотеки", err)
+	}
+
+	return r.checkAffected(result, "Манга не добавлена в библиотеку")
+}
+
+// List возвращает страницу библиотеки пользователя с данными манги и общее количество записей
+func (r *LibraryRepository) List(ctx context.Context, userID int64, filter entity.LibraryFilter) ([]*entity.LibraryEntryWithManga, int, error) {
+	where := []string{"e.user_id = $1"}
+	args := []interface{}{userID}
+	argIndex := 2
+
+	if filter.Status != "" {
+		where = append(where, fmt.Sprintf("e.status = $%d", argIndex))
+		args = append(args, filter.Status)
+		argIndex++
+	}
+
+	if filter.ShelfID != nil {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM library_shelf_entries se WHERE se.entry_id = e.id AND se.shelf_id = $%d)", argIndex))
+		args = append(args, *filter.ShelfID)
+		argIndex++
+	}
+
+	if filter.Query != "" {
+		where = append(where, fmt.Sprintf("m.title ILIKE $%d", argIndex))
+		args = append(args, "%"+filter.Query+"%")
+		argIndex++
+	}
+
+	whereClause := "WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM library_entries e JOIN manga m ON m.id = e.manga_id " + whereClause
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		r.log.Error("Ошибка подсчета записей библиотеки", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения библиотеки", err)
+	}
+
+	orderBy, ok := librarySortOrders[filter.Sort]
+	if !ok {
+		orderBy = librarySortOrders[entity.LibrarySortUpdated]
+	}
+
+	query := `
+		SELECT e.id, e.user_id, e.manga_id, e.status, e.score, e.notes, e.started_at, e.finished_at,
+		       COALESCE(m.mal_id, e.mal_id) AS mal_id, e.created_at, e.updated_at,
+		       m.title AS manga_title,
+		       COALESCE(m.cover_image, '') AS manga_cover_image,
+		       m.status AS manga_status,
+		       (
+		           SELECT COUNT(DISTINCT c.number) FROM chapters c
+		           WHERE c.manga_id = e.manga_id AND c.status = 'published'
+		       ) AS total_chapters,
+		       (
+		           SELECT COUNT(DISTINCT c.number) FROM chapter_reads cr
+		           JOIN chapters c ON c.id = cr.chapter_id
+		           WHERE cr.user_id = e.user_id AND cr.manga_id = e.manga_id AND c.status = 'published'
+		       ) AS chapters_read,
+		       ARRAY(
+		           SELECT se.shelf_id FROM library_shelf_entries se
+		           WHERE se.entry_id = e.id ORDER BY se.shelf_id
+		       ) AS shelf_ids
+		FROM library_entries e
+		JOIN manga m ON m.id = e.manga_id
+		` + whereClause + `
+		ORDER BY ` + orderBy
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	var rows []*libraryEntryRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		r.log.Error("Ошибка получения библиотеки", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения библиотеки", err)
+	}
+
+	entries := make([]*entity.LibraryEntryWithManga, 0, len(rows))
+	for _, row := range rows {
+		entry := row.LibraryEntryWithManga
+		entry.ShelfIDs = []int64(row.Shelves)
+		entries = append(entries, &entry)
+	}
+
+	return entries, total, nil
+}
+
+// CountByStatus возвращает количество записей библиотеки пользователя по статусам
+func (r *LibraryRepository) CountByStatus(ctx context.Context, userID int64) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+
+	query := "SELECT status, COUNT(*) AS count FROM library_entries WHERE user_id = $1 GROUP BY status"
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		r.log.Error("Ошибка подсчета записей библиотеки", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения статистики библиотеки", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// ListShelves возвращает полки пользователя с количеством записей
+func (r *LibraryRepository) ListShelves(ctx context.Context, userID int64) ([]*entity.LibraryShelf, error) {
+	query := `
+		SELECT s.id, s.user_id, s.name, s.created_at,
+		       (SELECT COUNT(*) FROM library_shelf_entries se WHERE se.shelf_id = s.id) AS entry_count
+		FROM library_shelves s
+		WHERE s.user_id = $1
+		ORDER BY s.name ASC, s.id ASC
+	`
+
+	var shelves []*entity.LibraryShelf
+	if err := r.db.SelectContext(ctx, &shelves, query, userID); err != nil {
+		r.log.Error("Ошибка получения полок", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения полок", err)
+	}
+
+	return shelves, nil
+}
+
+// CreateShelf создает полку пользователя
+func (r *LibraryRepository) CreateShelf(ctx context.Context, shelf *entity.LibraryShelf) error {
+	query := `
+		INSERT INTO library_shelves (user_id, name, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, created_at
+	`
+
+	if err := r.db.QueryRowxContext(ctx, query, shelf.UserID, shelf.Name).Scan(&shelf.ID, &shelf.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Полка с таким названием уже существует", nil)
+		}
+		r.log.Error("Ошибка создания полки", "error", err.Error(), "user_id", shelf.UserID)
+		return errors.NewDatabaseError("Ошибка создания полки", err)
+	}
+
+	return nil
+}
+
+// RenameShelf переименовывает полку пользователя
+func (r *LibraryRepository) RenameShelf(ctx context.Context, shelf *entity.LibraryShelf) error {
+	query := `
+		UPDATE library_shelves
+		SET name = $1
+		WHERE id = $2 AND user_id = $3
+		RETURNING created_at, (SELECT COUNT(*) FROM library_shelf_entries se WHERE se.shelf_id = $2)
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, shelf.Name, shelf.ID, shelf.UserID).Scan(&shelf.CreatedAt, &shelf.EntryCount)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Полка не найдена", nil)
+		}
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Полка с таким названием уже существует", nil)
+		}
+		r.log.Error("Ошибка переименования полки", "error", err.Error(), "shelf_id", shelf.ID)
+		return errors.NewDatabaseError("Ошибка переименования полки", err)
+	}
+
+	return nil
+}
+
+// DeleteShelf удаляет полку пользователя; записи библиотеки сохраняются
+func (r *LibraryRepository) DeleteShelf(ctx context.Context, userID, shelfID int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM library_shelves WHERE id = $1 AND user_id = $2", shelfID, userID)
+	if err != nil {
+		r.log.Error("Ошибка удаления полки", "error", err.Error(), "shelf_id", shelfID)
+		return errors.NewDatabaseError("Ошибка удаления полки", err)
+	}
+
+	return r.checkAffected(result, "Полка не найдена")
+}
+
+// AddToShelf кладет мангу из библиотеки пользователя на полку
+func (r *LibraryRepository) AddToShelf(ctx context.Context, userID, shelfID, mangaID int64) error {
+	query := `
+		SELECT s.id AS shelf_id, e.id AS entry_id
+		FROM library_shelves s
+		JOIN library_entries e ON e.user_id = s.user_id AND e.manga_id = $3
+		WHERE s.id = $1 AND s.user_id = $2
+	`
+
+	var ids struct {
+		ShelfID int64 `db:"shelf_id"`
+		EntryID int64 `db:"entry_id"`
+	}
+	if err := r.db.GetContext(ctx, &ids, query, shelfID, userID, mangaID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Полка не найдена или манга не добавлена в библиотеку", nil)
+		}
+		r.log.Error("Ошибка добавления манги на полку", "error", err.Error(), "shelf_id", shelfID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка добавления манги на полку", err)
+	}
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO library_shelf_entries (shelf_id, entry_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", ids.ShelfID, ids.EntryID)
+	if err != nil {
+		r.log.Error("Ошибка добавления манги на полку", "error", err.Error(), "shelf_id", shelfID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка добавления манги на полку", err)
+	}
+
+	return nil
+}
+
+// RemoveFromShelf убирает мангу с полки пользователя
+func (r *LibraryRepository) RemoveFromShelf(ctx context.Context, userID, shelfID, mangaID int64) error {
+	query := `
+		DELETE FROM library_shelf_entries se
+		USING library_shelves s, library_entries e
+		WHERE se.shelf_id = s.id AND se.entry_id = e.id
+		  AND s.id = $1 AND s.user_id = $2 AND e.manga_id = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, shelfID, userID, mangaID)
+	if err != nil {
+		r.log.Error("Ошибка удаления манги с полки", "error", err.Error(), "shelf_id", shelfID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка удаления манги с полки", err)
+	}
+
+	return r.checkAffected(result, "Манга не найдена на полке")
+}
+
+// MatchMangaByTitles находит мангу каталога по основным и альтернативным названиям без учета регистра.
+// Совпадение с основным названием важнее альтернативного. Ключи результата — названия в нижнем регистре
+func (r *LibraryRepository) MatchMangaByTitles(ctx context.Context, titles []string) (map[string]int64, error) {
+	matches := make(map[string]int64, len(titles))
+	if len(titles) == 0 {
+		return matches, nil
+	}
+
+	lowered := make([]string, 0, len(titles))
+	for _, title := range titles {
+		lowered = append(lowered, strings.ToLower(title))
+	}
+
+	query := `
+		SELECT DISTINCT ON (title) title, id
+		FROM (
+			SELECT LOWER(title) AS title, id, 0 AS priority
+			FROM manga
+			WHERE LOWER(title) = ANY($1)
+			UNION ALL
+			SELECT LOWER(title) AS title, manga_id AS id, 1 AS priority
+			FROM manga_alt_titles
+			WHERE LOWER(title) = ANY($1)
+		) matched
+		ORDER BY title, priority, id
+	`
+
+	var rows []struct {
+		Title string `db:"title"`
+		ID    int64  `db:"id"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(lowered)); err != nil {
+		r.log.Error("Ошибка поиска манги по названиям", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка поиска манги по названиям", err)
+	}
+
+	for _, row := range rows {
+		matches[row.Title] = row.ID
+	}
+
+	return matches, nil
+}
+
+// MatchMangaByMALIDs находит мангу каталога по ID MyAnimeList.
+// Ключи результата — ID MyAnimeList
+func (r *LibraryRepository) MatchMangaByMALIDs(ctx context.Context, malIDs []int64) (map[int64]int64, error) {
+	matches := make(map[int64]int64, len(malIDs))
+	if len(malIDs) == 0 {
+		return matches, nil
+	}
+
+	query := `SELECT mal_id, id FROM manga WHERE mal_id = ANY($1)`
+
+	var rows []struct {
+		MALID int64 `db:"mal_id"`
+		ID    int64 `db:"id"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(malIDs)); err != nil {
+		r.log.Error("Ошибка поиска манги по ID MyAnimeList", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка поиска манги по ID MyAnimeList", err)
+	}
+
+	for _, row := range rows {
+		matches[row.MALID] = row.ID
+	}
+
+	return matches, nil
+}
+
+// Import сохраняет импортированные записи и их полки в одной транзакции.
+// Существующие записи заменяются только при item.Overwrite
+func (r *LibraryRepository) Import(ctx context.Context, userID int64, items []*entity.LibraryImportItem) (imported, updated int, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return 0, 0, errors.NewDatabaseError("Ошибка импорта библиотеки", err)
+	}
+	defer tx.Rollback()
+
+	entryQuery := `
+		INSERT INTO library_entries (user_id, manga_id, status, score, notes, started_at, finished_at, mal_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $9, NOW(), NOW())
+		ON CONFLICT (user_id, manga_id) DO UPDATE
+		SET status = EXCLUDED.status,
+		    score = EXCLUDED.score,
+		    notes = EXCLUDED.notes,
+		    started_at = EXCLUDED.started_at,
+		    finished_at = EXCLUDED.finished_at,
+		    mal_id = COALESCE(EXCLUDED.mal_id, library_entries.mal_id),
+		    updated_at = NOW()
+		WHERE $8::boolean
+		RETURNING id, (xmax = 0) AS inserted
+	`
+
+	shelfQuery := `
+		INSERT INTO library_shelves (user_id, name, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	`
+
+	shelfIDs := make(map[string]int64)
+	for _, item := range items {
+		var entryID int64
+		var inserted bool
+		err = tx.QueryRowxContext(
+			ctx,
+			entryQuery,
+			userID,
+			item.Entry.MangaID,
+			item.Entry.Status,
+			item.Entry.Score,
+			item.Entry.Notes,
+			item.Entry.StartedAt,
+			item.Entry.FinishedAt,
+			item.Overwrite,
+			item.Entry.MALID,
+		).Scan(&entryID, &inserted)
+		if stderrors.Is(err, sql.ErrNoRows) {
+			// Запись уже есть, а замена не разрешена
+			continue
+		}
+		if err != nil {
+			r.log.Error("Ошибка импорта записи библиотеки", "error", err.Error(), "user_id", userID, "manga_id", item.Entry.MangaID)
+			return 0, 0, errors.NewDatabaseError("Ошибка импорта библиотеки", err)
+		}
+
+		if inserted {
+			imported++
+		} else {
+			updated++
+		}
+
+		for _, name := range item.Shelves {
+			shelfID, ok := shelfIDs[name]
+			if !ok {
+				if err = tx.QueryRowxContext(ctx, shelfQuery, userID, name).Scan(&shelfID); err != nil {
+					r.log.Error("Ошибка создания полки при импорте", "error", err.Error(), "user_id", userID)
+					return 0, 0, errors.NewDatabaseError("Ошибка импорта библиотеки", err)
+				}
+				shelfIDs[name] = shelfID
+			}
+
+			_, err = tx.ExecContext(ctx, "INSERT INTO library_shelf_entries (shelf_id, entry_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", shelfID, entryID)
+			if err != nil {
+				r.log.Error("Ошибка добавления манги на полку при импорте", "error", err.Error(), "user_id", userID)
+				return 0, 0, errors.NewDatabaseError("Ошибка импорта библиотеки", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return 0, 0, errors.NewDatabaseError("Ошибка импорта библиотеки", err)
+	}
+
+	return imported, updated, nil
+}
+
+// checkAffected возвращает ошибку "не найдено", если запрос не изменил ни одной строки
+func (r *LibraryRepository) checkAffected(result sql.Result, notFoundMsg string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка изменения библиотеки", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError(notFoundMsg, nil)
+	}
+
+	return nil
+}
+
+// isUniqueViolation проверяет, нарушено ли ограничение уникальности
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	stderrors "errors"
 
@@ -16,6 +17,20 @@ import (
 	"manga-reader2/internal/domain/repository"
 )
 
+// mangaColumns перечисляет поля манги для выборок
+const mangaColumns = `
+	manga.id, manga.title, manga.description, manga.cover_image, manga.status, manga.author, manga.artist, manga.mal_id,
+	manga.comment_count, manga.rating_average, manga.bayesian_score, manga.rating_count, manga.review_count,
+	manga.created_at, manga.updated_at`
+
+// mangaSortOrders соответствие вариантов сортировки каталога выражениям ORDER BY
+var mangaSortOrders = map[string]string{
+	entity.MangaSortUpdated: "manga.updated_at DESC, manga.id DESC",
+	entity.MangaSortScore:   "manga.bayesian_score DESC, manga.id DESC",
+	entity.MangaSortAverage: "manga.rating_average DESC, manga.id DESC",
+	entity.MangaSortRatings: "manga.rating_count DESC, manga.id DESC",
+}
+
 // MangaRepository реализует интерфейс repository.MangaRepository для PostgreSQL
 type MangaRepository struct {
 	db  *sqlx.DB
@@ -33,8 +48,8 @@ func NewMangaRepository(db *sqlx.DB, log logger.Logger) repository.MangaReposito
 // Create создает новую мангу в базе данных
 func (r *MangaRepository) Create(ctx context.Context, manga *entity.Manga) (int64, error) {
 	query := `
-		INSERT INTO manga (title, description, cover_image, status, author, artist, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		INSERT INTO manga (title, description, cover_image, status, author, artist, mal_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 		RETURNING id
 	`
 
@@ -48,9 +63,13 @@ func (r *MangaRepository) Create(ctx context.Context, manga *entity.Manga) (int6
 		manga.Status,
 		manga.Author,
 		manga.Artist,
+		manga.MALID,
 	).Scan(&id)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, errors.NewConflictError("Манга с таким ID MyAnimeList уже существует", nil)
+		}
 		r.log.Error("Ошибка создания манги", "error", err.Error())
 		return 0, errors.NewDatabaseError("Ошибка создания манги", err)
 	}
@@ -64,16 +83,16 @@ func (r *MangaRepository) Create(ctx context.Context, manga *entity.Manga) (int6
 		}
 	}
 
+	if err = r.saveTranslations(ctx, id, manga); err != nil {
+		return 0, err
+	}
+
 	return id, nil
 }
 
 // GetByID получает мангу по идентификатору
 func (r *MangaRepository) GetByID(ctx context.Context, id int64) (*entity.Manga, error) {
-	query := `
-		SELECT id, title, description, cover_image, status, author, artist, created_at, updated_at
-		FROM manga
-		WHERE id = $1
-	`
+	query := "SELECT " + mangaColumns + " FROM manga WHERE manga.id = $1"
 
 	manga := &entity.Manga{}
 	err := r.db.GetContext(ctx, manga, query, id)
@@ -93,51 +112,55 @@ func (r *MangaRepository) GetByID(ctx context.Context, id int64) (*entity.Manga,
 		manga.Genres = genres
 	}
 
+	if err = r.fillTranslations(ctx, []*entity.Manga{manga}); err != nil {
+		return nil, err
+	}
+
 	return manga, nil
 }
 
 // List получает список манг с пагинацией и фильтрацией
 func (r *MangaRepository) List(ctx context.Context, filter entity.MangaFilter) ([]*entity.Manga, error) {
-	queryParts := []string{
-		"SELECT id, title, description, cover_image, status, author, artist, created_at, updated_at FROM manga",
-	}
+	queryParts := []string{"SELECT " + mangaColumns + " FROM manga"}
 
 	var where []string
 	var args []interface{}
 	argIndex := 1
 
 	if filter.Title != "" {
-		where = append(where, fmt.Sprintf("title ILIKE $%d", argIndex))
+		where = append(where, fmt.Sprintf(`(manga.title ILIKE $%[1]d OR EXISTS (
+			SELECT 1 FROM manga_alt_titles a WHERE a.manga_id = manga.id AND a.title ILIKE $%[1]d))`, argIndex))
 		args = append(args, "%"+filter.Title+"%")
 		argIndex++
 	}
 
 	if filter.Status != "" {
-		where = append(where, fmt.Sprintf("status = $%d", argIndex))
+		where = append(where, fmt.Sprintf("manga.status = $%d", argIndex))
 		args = append(args, filter.Status)
 		argIndex++
 	}
 
 	if len(filter.Genres) > 0 {
-		queryParts = append(queryParts, "JOIN manga_genres mg ON manga.id = mg.manga_id")
-		queryParts = append(queryParts, "JOIN genres g ON mg.genre_id = g.id")
-
 		genreConditions := make([]string, len(filter.Genres))
 		for i, genre := range filter.Genres {
 			genreConditions[i] = fmt.Sprintf("g.name = $%d", argIndex)
 			args = append(args, genre)
 			argIndex++
 		}
-		where = append(where, "("+strings.Join(genreConditions, " OR ")+")")
-
-		queryParts = append(queryParts, "GROUP BY manga.id")
+		where = append(where, `EXISTS (
+			SELECT 1 FROM manga_genres mg JOIN genres g ON mg.genre_id = g.id
+			WHERE mg.manga_id = manga.id AND (`+strings.Join(genreConditions, " OR ")+`))`)
 	}
 
 	if len(where) > 0 {
 		queryParts = append(queryParts, "WHERE "+strings.Join(where, " AND "))
 	}
 
-	queryParts = append(queryParts, "ORDER BY updated_at DESC")
+	orderBy, ok := mangaSortOrders[filter.Sort]
+	if !ok {
+		orderBy = mangaSortOrders[entity.MangaSortUpdated]
+	}
+	queryParts = append(queryParts, "ORDER BY "+orderBy)
 
 	if filter.Limit > 0 {
 		queryParts = append(queryParts, fmt.Sprintf("LIMIT $%d", argIndex))
@@ -167,6 +190,10 @@ func (r *MangaRepository) List(ctx context.Context, filter entity.MangaFilter) (
 		}
 	}
 
+	if err = r.fillTranslations(ctx, mangas); err != nil {
+		return nil, err
+	}
+
 	return mangas, nil
 }
 
@@ -174,8 +201,9 @@ func (r *MangaRepository) List(ctx context.Context, filter entity.MangaFilter) (
 func (r *MangaRepository) Update(ctx context.Context, manga *entity.Manga) error {
 	query := `
 		UPDATE manga 
-		SET title = $1, description = $2, cover_image = $3, status = $4, author = $5, artist = $6, updated_at = NOW()
-		WHERE id = $7
+		SET title = $1, description = $2, cover_image = $3, status = $4, author = $5, artist = $6, mal_id = $7,
+		    updated_at = NOW()
+		WHERE id = $8
 	`
 
 	result, err := r.db.ExecContext(
@@ -187,10 +215,14 @@ func (r *MangaRepository) Update(ctx context.Context, manga *entity.Manga) error
 		manga.Status,
 		manga.Author,
 		manga.Artist,
+		manga.MALID,
 		manga.ID,
 	)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Манга с таким ID MyAnimeList уже существует", nil)
+		}
 		r.log.Error("Ошибка обновления манги", "error", err.Error(), "id", manga.ID)
 		return errors.NewDatabaseError("Ошибка обновления манги", err)
 	}
@@ -252,6 +284,122 @@ func (r *MangaRepository) Update(ctx context.Context, manga *entity.Manga) error
 		}
 	}
 
+	return r.saveTranslations(ctx, manga.ID, manga)
+}
+
+// saveTranslations заменяет альтернативные названия и описания манги в одной транзакции.
+// Если список названий или описаний не передан (nil), сохраненные значения не меняются
+func (r *MangaRepository) saveTranslations(ctx context.Context, mangaID int64, manga *entity.Manga) error {
+	if manga.AltTitles == nil && manga.Descriptions == nil {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения переводов манги", err)
+	}
+	defer tx.Rollback()
+
+	if manga.AltTitles != nil {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM manga_alt_titles WHERE manga_id = $1", mangaID); err != nil {
+			r.log.Error("Ошибка удаления альтернативных названий", "error", err.Error(), "manga_id", mangaID)
+			return errors.NewDatabaseError("Ошибка сохранения переводов манги", err)
+		}
+
+		for i, altTitle := range manga.AltTitles {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO manga_alt_titles (manga_id, title, language, position)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (manga_id, language, title) DO NOTHING
+			`, mangaID, altTitle.Title, altTitle.Language, i)
+			if err != nil {
+				r.log.Error("Ошибка добавления альтернативного названия", "error", err.Error(), "manga_id", mangaID)
+				return errors.NewDatabaseError("Ошибка сохранения переводов манги", err)
+			}
+		}
+	}
+
+	if manga.Descriptions != nil {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM manga_descriptions WHERE manga_id = $1", mangaID); err != nil {
+			r.log.Error("Ошибка удаления описаний", "error", err.Error(), "manga_id", mangaID)
+			return errors.NewDatabaseError("Ошибка сохранения переводов манги", err)
+		}
+
+		for language, description := range manga.Descriptions {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO manga_descriptions (manga_id, language, description)
+				VALUES ($1, $2, $3)
+			`, mangaID, language, description)
+			if err != nil {
+				r.log.Error("Ошибка добавления описания", "error", err.Error(), "manga_id", mangaID, "language", language)
+				return errors.NewDatabaseError("Ошибка сохранения переводов манги", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения переводов манги", err)
+	}
+
+	return nil
+}
+
+// fillTranslations загружает альтернативные названия и описания для списка манги двумя запросами
+func (r *MangaRepository) fillTranslations(ctx context.Context, mangas []*entity.Manga) error {
+	if len(mangas) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, len(mangas))
+	byID := make(map[int64]*entity.Manga, len(mangas))
+	for i, manga := range mangas {
+		ids[i] = manga.ID
+		byID[manga.ID] = manga
+	}
+
+	var altTitles []struct {
+		MangaID int64 `db:"manga_id"`
+		entity.AltTitle
+	}
+	err := r.db.SelectContext(ctx, &altTitles, `
+		SELECT manga_id, title, language
+		FROM manga_alt_titles
+		WHERE manga_id = ANY($1)
+		ORDER BY manga_id, position, id
+	`, ids)
+	if err != nil {
+		r.log.Error("Ошибка получения альтернативных названий", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка получения переводов манги", err)
+	}
+	for i := range altTitles {
+		manga := byID[altTitles[i].MangaID]
+		manga.AltTitles = append(manga.AltTitles, &altTitles[i].AltTitle)
+	}
+
+	var descriptions []struct {
+		MangaID     int64  `db:"manga_id"`
+		Language    string `db:"language"`
+		Description string `db:"description"`
+	}
+	err = r.db.SelectContext(ctx, &descriptions, `
+		SELECT manga_id, language, description
+		FROM manga_descriptions
+		WHERE manga_id = ANY($1)
+	`, ids)
+	if err != nil {
+		r.log.Error("Ошибка получения описаний", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка получения переводов манги", err)
+	}
+	for _, row := range descriptions {
+		manga := byID[row.MangaID]
+		if manga.Descriptions == nil {
+			manga.Descriptions = make(map[string]string)
+		}
+		manga.Descriptions[row.Language] = row.Description
+	}
+
 	return nil
 }
 
@@ -393,3 +541,55 @@ func (r *MangaRepository) getOrCreateGenre(ctx context.Context, genre string) (i
 
 	return id, nil
 }
+
+// AssignUploader назначает загрузчика на мангу
+func (r *MangaRepository) AssignUploader(ctx context.Context, mangaID, userID int64) error {
+	query := `
+		INSERT INTO manga_uploaders (manga_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (manga_id, user_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, mangaID, userID); err != nil {
+		r.log.Error("Ошибка назначения загрузчика", "error", err.Error(), "manga_id", mangaID, "user_id", userID)
+		return errors.NewDatabaseError("Ошибка назначения загрузчика", err)
+	}
+
+	return nil
+}
+
+// UnassignUploader снимает загрузчика с манги
+func (r *MangaRepository) UnassignUploader(ctx context.Context, mangaID, userID int64) error {
+	query := `DELETE FROM manga_uploaders WHERE manga_id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, mangaID, userID)
+	if err != nil {
+		r.log.Error("Ошибка снятия загрузчика", "error", err.Error(), "manga_id", mangaID, "user_id", userID)
+		return errors.NewDatabaseError("Ошибка снятия загрузчика", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества удаленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка снятия загрузчика", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Загрузчик не назначен на эту мангу", nil)
+	}
+
+	return nil
+}
+
+// IsUploaderAssigned проверяет, назначен ли пользователь загрузчиком манги
+func (r *MangaRepository) IsUploaderAssigned(ctx context.Context, mangaID, userID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM manga_uploaders WHERE manga_id = $1 AND user_id = $2)`
+
+	var assigned bool
+	if err := r.db.GetContext(ctx, &assigned, query, mangaID, userID); err != nil {
+		r.log.Error("Ошибка проверки назначения загрузчика", "error", err.Error(), "manga_id", mangaID, "user_id", userID)
+		return false, errors.NewDatabaseError("Ошибка проверки назначения загрузчика", err)
+	}
+
+	return assigned, nil
+}
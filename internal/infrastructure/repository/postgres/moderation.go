@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+)
+
+// ModerationRepository реализация интерфейса repository.ModerationRepository для PostgreSQL.
+// Количество открытых жалоб хранится в comments.report_count и обновляется вместе с жалобами
+type ModerationRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewModerationRepository создает новый экземпляр ModerationRepository
+func NewModerationRepository(db *sqlx.DB, log logger.Logger) repository.ModerationRepository {
+	return &ModerationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// moderationQueueRow строка очереди модерации с массивом причин жалоб
+type moderationQueueRow struct {
+	entity.ModerationQueueItem
+	Reasons pq.StringArray `db:"reasons"`
+}
+
+// CreateReport сохраняет жалобу и возвращает количество открытых жалоб на комментарий
+func (r *ModerationRepository) CreateReport(ctx context.Context, report *entity.CommentReport) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка создания жалобы", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO comment_reports (comment_id, reporter_id, reason, details, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowxContext(ctx, query, report.CommentID, report.ReporterID, report.Reason, report.Details, entity.ReportStatusOpen).
+		Scan(&report.ID, &report.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, errors.NewConflictError("Вы уже отправляли жалобу на этот комментарий", nil)
+		}
+		r.log.Error("Ошибка создания жалобы", "error", err.Error(), "comment_id", report.CommentID, "reporter_id", report.ReporterID)
+		return 0, errors.NewDatabaseError("Ошибка создания жалобы", err)
+	}
+	report.Status = entity.ReportStatusOpen
+
+	var openReports int
+	countQuery := "UPDATE comments SET report_count = report_count + 1 WHERE id = $1 RETURNING report_count"
+	if err = tx.GetContext(ctx, &openReports, countQuery, report.CommentID); err != nil {
+		r.log.Error("Ошибка обновления счетчика жалоб", "error", err.Error(), "comment_id", report.CommentID)
+		return 0, errors.NewDatabaseError("Ошибка создания жалобы", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка создания жалобы", err)
+	}
+
+	return openReports, nil
+}
+
+// ListQueue возвращает комментарии с открытыми жалобами: сначала с наибольшим числом жалоб
+func (r *ModerationRepository) ListQueue(ctx context.Context, filter entity.ModerationQueueFilter) ([]*entity.ModerationQueueItem, int, error) {
+	var total int
+	countQuery := "SELECT COUNT(*) FROM comments WHERE report_count > 0 AND deleted_at IS NULL"
+	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+		r.log.Error("Ошибка подсчета очереди модерации", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения очереди модерации", err)
+	}
+
+	query := "SELECT " + commentColumns + `,
+			c.report_count AS open_reports, rp.reasons, rp.last_reported_at
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN LATERAL (
+			SELECT array_agg(DISTINCT reason) AS reasons, MAX(created_at) AS last_reported_at
+			FROM comment_reports
+			WHERE comment_id = c.id AND status = 'open'
+		) rp ON TRUE
+		WHERE c.report_count > 0 AND c.deleted_at IS NULL
+		ORDER BY c.report_count DESC, rp.last_reported_at DESC, c.id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	var rows []*moderationQueueRow
+	if err := r.db.SelectContext(ctx, &rows, query, filter.Limit, filter.Offset); err != nil {
+		r.log.Error("Ошибка получения очереди модерации", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения очереди модерации", err)
+	}
+
+	items := make([]*entity.ModerationQueueItem, 0, len(rows))
+	for _, row := range rows {
+		item := row.ModerationQueueItem
+		item.Reasons = []string(row.Reasons)
+		items = append(items, &item)
+	}
+
+	return items, total, nil
+}
+
+// LogAction добавляет запись в журнал модерации
+func (r *ModerationRepository) LogAction(ctx context.Context, entry *entity.ModerationLogEntry) error {
+	return logModerationAction(ctx, r.db, r.log, entry)
+}
+
+// ListLog возвращает страницу журнала модерации от новых записей к старым
+func (r *ModerationRepository) ListLog(ctx context.Context, filter entity.ModerationLogFilter) ([]*entity.ModerationLogEntry, int, error) {
+	var where []string
+	var args []interface{}
+
+	if filter.ModeratorID != nil {
+		args = append(args, *filter.ModeratorID)
+		where = append(where, fmt.Sprintf("moderator_id = $%d", len(args)))
+	}
+	if filter.TargetUserID != nil {
+		args = append(args, *filter.TargetUserID)
+		where = append(where, fmt.Sprintf("target_user_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		where = append(where, fmt.Sprintf("action = $%d", len(args)))
+	}
+
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM moderation_log "+whereClause, args...); err != nil {
+		r.log.Error("Ошибка подсчета записей журнала модерации", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения журнала модерации", err)
+	}
+
+	query := `
+		SELECT id, moderator_id, action, comment_id, target_user_id, reason, created_at
+		FROM moderation_log
+		` + whereClause + `
+		ORDER BY created_at DESC, id DESC` + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var entries []*entity.ModerationLogEntry
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		r.log.Error("Ошибка получения журнала модерации", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения журнала модерации", err)
+	}
+
+	return entries, total, nil
+}
+
+// Apply выполняет действие модератора над комментарием, закрывает жалобы на него со статусом reportStatus
+// и записывает действие в журнал одной транзакцией. Для warn автор комментария получает уведомление
+// с причиной, для ban автор блокируется, а комментарий скрывается. Возвращает, изменился ли комментарий, и количество закрытых жалоб
+func (r *ModerationRepository) Apply(ctx context.Context, entry *entity.ModerationLogEntry, ban *entity.UserBan, reportStatus string) (bool, int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return false, 0, errors.NewDatabaseError("Ошибка выполнения действия модератора", err)
+	}
+	defer tx.Rollback()
+
+	commentID := *entry.CommentID
+	changed := false
+
+	switch entry.Action {
+	case entity.ModerationActionHide:
+		changed, err = setCommentHidden(ctx, tx, r.log, commentID, true)
+	case entity.ModerationActionUnhide:
+		changed, err = setCommentHidden(ctx, tx, r.log, commentID, false)
+	case entity.ModerationActionDelete:
+		err = deleteComment(ctx, tx, r.log, commentID)
+		changed = err == nil
+	case entity.ModerationActionWarn:
+		err = notifyCommentWarning(ctx, tx, r.log, commentID, entry.Reason)
+	case entity.ModerationActionBan:
+		if err = banUser(ctx, tx, r.log, *entry.TargetUserID, ban, *entry.ModeratorID); err == nil {
+			changed, err = setCommentHidden(ctx, tx, r.log, commentID, true)
+		}
+	}
+	if err != nil {
+		return false, 0, err
+	}
+
+	resolved, err := resolveReports(ctx, tx, r.log, commentID, reportStatus, *entry.ModeratorID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if err = logModerationAction(ctx, tx, r.log, entry); err != nil {
+		return false, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return false, 0, errors.NewDatabaseError("Ошибка выполнения действия модератора", err)
+	}
+
+	return changed, resolved, nil
+}
+
+// resolveReports закрывает открытые жалобы на комментарий в транзакции tx и обнуляет счетчик жалоб
+func resolveReports(ctx context.Context, tx *sqlx.Tx, log logger.Logger, commentID int64, status string, moderatorID int64) (int, error) {
+	query := `
+		UPDATE comment_reports
+		SET status = $2, resolved_by = $3, resolved_at = NOW()
+		WHERE comment_id = $1 AND status = 'open'
+	`
+	result, err := tx.ExecContext(ctx, query, commentID, status, moderatorID)
+	if err != nil {
+		log.Error("Ошибка обработки жалоб", "error", err.Error(), "comment_id", commentID)
+		return 0, errors.NewDatabaseError("Ошибка обработки жалоб", err)
+	}
+
+	resolved, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка обработки жалоб", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE comments SET report_count = 0 WHERE id = $1", commentID); err != nil {
+		log.Error("Ошибка обновления счетчика жалоб", "error", err.Error(), "comment_id", commentID)
+		return 0, errors.NewDatabaseError("Ошибка обработки жалоб", err)
+	}
+
+	return int(resolved), nil
+}
+
+// notifyCommentWarning создает в транзакции tx уведомление автору комментария о предупреждении модератора
+func notifyCommentWarning(ctx context.Context, tx *sqlx.Tx, log logger.Logger, commentID int64, reason string) error {
+	query := `
+		INSERT INTO notifications (user_id, type, manga_id, comment_id, message, created_at)
+		SELECT c.user_id, $2, COALESCE(c.manga_id, ch.manga_id), c.id, $3, NOW()
+		FROM comments c
+		LEFT JOIN chapters ch ON ch.id = c.chapter_id
+		WHERE c.id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, commentID, entity.NotificationTypeCommentWarning, reason); err != nil {
+		log.Error("Ошибка создания уведомления о предупреждении", "error", err.Error(), "comment_id", commentID)
+		return errors.NewDatabaseError("Ошибка создания уведомления о предупреждении", err)
+	}
+
+	return nil
+}
+
+// logModerationAction добавляет запись в журнал модерации запросом через db, которым может быть и транзакция
+func logModerationAction(ctx context.Context, db sqlx.QueryerContext, log logger.Logger, entry *entity.ModerationLogEntry) error {
+	query := `
+		INSERT INTO moderation_log (moderator_id, action, comment_id, target_user_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, created_at
+	`
+	err := db.QueryRowxContext(ctx, query, entry.ModeratorID, entry.Action, entry.CommentID, entry.TargetUserID, entry.Reason).
+		Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		log.Error("Ошибка записи в журнал модерации", "error", err.Error(), "action", entry.Action)
+		return errors.NewDatabaseError("Ошибка записи в журнал модерации", err)
+	}
+
+	return nil
+}
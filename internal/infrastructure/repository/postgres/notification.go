@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// NotificationRepository реализация интерфейса repository.NotificationRepository для PostgreSQL
+type NotificationRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewNotificationRepository создает новый экземпляр NotificationRepository
+func NewNotificationRepository(db *sqlx.DB, log logger.Logger) repository.NotificationRepository {
+	return &NotificationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// List возвращает страницу уведомлений пользователя, начиная с новых, и общее количество
+func (r *NotificationRepository) List(ctx context.Context, userID int64, filter entity.NotificationFilter) ([]*entity.NotificationItem, int, error) {
+	where := "WHERE n.user_id = $1"
+	if filter.UnreadOnly {
+		where += " AND n.read_at IS NULL"
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM notifications n "+where, userID); err != nil {
+		r.log.Error("Ошибка подсчета уведомлений", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения уведомлений", err)
+	}
+
+	query := `
+		SELECT n.id, n.user_id, n.type, n.manga_id, n.chapter_id, n.comment_id, COALESCE(n.message, '') AS message,
+		       n.read_at, n.created_at,
+		       m.title AS manga_title,
+		       COALESCE(m.cover_image, '') AS manga_cover_image,
+		       c.number AS chapter_number,
+		       c.title AS chapter_title
+		FROM notifications n
+		JOIN manga m ON m.id = n.manga_id
+		LEFT JOIN chapters c ON c.id = n.chapter_id
+		` + where + `
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var notifications []*entity.NotificationItem
+	if err := r.db.SelectContext(ctx, &notifications, query, userID, filter.Limit, filter.Offset); err != nil {
+		r.log.Error("Ошибка получения уведомлений", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения уведомлений", err)
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread возвращает количество непрочитанных уведомлений пользователя
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL"
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		r.log.Error("Ошибка подсчета непрочитанных уведомлений", "error", err.Error(), "user_id", userID)
+		return 0, errors.NewDatabaseError("Ошибка подсчета непрочитанных уведомлений", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		r.log.Error("Ошибка отметки уведомления прочитанным", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка отметки уведомления прочитанным", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка отметки уведомления прочитанным", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Уведомление не найдено", nil)
+	}
+
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	result, err := r.db.ExecContext(ctx, "UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL", userID)
+	if err != nil {
+		r.log.Error("Ошибка отметки уведомлений прочитанными", "error", err.Error(), "user_id", userID)
+		return 0, errors.NewDatabaseError("Ошибка отметки уведомлений прочитанными", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка отметки уведомлений прочитанными", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// CreateJob сохраняет задание рассылки уведомлений
+func (r *NotificationRepository) CreateJob(ctx context.Context, job *entity.NotificationJob) error {
+	query := `
+		INSERT INTO notification_jobs (type, manga_id, chapter_id, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, created_at
+	`
+
+	if err := r.db.QueryRowxContext(ctx, query, job.Type, job.MangaID, job.ChapterID).Scan(&job.ID, &job.CreatedAt); err != nil {
+		r.log.Error("Ошибка создания задания рассылки", "error", err.Error(), "manga_id", job.MangaID)
+		return errors.NewDatabaseError("Ошибка создания задания рассылки", err)
+	}
+
+	return nil
+}
+
+// ProcessJobBatch рассылает уведомления следующему пакету подписчиков самого старого незавершенного задания.
+// Задание блокируется на время пакета (SKIP LOCKED), поэтому несколько экземпляров приложения не мешают друг другу.
+// Возвращает обработанное задание (nil, если заданий нет) и пользователей, которым созданы уведомления
+func (r *NotificationRepository) ProcessJobBatch(ctx context.Context, batchSize int) (*entity.NotificationJob, []int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return nil, nil, errors.NewDatabaseError("Ошибка рассылки уведомлений", err)
+	}
+	defer tx.Rollback()
+
+	jobQuery := `
+		SELECT id, type, manga_id, chapter_id, cursor_user_id, notified, created_at, completed_at
+		FROM notification_jobs
+		WHERE completed_at IS NULL
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var job entity.NotificationJob
+	if err = tx.GetContext(ctx, &job, jobQuery); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, nil, nil
+		}
+		r.log.Error("Ошибка получения задания рассылки", "error", err.Error())
+		return nil, nil, errors.NewDatabaseError("Ошибка рассылки уведомлений", err)
+	}
+
+	batchQuery := `
+		WITH batch AS (
+		    SELECT user_id FROM manga_follows
+		    WHERE manga_id = $1 AND user_id > $2
+		    ORDER BY user_id
+		    LIMIT $3
+		), inserted AS (
+		    INSERT INTO notifications (user_id, type, manga_id, chapter_id, created_at)
+		    SELECT user_id, $4, $1, $5, NOW() FROM batch
+		    ON CONFLICT (user_id, type, chapter_id) DO NOTHING
+		    RETURNING user_id
+		)
+		SELECT (SELECT COUNT(*) FROM batch) AS batch_size,
+		       COALESCE((SELECT MAX(user_id) FROM batch), $2::bigint) AS last_user_id,
+		       COALESCE((SELECT array_agg(user_id) FROM inserted), '{}') AS inserted
+	`
+
+	var batch struct {
+		Size       int           `db:"batch_size"`
+		LastUserID int64         `db:"last_user_id"`
+		Inserted   pq.Int64Array `db:"inserted"`
+	}
+	if err = tx.GetContext(ctx, &batch, batchQuery, job.MangaID, job.CursorUserID, batchSize, job.Type, job.ChapterID); err != nil {
+		r.log.Error("Ошибка рассылки пакета уведомлений", "error", err.Error(), "job_id", job.ID)
+		return nil, nil, errors.NewDatabaseError("Ошибка рассылки уведомлений", err)
+	}
+
+	// Неполный пакет означает, что подписчики закончились
+	done := batch.Size < batchSize
+	updateQuery := `
+		UPDATE notification_jobs
+		SET cursor_user_id = $2,
+		    notified = notified + $3,
+		    completed_at = CASE WHEN $4::boolean THEN NOW() END
+		WHERE id = $1
+		RETURNING cursor_user_id, notified, completed_at
+	`
+	err = tx.QueryRowxContext(ctx, updateQuery, job.ID, batch.LastUserID, len(batch.Inserted), done).
+		Scan(&job.CursorUserID, &job.Notified, &job.CompletedAt)
+	if err != nil {
+		r.log.Error("Ошибка обновления задания рассылки", "error", err.Error(), "job_id", job.ID)
+		return nil, nil, errors.NewDatabaseError("Ошибка рассылки уведомлений", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return nil, nil, errors.NewDatabaseError("Ошибка рассылки уведомлений", err)
+	}
+
+	return &job, batch.Inserted, nil
+}
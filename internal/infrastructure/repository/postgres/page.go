@@ -6,6 +6,7 @@ import (
 	stderrors "errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"manga-reader2/internal/common/errors"
 	"manga-reader2/internal/common/logger"
 	"manga-reader2/internal/domain/entity"
@@ -100,6 +101,36 @@ func (r *PageRepository) ListByChapter(ctx context.Context, chapterID int64) ([]
 	return pages, nil
 }
 
+// CountByChapters возвращает число страниц в каждой из глав. Главы без страниц в результат не попадают
+func (r *PageRepository) CountByChapters(ctx context.Context, chapterIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(chapterIDs))
+	if len(chapterIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT chapter_id, COUNT(*) AS page_count
+		FROM pages
+		WHERE chapter_id = ANY($1)
+		GROUP BY chapter_id
+	`
+
+	var rows []struct {
+		ChapterID int64 `db:"chapter_id"`
+		PageCount int   `db:"page_count"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Int64Array(chapterIDs)); err != nil {
+		r.log.Error("Ошибка подсчета страниц глав", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка подсчета страниц глав", err)
+	}
+
+	for _, row := range rows {
+		counts[row.ChapterID] = row.PageCount
+	}
+
+	return counts, nil
+}
+
 // Update обновляет информацию о странице
 func (r *PageRepository) Update(ctx context.Context, page *entity.Page) error {
 	query := `
@@ -174,3 +205,183 @@ func (r *PageRepository) DeleteByChapterID(ctx context.Context, chapterID int64)
 
 	return nil
 }
+
+// Reorder перенумеровывает страницы главы с 1 в порядке pageIDs. Список должен содержать
+// каждую страницу главы ровно один раз
+func (r *PageRepository) Reorder(ctx context.Context, chapterID int64, pageIDs []int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка изменения порядка страниц", err)
+	}
+	defer tx.Rollback()
+
+	if err = r.lockChapterPages(ctx, tx, chapterID); err != nil {
+		return err
+	}
+
+	var currentIDs []int64
+	if err = tx.SelectContext(ctx, &currentIDs, "SELECT id FROM pages WHERE chapter_id = $1", chapterID); err != nil {
+		r.log.Error("Ошибка получения страниц главы", "error", err.Error(), "chapter_id", chapterID)
+		return errors.NewDatabaseError("Ошибка изменения порядка страниц", err)
+	}
+	if !samePageIDs(currentIDs, pageIDs) {
+		return errors.NewValidationError("Порядок должен содержать каждую страницу главы ровно один раз", map[string]interface{}{
+			"pages":    len(currentIDs),
+			"page_ids": len(pageIDs),
+		})
+	}
+
+	query := `
+		UPDATE pages p
+		SET number = o.position, updated_at = NOW()
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, position)
+		WHERE p.id = o.id AND p.chapter_id = $1 AND p.number <> o.position
+	`
+	if _, err = tx.ExecContext(ctx, query, chapterID, pq.Int64Array(pageIDs)); err != nil {
+		r.log.Error("Ошибка изменения порядка страниц", "error", err.Error(), "chapter_id", chapterID)
+		return errors.NewDatabaseError("Ошибка изменения порядка страниц", err)
+	}
+
+	return r.commitPages(tx, chapterID, "Ошибка изменения порядка страниц")
+}
+
+// Insert вставляет страницу на позицию page.Number, сдвигая эту и последующие страницы главы.
+// Позиция может быть от 1 до номера последней страницы + 1
+func (r *PageRepository) Insert(ctx context.Context, page *entity.Page) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка вставки страницы", err)
+	}
+	defer tx.Rollback()
+
+	if err = r.lockChapterPages(ctx, tx, page.ChapterID); err != nil {
+		return err
+	}
+
+	var lastNumber int
+	if err = tx.GetContext(ctx, &lastNumber, "SELECT COALESCE(MAX(number), 0) FROM pages WHERE chapter_id = $1", page.ChapterID); err != nil {
+		r.log.Error("Ошибка получения страниц главы", "error", err.Error(), "chapter_id", page.ChapterID)
+		return errors.NewDatabaseError("Ошибка вставки страницы", err)
+	}
+	if page.Number < 1 || page.Number > lastNumber+1 {
+		return errors.NewValidationError("Некорректная позиция страницы", map[string]interface{}{
+			"position":     page.Number,
+			"max_position": lastNumber + 1,
+		})
+	}
+
+	query := `
+		UPDATE pages
+		SET number = number + 1, updated_at = NOW()
+		WHERE chapter_id = $1 AND number >= $2
+	`
+	if _, err = tx.ExecContext(ctx, query, page.ChapterID, page.Number); err != nil {
+		r.log.Error("Ошибка сдвига страниц", "error", err.Error(), "chapter_id", page.ChapterID)
+		return errors.NewDatabaseError("Ошибка вставки страницы", err)
+	}
+
+	query = `
+		INSERT INTO pages (chapter_id, number, image_path, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowxContext(ctx, query, page.ChapterID, page.Number, page.ImagePath).
+		Scan(&page.ID, &page.CreatedAt, &page.UpdatedAt)
+	if err != nil {
+		r.log.Error("Ошибка вставки страницы", "error", err.Error(), "chapter_id", page.ChapterID)
+		return errors.NewDatabaseError("Ошибка вставки страницы", err)
+	}
+
+	return r.commitPages(tx, page.ChapterID, "Ошибка вставки страницы")
+}
+
+// ReplaceAll заменяет все страницы главы новыми с номерами по порядку imagePaths
+// и возвращает пути изображений удаленных страниц
+func (r *PageRepository) ReplaceAll(ctx context.Context, chapterID int64, imagePaths []string) ([]string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка замены страниц", err)
+	}
+	defer tx.Rollback()
+
+	if err = r.lockChapterPages(ctx, tx, chapterID); err != nil {
+		return nil, err
+	}
+
+	removedPaths := []string{}
+	if err = tx.SelectContext(ctx, &removedPaths, "DELETE FROM pages WHERE chapter_id = $1 RETURNING image_path", chapterID); err != nil {
+		r.log.Error("Ошибка удаления страниц", "error", err.Error(), "chapter_id", chapterID)
+		return nil, errors.NewDatabaseError("Ошибка замены страниц", err)
+	}
+
+	query := `
+		INSERT INTO pages (chapter_id, number, image_path, created_at, updated_at)
+		SELECT $1, o.position, o.image_path, NOW(), NOW()
+		FROM unnest($2::text[]) WITH ORDINALITY AS o(image_path, position)
+	`
+	if _, err = tx.ExecContext(ctx, query, chapterID, pq.StringArray(imagePaths)); err != nil {
+		r.log.Error("Ошибка создания страниц", "error", err.Error(), "chapter_id", chapterID)
+		return nil, errors.NewDatabaseError("Ошибка замены страниц", err)
+	}
+
+	if err = r.commitPages(tx, chapterID, "Ошибка замены страниц"); err != nil {
+		return nil, err
+	}
+
+	return removedPaths, nil
+}
+
+// lockChapterPages блокирует главу до конца транзакции, чтобы изменения ее страниц выполнялись по очереди,
+// и откладывает проверку уникальности номеров страниц до фиксации
+func (r *PageRepository) lockChapterPages(ctx context.Context, tx *sqlx.Tx, chapterID int64) error {
+	var id int64
+	if err := tx.GetContext(ctx, &id, "SELECT id FROM chapters WHERE id = $1 FOR NO KEY UPDATE", chapterID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewChapterNotFoundError(chapterID)
+		}
+		r.log.Error("Ошибка блокировки главы", "error", err.Error(), "chapter_id", chapterID)
+		return errors.NewDatabaseError("Ошибка блокировки главы", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SET CONSTRAINTS pages_chapter_id_number_key DEFERRED"); err != nil {
+		r.log.Error("Ошибка откладывания проверки номеров страниц", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка откладывания проверки номеров страниц", err)
+	}
+
+	return nil
+}
+
+// commitPages фиксирует изменение страниц. Отложенная проверка уникальности номеров срабатывает при фиксации
+func (r *PageRepository) commitPages(tx *sqlx.Tx, chapterID int64, message string) error {
+	if err := tx.Commit(); err != nil {
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Номера страниц главы повторяются", err)
+		}
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error(), "chapter_id", chapterID)
+		return errors.NewDatabaseError(message, err)
+	}
+
+	return nil
+}
+
+// samePageIDs проверяет, что ids содержит каждую страницу из current ровно один раз
+func samePageIDs(current, ids []int64) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+
+	remaining := make(map[int64]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
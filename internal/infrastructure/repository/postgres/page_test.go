@@ -0,0 +1,28 @@
+package postgres
+
+import "testing"
+
+func TestSamePageIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []int64
+		ids     []int64
+		want    bool
+	}{
+		{name: "тот же порядок", current: []int64{1, 2, 3}, ids: []int64{1, 2, 3}, want: true},
+		{name: "другой порядок", current: []int64{1, 2, 3}, ids: []int64{3, 1, 2}, want: true},
+		{name: "пустая глава", current: []int64{}, ids: []int64{}, want: true},
+		{name: "не хватает страницы", current: []int64{1, 2, 3}, ids: []int64{1, 2}, want: false},
+		{name: "лишняя страница", current: []int64{1, 2}, ids: []int64{1, 2, 3}, want: false},
+		{name: "чужая страница", current: []int64{1, 2, 3}, ids: []int64{1, 2, 4}, want: false},
+		{name: "повтор вместо страницы", current: []int64{1, 2, 3}, ids: []int64{1, 2, 2}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := samePageIDs(tt.current, tt.ids); got != tt.want {
+				t.Errorf("samePageIDs(%v, %v) = %v, ожидалось %v", tt.current, tt.ids, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// ratingPriorWeight число "виртуальных" оценок со средним по каталогу в байесовской оценке:
+// манга с несколькими оценками не обгоняет мангу с сотнями оценок чуть ниже
+const ratingPriorWeight = 10
+
+// ratingScoresUpdate пересчитывает среднюю и байесовскую оценки по хранимым счетчикам манги
+// и общим счетчикам каталога; условие отбора манги подставляется в конец запроса
+var ratingScoresUpdate = fmt.Sprintf(`
+	UPDATE manga m
+	SET rating_average = CASE WHEN m.rating_count > 0 THEN ROUND(m.rating_sum::numeric / m.rating_count, 2) ELSE 0 END,
+	    bayesian_score = CASE WHEN m.rating_count > 0
+	        THEN ROUND((m.rating_sum + %[1]d * s.mean) / (m.rating_count + %[1]d), 2)
+	        ELSE 0 END
+	FROM (
+		SELECT CASE WHEN rating_count > 0 THEN rating_sum::numeric / rating_count ELSE 0 END AS mean
+		FROM rating_stats
+	) s
+	WHERE `, ratingPriorWeight)
+
+// RatingRepository реализация интерфейса repository.RatingRepository для PostgreSQL.
+// Счетчики, гистограмма и оценки манги обновляются инкрементально в транзакции вместе с оценкой,
+// а среднее по каталогу для байесовской оценки — периодически в RefreshScores
+type RatingRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewRatingRepository создает новый экземпляр RatingRepository
+func NewRatingRepository(db *sqlx.DB, log logger.Logger) repository.RatingRepository {
+	return &RatingRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Rate ставит или изменяет оценку пользователя
+func (r *RatingRepository) Rate(ctx context.Context, userID, mangaID int64, score int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения оценки", err)
+	}
+	defer tx.Rollback()
+
+	histogram, err := r.lockManga(ctx, tx, mangaID)
+	if err != nil {
+		return err
+	}
+
+	var previous int
+	err = tx.GetContext(ctx, &previous, "SELECT score FROM manga_ratings WHERE user_id = $1 AND manga_id = $2", userID, mangaID)
+	if err != nil && !stderrors.Is(err, sql.ErrNoRows) {
+		r.log.Error("Ошибка получения оценки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка сохранения оценки", err)
+	}
+
+	query := `
+		INSERT INTO manga_ratings (user_id, manga_id, score, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (user_id, manga_id) DO UPDATE SET score = EXCLUDED.score, updated_at = NOW()
+	`
+	if _, err = tx.ExecContext(ctx, query, userID, mangaID, score); err != nil {
+		r.log.Error("Ошибка сохранения оценки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка сохранения оценки", err)
+	}
+
+	if previous != score {
+		if err = r.applyChange(ctx, tx, mangaID, histogram, previous, score); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения оценки", err)
+	}
+
+	return nil
+}
+
+// Remove удаляет оценку пользователя
+func (r *RatingRepository) Remove(ctx context.Context, userID, mangaID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления оценки", err)
+	}
+	defer tx.Rollback()
+
+	histogram, err := r.lockManga(ctx, tx, mangaID)
+	if err != nil {
+		return err
+	}
+
+	var previous int
+	query := "DELETE FROM manga_ratings WHERE user_id = $1 AND manga_id = $2 RETURNING score"
+	if err = tx.GetContext(ctx, &previous, query, userID, mangaID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Оценка не найдена", nil)
+		}
+		r.log.Error("Ошибка удаления оценки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка удаления оценки", err)
+	}
+
+	if err = r.applyChange(ctx, tx, mangaID, histogram, previous, 0); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления оценки", err)
+	}
+
+	return nil
+}
+
+// Get получает оценку пользователя
+func (r *RatingRepository) Get(ctx context.Context, userID, mangaID int64) (*entity.MangaRating, error) {
+	query := `
+		SELECT user_id, manga_id, score, created_at, updated_at
+		FROM manga_ratings
+		WHERE user_id = $1 AND manga_id = $2
+	`
+
+	var rating entity.MangaRating
+	if err := r.db.GetContext(ctx, &rating, query, userID, mangaID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Оценка не найдена", nil)
+		}
+		r.log.Error("Ошибка получения оценки", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения оценки", err)
+	}
+
+	return &rating, nil
+}
+
+// Summary возвращает хранимые агрегаты оценок манги
+func (r *RatingRepository) Summary(ctx context.Context, mangaID int64) (*entity.MangaRatingSummary, error) {
+	query := `
+		SELECT rating_average, bayesian_score, rating_count, rating_histogram
+		FROM manga
+		WHERE id = $1
+	`
+
+	summary := &entity.MangaRatingSummary{MangaID: mangaID}
+	var histogram pq.Int64Array
+	err := r.db.QueryRowxContext(ctx, query, mangaID).
+		Scan(&summary.Average, &summary.BayesianScore, &summary.Count, &histogram)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewMangaNotFoundError(mangaID)
+		}
+		r.log.Error("Ошибка получения оценок манги", "error", err.Error(), "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения оценок манги", err)
+	}
+
+	summary.Histogram = make([]entity.RatingBucket, 0, entity.RatingMaxScore)
+	for score := entity.RatingMinScore; score <= entity.RatingMaxScore; score++ {
+		bucket := entity.RatingBucket{Score: score}
+		if score <= len(histogram) {
+			bucket.Count = histogram[score-1]
+		}
+		summary.Histogram = append(summary.Histogram, bucket)
+	}
+
+	return summary, nil
+}
+
+// RefreshScores обновляет общие счетчики каталога по суммам манги и пересчитывает
+// байесовские оценки всех оцененных манг с новым средним по каталогу
+func (r *RatingRepository) RefreshScores(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка пересчета оценок", err)
+	}
+	defer tx.Rollback()
+
+	statsQuery := `
+		UPDATE rating_stats
+		SET rating_count = totals.rating_count, rating_sum = totals.rating_sum
+		FROM (SELECT COALESCE(SUM(rating_count), 0) AS rating_count, COALESCE(SUM(rating_sum), 0) AS rating_sum FROM manga) totals
+	`
+	if _, err = tx.ExecContext(ctx, statsQuery); err != nil {
+		r.log.Error("Ошибка сверки счетчиков оценок", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка пересчета оценок", err)
+	}
+
+	result, err := tx.ExecContext(ctx, ratingScoresUpdate+"m.rating_count > 0")
+	if err != nil {
+		r.log.Error("Ошибка пересчета оценок", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка пересчета оценок", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка пересчета оценок", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return 0, errors.NewDatabaseError("Ошибка пересчета оценок", err)
+	}
+
+	return updated, nil
+}
+
+// lockManga блокирует строку манги до конца транзакции и возвращает ее гистограмму оценок
+func (r *RatingRepository) lockManga(ctx context.Context, tx *sqlx.Tx, mangaID int64) (pq.Int64Array, error) {
+	var histogram pq.Int64Array
+	err := tx.QueryRowxContext(ctx, "SELECT rating_histogram FROM manga WHERE id = $1 FOR UPDATE", mangaID).Scan(&histogram)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewMangaNotFoundError(mangaID)
+		}
+		r.log.Error("Ошибка блокировки манги", "error", err.Error(), "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка сохранения оценки", err)
+	}
+
+	return histogram, nil
+}
+
+// applyChange учитывает замену оценки previous на score (0 — оценки нет) в счетчиках манги
+// и пересчитывает ее среднюю и байесовскую оценки. Общие счетчики каталога здесь не трогаются,
+// чтобы оценки разных манг не ждали друг друга на одной строке; их обновляет RefreshScores
+func (r *RatingRepository) applyChange(ctx context.Context, tx *sqlx.Tx, mangaID int64, histogram pq.Int64Array, previous, score int) error {
+	for len(histogram) < entity.RatingMaxScore {
+		histogram = append(histogram, 0)
+	}
+
+	var countDelta int
+	if previous > 0 {
+		histogram[previous-1]--
+		countDelta--
+	}
+	if score > 0 {
+		histogram[score-1]++
+		countDelta++
+	}
+	sumDelta := score - previous
+
+	mangaQuery := `
+		UPDATE manga
+		SET rating_count = rating_count + $2, rating_sum = rating_sum + $3, rating_histogram = $4
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, mangaQuery, mangaID, countDelta, sumDelta, histogram); err != nil {
+		r.log.Error("Ошибка обновления счетчиков манги", "error", err.Error(), "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка обновления оценок манги", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ratingScoresUpdate+"m.id = $1", mangaID); err != nil {
+		r.log.Error("Ошибка пересчета оценки манги", "error", err.Error(), "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка обновления оценок манги", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// ReadingHistoryRepository реализация интерфейса repository.ReadingHistoryRepository для PostgreSQL
+type ReadingHistoryRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewReadingHistoryRepository создает новый экземпляр ReadingHistoryRepository
+func NewReadingHistoryRepository(db *sqlx.DB, log logger.Logger) repository.ReadingHistoryRepository {
+	return &ReadingHistoryRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Upsert записывает чтение главы; повторное чтение обновляет время и, если указана, страницу
+func (r *ReadingHistoryRepository) Upsert(ctx context.Context, entry *entity.ReadingHistoryEntry) error {
+	query := `
+		INSERT INTO reading_history (user_id, manga_id, chapter_id, page_number, read_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, chapter_id) DO UPDATE
+		SET page_number = COALESCE(EXCLUDED.page_number, reading_history.page_number),
+		    read_at = NOW()
+		RETURNING id, page_number, read_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, entry.UserID, entry.MangaID, entry.ChapterID, entry.PageNumber).
+		Scan(&entry.ID, &entry.PageNumber, &entry.ReadAt)
+	if err != nil {
+		r.log.Error("Ошибка записи истории чтения", "error", err.Error(), "user_id", entry.UserID, "chapter_id", entry.ChapterID)
+		return errors.NewDatabaseError("Ошибка записи истории чтения", err)
+	}
+
+	return nil
+}
+
+// ListByUser возвращает страницу истории чтения пользователя и общее количество записей
+func (r *ReadingHistoryRepository) ListByUser(ctx context.Context, userID int64, filter entity.ReadingHistoryFilter) ([]*entity.ReadingHistoryItem, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM reading_history WHERE user_id = $1", userID); err != nil {
+		r.log.Error("Ошибка подсчета истории чтения", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения истории чтения", err)
+	}
+
+	query := `
+		SELECT h.id, h.user_id, h.manga_id, h.chapter_id, h.page_number, h.read_at,
+		       m.title AS manga_title,
+		       COALESCE(m.cover_image, '') AS manga_cover_image,
+		       c.number AS chapter_number,
+		       c.title AS chapter_title
+		FROM reading_history h
+		JOIN manga m ON m.id = h.manga_id
+		JOIN chapters c ON c.id = h.chapter_id
+		WHERE h.user_id = $1
+		ORDER BY h.read_at DESC, h.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var items []*entity.ReadingHistoryItem
+	if err := r.db.SelectContext(ctx, &items, query, userID, filter.Limit, filter.Offset); err != nil {
+		r.log.Error("Ошибка получения истории чтения", "error", err.Error(), "user_id", userID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения истории чтения", err)
+	}
+
+	return items, total, nil
+}
+
+// ListLatestPerManga возвращает последнюю прочитанную главу и страницу по каждой манге
+func (r *ReadingHistoryRepository) ListLatestPerManga(ctx context.Context, userID int64, limit int) ([]*entity.ReadingHistoryItem, error) {
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (h.manga_id)
+			       h.id, h.user_id, h.manga_id, h.chapter_id, h.page_number, h.read_at,
+			       m.title AS manga_title,
+			       COALESCE(m.cover_image, '') AS manga_cover_image,
+			       c.number AS chapter_number,
+			       c.title AS chapter_title
+			FROM reading_history h
+			JOIN manga m ON m.id = h.manga_id
+			JOIN chapters c ON c.id = h.chapter_id
+			WHERE h.user_id = $1
+			ORDER BY h.manga_id, h.read_at DESC, h.id DESC
+		) latest
+		ORDER BY read_at DESC, id DESC
+		LIMIT $2
+	`
+
+	var items []*entity.ReadingHistoryItem
+	if err := r.db.SelectContext(ctx, &items, query, userID, limit); err != nil {
+		r.log.Error("Ошибка получения позиций продолжения чтения", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения истории чтения", err)
+	}
+
+	return items, nil
+}
+
+// Delete удаляет запись истории чтения пользователя
+func (r *ReadingHistoryRepository) Delete(ctx context.Context, id, userID int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM reading_history WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		r.log.Error("Ошибка удаления записи истории чтения", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка удаления записи истории чтения", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества удаленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления записи истории чтения", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Запись истории чтения не найдена", nil)
+	}
+
+	return nil
+}
+
+// DeleteAllByUser удаляет всю историю чтения пользователя
+func (r *ReadingHistoryRepository) DeleteAllByUser(ctx context.Context, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM reading_history WHERE user_id = $1", userID); err != nil {
+		r.log.Error("Ошибка очистки истории чтения", "error", err.Error(), "user_id", userID)
+		return errors.NewDatabaseError("Ошибка очистки истории чтения", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// progressWinConditions условия, при которых новая позиция заменяет сохраненную
+var progressWinConditions = map[string]string{
+	entity.ProgressPolicyLatestWrite: "EXCLUDED.client_updated_at > p.client_updated_at",
+	entity.ProgressPolicyFurthestRead: `
+		((SELECT number FROM chapters WHERE id = EXCLUDED.chapter_id), EXCLUDED.page_number, EXCLUDED.scroll_offset) >
+		((SELECT number FROM chapters WHERE id = p.chapter_id), p.page_number, p.scroll_offset)`,
+}
+
+// ReadingProgressRepository реализация интерфейса repository.ReadingProgressRepository для PostgreSQL
+type ReadingProgressRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewReadingProgressRepository создает новый экземпляр ReadingProgressRepository
+func NewReadingProgressRepository(db *sqlx.DB, log logger.Logger) repository.ReadingProgressRepository {
+	return &ReadingProgressRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save сохраняет позицию чтения, если она побеждает текущую по политике.
+// Сравнение и запись выполняются одним запросом, поэтому одновременные обновления с разных устройств не теряются
+func (r *ReadingProgressRepository) Save(ctx context.Context, progress *entity.ReadingProgress, policy string) (bool, error) {
+	condition, ok := progressWinConditions[policy]
+	if !ok {
+		return false, errors.NewValidationError("Неизвестная политика синхронизации", nil)
+	}
+
+	query := `
+		INSERT INTO reading_progress AS p
+		    (user_id, manga_id, chapter_id, page_number, scroll_offset, device_id, client_updated_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (user_id, manga_id) DO UPDATE
+		SET chapter_id = EXCLUDED.chapter_id,
+		    page_number = EXCLUDED.page_number,
+		    scroll_offset = EXCLUDED.scroll_offset,
+		    device_id = EXCLUDED.device_id,
+		    client_updated_at = EXCLUDED.client_updated_at,
+		    updated_at = NOW()
+		WHERE ` + condition + `
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		progress.UserID,
+		progress.MangaID,
+		progress.ChapterID,
+		progress.PageNumber,
+		progress.ScrollOffset,
+		progress.DeviceID,
+		progress.ClientUpdatedAt,
+	).Scan(&progress.UpdatedAt)
+
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		r.log.Error("Ошибка сохранения прогресса чтения", "error", err.Error(), "user_id", progress.UserID, "manga_id", progress.MangaID)
+		return false, errors.NewDatabaseError("Ошибка сохранения прогресса чтения", err)
+	}
+
+	return true, nil
+}
+
+// Get получает позицию чтения манги пользователем
+func (r *ReadingProgressRepository) Get(ctx context.Context, userID, mangaID int64) (*entity.ReadingProgress, error) {
+	query := `
+		SELECT p.user_id, p.manga_id, p.chapter_id, c.number AS chapter_number, p.page_number, p.scroll_offset,
+		       p.device_id, p.client_updated_at, p.updated_at
+		FROM reading_progress p
+		JOIN chapters c ON c.id = p.chapter_id
+		WHERE p.user_id = $1 AND p.manga_id = $2
+	`
+
+	var progress entity.ReadingProgress
+	if err := r.db.GetContext(ctx, &progress, query, userID, mangaID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Прогресс чтения не найден", nil)
+		}
+		r.log.Error("Ошибка получения прогресса чтения", "error", err.Error(), "user_id", userID, "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения прогресса чтения", err)
+	}
+
+	return &progress, nil
+}
+
+// ListByUser возвращает позиции чтения пользователя, при указании since — только измененные после этого времени
+func (r *ReadingProgressRepository) ListByUser(ctx context.Context, userID int64, since *time.Time) ([]*entity.ReadingProgress, error) {
+	query := `
+		SELECT p.user_id, p.manga_id, p.chapter_id, c.number AS chapter_number, p.page_number, p.scroll_offset,
+		       p.device_id, p.client_updated_at, p.updated_at
+		FROM reading_progress p
+		JOIN chapters c ON c.id = p.chapter_id
+		WHERE p.user_id = $1 AND ($2::timestamp IS NULL OR p.updated_at > $2)
+		ORDER BY p.updated_at DESC
+	`
+
+	var progress []*entity.ReadingProgress
+	if err := r.db.SelectContext(ctx, &progress, query, userID, since); err != nil {
+		r.log.Error("Ошибка получения прогресса чтения", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения прогресса чтения", err)
+	}
+
+	return progress, nil
+}
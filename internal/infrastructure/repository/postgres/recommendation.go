@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// Параметры пересчета похожести манги
+const (
+	// similarityUserItems ограничивает число последних манг пользователя, участвующих в подсчете пар:
+	// число пар растет квадратично от размера истории
+	similarityUserItems = 200
+	// similarityMinCoReaders минимальное число общих читателей для сохранения пары
+	similarityMinCoReaders = 2
+	// similarityShrinkage сглаживает меру для пар с малым числом общих читателей
+	similarityShrinkage = 5
+	// similarityNeighbors число соседей, сохраняемых для каждой манги
+	similarityNeighbors = 50
+)
+
+// Веса составляющих итоговой оценки рекомендации
+const (
+	recommendationCoReadingWeight = 0.7
+	recommendationGenreWeight     = 0.3
+)
+
+// recommendationSeen перечисляет мангу, уже известную пользователю: прочитанную,
+// в закладках и в библиотеке. Такая манга не попадает в рекомендации
+const recommendationSeen = `
+	seen AS (
+		SELECT manga_id FROM reading_history WHERE user_id = $1
+		UNION SELECT manga_id FROM bookmarks WHERE user_id = $1
+		UNION SELECT manga_id FROM chapter_reads WHERE user_id = $1
+		UNION SELECT manga_id FROM library_entries WHERE user_id = $1
+	)`
+
+// RecommendationRepository реализация интерфейса repository.RecommendationRepository для PostgreSQL.
+// Похожесть по совместному чтению пересчитывается периодически в manga_similarity,
+// а подборка для пользователя собирается одним запросом по этой таблице и жанрам его манги
+type RecommendationRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewRecommendationRepository создает новый экземпляр RecommendationRepository
+func NewRecommendationRepository(db *sqlx.DB, log logger.Logger) repository.RecommendationRepository {
+	return &RecommendationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// ListForUser подбирает мангу для пользователя. Оценка складывается из похожести на мангу
+// из истории чтения и закладок (нормированной на лучшего кандидата) и доли жанровых
+// предпочтений пользователя, которую покрывает манга
+func (r *RecommendationRepository) ListForUser(ctx context.Context, userID int64, limit int) ([]*entity.Recommendation, error) {
+	query := `
+		WITH seeds AS (
+			SELECT manga_id FROM reading_history WHERE user_id = $1
+			UNION SELECT manga_id FROM bookmarks WHERE user_id = $1
+		),` + recommendationSeen + `,
+		co AS (
+			SELECT ms.similar_manga_id AS manga_id, SUM(ms.score) AS score
+			FROM manga_similarity ms
+			JOIN seeds s ON s.manga_id = ms.manga_id
+			GROUP BY ms.similar_manga_id
+		),
+		affinity AS (
+			SELECT mg.genre_id, COUNT(*)::float8 AS weight
+			FROM seeds s
+			JOIN manga_genres mg ON mg.manga_id = s.manga_id
+			GROUP BY mg.genre_id
+		),
+		genre AS (
+			SELECT mg.manga_id, SUM(a.weight) / (SELECT SUM(weight) FROM affinity) AS score
+			FROM affinity a
+			JOIN manga_genres mg ON mg.genre_id = a.genre_id
+			GROUP BY mg.manga_id
+		),
+		candidates AS (
+			SELECT COALESCE(co.manga_id, genre.manga_id) AS manga_id,
+			       COALESCE(co.score / NULLIF(MAX(co.score) OVER (), 0), 0) AS co_score,
+			       COALESCE(genre.score, 0) AS genre_score
+			FROM co
+			FULL JOIN genre ON genre.manga_id = co.manga_id
+		)
+		SELECT ` + mangaColumns + `,
+		       $2 * c.co_score + $3 * c.genre_score AS score,
+		       CASE WHEN c.co_score > 0 THEN '` + entity.RecommendationReasonCoReading + `'
+		            ELSE '` + entity.RecommendationReasonGenre + `' END AS reason
+		FROM candidates c
+		JOIN manga ON manga.id = c.manga_id
+		WHERE NOT EXISTS (SELECT 1 FROM seen WHERE seen.manga_id = c.manga_id)
+		ORDER BY score DESC, manga.bayesian_score DESC, manga.id
+		LIMIT $4
+	`
+
+	recommendations := []*entity.Recommendation{}
+	err := r.db.SelectContext(ctx, &recommendations, query,
+		userID, recommendationCoReadingWeight, recommendationGenreWeight, limit)
+	if err != nil {
+		r.log.Error("Ошибка подбора рекомендаций", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения рекомендаций", err)
+	}
+
+	return recommendations, nil
+}
+
+// ListPopularForUser возвращает непрочитанную пользователем мангу: сначала из popularIDs
+// в заданном порядке, затем остальную по байесовской оценке
+func (r *RecommendationRepository) ListPopularForUser(ctx context.Context, userID int64, popularIDs []int64, limit int) ([]*entity.Recommendation, error) {
+	query := `
+		WITH` + recommendationSeen + `
+		SELECT ` + mangaColumns + `, 0::float8 AS score, '` + entity.RecommendationReasonPopular + `' AS reason
+		FROM manga
+		WHERE NOT EXISTS (SELECT 1 FROM seen WHERE seen.manga_id = manga.id)
+		ORDER BY array_position($2::bigint[], manga.id::bigint) NULLS LAST, manga.bayesian_score DESC, manga.id
+		LIMIT $3
+	`
+
+	recommendations := []*entity.Recommendation{}
+	if err := r.db.SelectContext(ctx, &recommendations, query, userID, pq.Int64Array(popularIDs), limit); err != nil {
+		r.log.Error("Ошибка подбора популярной манги", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения рекомендаций", err)
+	}
+
+	return recommendations, nil
+}
+
+// RebuildSimilarity пересчитывает таблицу похожести манги по совместному чтению.
+// Читателями манги считаются пользователи с историей чтения или закладкой на нее.
+// Мера — косинус между множествами читателей, умноженный на co/(co+similarityShrinkage),
+// чтобы пары с парой случайных общих читателей не вытесняли устойчивые.
+// Строка состояния блокируется на время пересчета, поэтому параллельный вызов
+// дожидается его окончания и пропускает уже выполненный пересчет
+func (r *RecommendationRepository) RebuildSimilarity(ctx context.Context, staleBefore time.Time) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+	defer tx.Rollback()
+
+	var computedAt sql.NullTime
+	if err = tx.GetContext(ctx, &computedAt, "SELECT computed_at FROM manga_similarity_state FOR UPDATE"); err != nil {
+		r.log.Error("Ошибка блокировки состояния похожести", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+	if computedAt.Valid && computedAt.Time.After(staleBefore) {
+		return false, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM manga_similarity"); err != nil {
+		r.log.Error("Ошибка очистки таблицы похожести", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+
+	query := `
+		INSERT INTO manga_similarity (manga_id, similar_manga_id, score, co_readers)
+		WITH interactions AS (
+			SELECT user_id, manga_id, MAX(last_at) AS last_at
+			FROM (
+				SELECT user_id, manga_id, read_at AS last_at FROM reading_history
+				UNION ALL
+				SELECT user_id, manga_id, updated_at FROM bookmarks
+			) s
+			GROUP BY user_id, manga_id
+		),
+		readers AS (
+			SELECT user_id, manga_id
+			FROM (
+				SELECT user_id, manga_id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY last_at DESC) AS position
+				FROM interactions
+			) ranked
+			WHERE position <= $1
+		),
+		audience AS (
+			SELECT manga_id, COUNT(*) AS readers FROM readers GROUP BY manga_id
+		),
+		pairs AS (
+			SELECT a.manga_id, b.manga_id AS similar_manga_id, COUNT(*) AS co_readers
+			FROM readers a
+			JOIN readers b ON b.user_id = a.user_id AND b.manga_id <> a.manga_id
+			GROUP BY a.manga_id, b.manga_id
+			HAVING COUNT(*) >= $2
+		),
+		scored AS (
+			SELECT p.manga_id, p.similar_manga_id, p.co_readers,
+			       p.co_readers / SQRT(aa.readers::float8 * ab.readers)
+			           * p.co_readers / (p.co_readers + $3::float8) AS score
+			FROM pairs p
+			JOIN audience aa ON aa.manga_id = p.manga_id
+			JOIN audience ab ON ab.manga_id = p.similar_manga_id
+		)
+		SELECT manga_id, similar_manga_id, score, co_readers
+		FROM (
+			SELECT scored.*, ROW_NUMBER() OVER (PARTITION BY manga_id ORDER BY score DESC, similar_manga_id) AS position
+			FROM scored
+		) ranked
+		WHERE position <= $4
+	`
+	result, err := tx.ExecContext(ctx, query, similarityUserItems, similarityMinCoReaders, similarityShrinkage, similarityNeighbors)
+	if err != nil {
+		r.log.Error("Ошибка пересчета похожести", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+
+	pairs, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE manga_similarity_state SET pair_count = $1, computed_at = NOW()", pairs); err != nil {
+		r.log.Error("Ошибка обновления состояния похожести", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return false, errors.NewDatabaseError("Ошибка пересчета похожести", err)
+	}
+
+	r.log.Info("Таблица похожести манги пересчитана", "pairs", pairs)
+	return true, nil
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// Веса составляющих оценки похожей манги
+const (
+	similarGenreWeight   = 0.7 // коэффициент Жаккара по жанрам
+	similarCreatorWeight = 0.3 // общий автор или художник
+)
+
+// MangaRelationRepository реализация интерфейса repository.MangaRelationRepository для PostgreSQL
+type MangaRelationRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewMangaRelationRepository создает новый экземпляр MangaRelationRepository
+func NewMangaRelationRepository(db *sqlx.DB, log logger.Logger) repository.MangaRelationRepository {
+	return &MangaRelationRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Set создает или изменяет связь в обе стороны в одной транзакции
+func (r *MangaRelationRepository) Set(ctx context.Context, mangaID, relatedID int64, relationType, inverseType string, createdBy int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения связи", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO manga_relations (manga_id, related_manga_id, relation_type, created_by, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (manga_id, related_manga_id) DO UPDATE
+		SET relation_type = EXCLUDED.relation_type, created_by = EXCLUDED.created_by
+	`
+	if _, err = tx.ExecContext(ctx, query, mangaID, relatedID, relationType, createdBy); err != nil {
+		r.log.Error("Ошибка сохранения связи", "error", err.Error(), "manga_id", mangaID, "related_manga_id", relatedID)
+		return errors.NewDatabaseError("Ошибка сохранения связи", err)
+	}
+	if _, err = tx.ExecContext(ctx, query, relatedID, mangaID, inverseType, createdBy); err != nil {
+		r.log.Error("Ошибка сохранения обратной связи", "error", err.Error(), "manga_id", relatedID, "related_manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка сохранения связи", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения связи", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет связь в обе стороны
+func (r *MangaRelationRepository) Delete(ctx context.Context, mangaID, relatedID int64) error {
+	query := `
+		DELETE FROM manga_relations
+		WHERE (manga_id = $1 AND related_manga_id = $2) OR (manga_id = $2 AND related_manga_id = $1)
+	`
+	result, err := r.db.ExecContext(ctx, query, mangaID, relatedID)
+	if err != nil {
+		r.log.Error("Ошибка удаления связи", "error", err.Error(), "manga_id", mangaID, "related_manga_id", relatedID)
+		return errors.NewDatabaseError("Ошибка удаления связи", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества удаленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления связи", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Связь не найдена", nil)
+	}
+
+	return nil
+}
+
+// ListRelations возвращает связанную мангу, упорядоченную по типу связи и названию
+func (r *MangaRelationRepository) ListRelations(ctx context.Context, mangaID int64) ([]*entity.MangaRelation, error) {
+	query := `
+		SELECT mr.manga_id, mr.related_manga_id, mr.relation_type, mr.created_at,
+		       m.title, COALESCE(m.cover_image, '') AS cover_image, m.status
+		FROM manga_relations mr
+		JOIN manga m ON m.id = mr.related_manga_id
+		WHERE mr.manga_id = $1
+		ORDER BY mr.relation_type, m.title, m.id
+	`
+
+	relations := []*entity.MangaRelation{}
+	if err := r.db.SelectContext(ctx, &relations, query, mangaID); err != nil {
+		r.log.Error("Ошибка получения связей манги", "error", err.Error(), "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка получения связей манги", err)
+	}
+
+	return relations, nil
+}
+
+// ListSimilar подбирает похожую мангу. Оценка складывается из коэффициента Жаккара
+// по множествам жанров и бонуса за общего автора или художника
+func (r *MangaRelationRepository) ListSimilar(ctx context.Context, mangaID int64, limit int) ([]*entity.SimilarManga, error) {
+	query := `
+		WITH target_genres AS (
+			SELECT genre_id FROM manga_genres WHERE manga_id = $1
+		),
+		shared AS (
+			SELECT mg.manga_id, COUNT(*) AS shared_genres
+			FROM manga_genres mg
+			JOIN target_genres tg ON tg.genre_id = mg.genre_id
+			WHERE mg.manga_id <> $1
+			GROUP BY mg.manga_id
+		),
+		genre_counts AS (
+			SELECT manga_id, COUNT(*) AS genres
+			FROM manga_genres
+			WHERE manga_id IN (SELECT manga_id FROM shared)
+			GROUP BY manga_id
+		),
+		creators AS (
+			SELECT DISTINCT other.manga_id
+			FROM manga_creators own
+			JOIN manga_creators other ON other.creator_id = own.creator_id
+			WHERE own.manga_id = $1 AND other.manga_id <> $1
+		),
+		candidates AS (
+			SELECT manga_id FROM shared
+			UNION
+			SELECT manga_id FROM creators
+		)
+		SELECT m.id AS manga_id, m.title, COALESCE(m.cover_image, '') AS cover_image, m.status,
+		       COALESCE(s.shared_genres, 0) AS shared_genres,
+		       cr.manga_id IS NOT NULL AS same_creator,
+		       $2 * COALESCE(s.shared_genres::float8 / (gc.genres + (SELECT COUNT(*) FROM target_genres) - s.shared_genres), 0)
+		           + CASE WHEN cr.manga_id IS NOT NULL THEN $3::float8 ELSE 0 END AS score
+		FROM candidates c
+		JOIN manga m ON m.id = c.manga_id
+		LEFT JOIN shared s ON s.manga_id = c.manga_id
+		LEFT JOIN genre_counts gc ON gc.manga_id = c.manga_id
+		LEFT JOIN creators cr ON cr.manga_id = c.manga_id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM manga_relations mr WHERE mr.manga_id = $1 AND mr.related_manga_id = c.manga_id
+		)
+		ORDER BY score DESC, m.bayesian_score DESC, m.id
+		LIMIT $4
+	`
+
+	similar := []*entity.SimilarManga{}
+	if err := r.db.SelectContext(ctx, &similar, query, mangaID, similarGenreWeight, similarCreatorWeight, limit); err != nil {
+		r.log.Error("Ошибка подбора похожей манги", "error", err.Error(), "manga_id", mangaID)
+		return nil, errors.NewDatabaseError("Ошибка подбора похожей манги", err)
+	}
+
+	return similar, nil
+}
@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// reviewSortOrders сопоставляет сортировку рецензий с выражением ORDER BY
+var reviewSortOrders = map[string]string{
+	entity.ReviewSortHelpful: "rv.helpful_count DESC, rv.created_at DESC, rv.id DESC",
+	entity.ReviewSortNewest:  "rv.created_at DESC, rv.id DESC",
+}
+
+// reviewColumns перечисляет поля рецензии с автором и его оценкой для выборок
+const reviewColumns = `
+	rv.id, rv.manga_id, rv.user_id, rv.title, rv.content, rv.content_html, rv.spoiler,
+	rv.helpful_count, rv.unhelpful_count, rv.created_at, rv.updated_at, u.username, mr.score AS rating`
+
+// reviewJoins присоединяет к рецензии автора и его оценку манги
+const reviewJoins = `
+	FROM manga_reviews rv
+	JOIN users u ON u.id = rv.user_id
+	LEFT JOIN manga_ratings mr ON mr.manga_id = rv.manga_id AND mr.user_id = rv.user_id`
+
+// ReviewRepository реализация интерфейса repository.ReviewRepository для PostgreSQL.
+// Счетчик рецензий манги и счетчики полезности обновляются в транзакции вместе с изменением
+type ReviewRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewReviewRepository создает новый экземпляр ReviewRepository
+func NewReviewRepository(db *sqlx.DB, log logger.Logger) repository.ReviewRepository {
+	return &ReviewRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create создает рецензию и увеличивает счетчик рецензий манги
+func (r *ReviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка создания рецензии", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO manga_reviews (manga_id, user_id, title, content, content_html, spoiler, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowxContext(ctx, query,
+		review.MangaID, review.UserID, review.Title, review.Content, review.ContentHTML, review.Spoiler,
+	).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.NewConflictError("Вы уже написали рецензию на эту мангу", nil)
+		}
+		r.log.Error("Ошибка создания рецензии", "error", err.Error(), "manga_id", review.MangaID, "user_id", review.UserID)
+		return errors.NewDatabaseError("Ошибка создания рецензии", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE manga SET review_count = review_count + 1 WHERE id = $1", review.MangaID); err != nil {
+		r.log.Error("Ошибка обновления счетчика рецензий", "error", err.Error(), "manga_id", review.MangaID)
+		return errors.NewDatabaseError("Ошибка создания рецензии", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка создания рецензии", err)
+	}
+
+	return nil
+}
+
+// GetByID получает рецензию с автором по идентификатору
+func (r *ReviewRepository) GetByID(ctx context.Context, id int64) (*entity.ReviewWithUser, error) {
+	query := "SELECT " + reviewColumns + reviewJoins + " WHERE rv.id = $1"
+
+	var review entity.ReviewWithUser
+	if err := r.db.GetContext(ctx, &review, query, id); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Рецензия не найдена", nil)
+		}
+		r.log.Error("Ошибка получения рецензии", "error", err.Error(), "id", id)
+		return nil, errors.NewDatabaseError("Ошибка получения рецензии", err)
+	}
+
+	return &review, nil
+}
+
+// List возвращает страницу рецензий манги и их общее количество
+func (r *ReviewRepository) List(ctx context.Context, filter entity.ReviewFilter) ([]*entity.ReviewWithUser, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM manga_reviews WHERE manga_id = $1", filter.MangaID); err != nil {
+		r.log.Error("Ошибка подсчета рецензий", "error", err.Error(), "manga_id", filter.MangaID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения рецензий", err)
+	}
+
+	orderBy, ok := reviewSortOrders[filter.Sort]
+	if !ok {
+		orderBy = reviewSortOrders[entity.ReviewSortHelpful]
+	}
+
+	query := "SELECT " + reviewColumns + reviewJoins + `
+		WHERE rv.manga_id = $1
+		ORDER BY ` + orderBy + `
+		LIMIT $2 OFFSET $3`
+
+	var reviews []*entity.ReviewWithUser
+	if err := r.db.SelectContext(ctx, &reviews, query, filter.MangaID, filter.Limit, filter.Offset); err != nil {
+		r.log.Error("Ошибка получения рецензий", "error", err.Error(), "manga_id", filter.MangaID)
+		return nil, 0, errors.NewDatabaseError("Ошибка получения рецензий", err)
+	}
+
+	return reviews, total, nil
+}
+
+// Update обновляет заголовок и текст рецензии
+func (r *ReviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	query := `
+		UPDATE manga_reviews
+		SET title = $2, content = $3, content_html = $4, spoiler = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err := r.db.QueryRowxContext(ctx, query, review.ID, review.Title, review.Content, review.ContentHTML, review.Spoiler).
+		Scan(&review.UpdatedAt)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Рецензия не найдена", nil)
+		}
+		r.log.Error("Ошибка обновления рецензии", "error", err.Error(), "id", review.ID)
+		return errors.NewDatabaseError("Ошибка обновления рецензии", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет рецензию вместе с оценками полезности и уменьшает счетчик рецензий манги
+func (r *ReviewRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления рецензии", err)
+	}
+	defer tx.Rollback()
+
+	var mangaID int64
+	if err = tx.GetContext(ctx, &mangaID, "DELETE FROM manga_reviews WHERE id = $1 RETURNING manga_id", id); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return errors.NewNotFoundError("Рецензия не найдена", nil)
+		}
+		r.log.Error("Ошибка удаления рецензии", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка удаления рецензии", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE manga SET review_count = review_count - 1 WHERE id = $1", mangaID); err != nil {
+		r.log.Error("Ошибка обновления счетчика рецензий", "error", err.Error(), "manga_id", mangaID)
+		return errors.NewDatabaseError("Ошибка удаления рецензии", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления рецензии", err)
+	}
+
+	return nil
+}
+
+// Vote сохраняет оценку полезности (1 или -1, 0 снимает оценку) и инкрементально обновляет счетчики.
+// Строка рецензии блокируется, поэтому одновременные голоса не искажают счетчики
+func (r *ReviewRepository) Vote(ctx context.Context, userID, reviewID int64, value int) (*entity.ReviewVoteResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка оценки рецензии", err)
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	if err = tx.GetContext(ctx, &locked, "SELECT id FROM manga_reviews WHERE id = $1 FOR UPDATE", reviewID); err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Рецензия не найдена", nil)
+		}
+		r.log.Error("Ошибка блокировки рецензии", "error", err.Error(), "review_id", reviewID)
+		return nil, errors.NewDatabaseError("Ошибка оценки рецензии", err)
+	}
+
+	var previous int
+	err = tx.GetContext(ctx, &previous, "SELECT value FROM review_votes WHERE user_id = $1 AND review_id = $2", userID, reviewID)
+	if err != nil && !stderrors.Is(err, sql.ErrNoRows) {
+		r.log.Error("Ошибка получения оценки рецензии", "error", err.Error(), "user_id", userID, "review_id", reviewID)
+		return nil, errors.NewDatabaseError("Ошибка оценки рецензии", err)
+	}
+
+	if value == 0 {
+		_, err = tx.ExecContext(ctx, "DELETE FROM review_votes WHERE user_id = $1 AND review_id = $2", userID, reviewID)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO review_votes (user_id, review_id, value, created_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (user_id, review_id) DO UPDATE SET value = EXCLUDED.value, created_at = NOW()
+		`, userID, reviewID, value)
+	}
+	if err != nil {
+		r.log.Error("Ошибка сохранения оценки рецензии", "error", err.Error(), "user_id", userID, "review_id", reviewID)
+		return nil, errors.NewDatabaseError("Ошибка оценки рецензии", err)
+	}
+
+	query := `
+		UPDATE manga_reviews
+		SET helpful_count = helpful_count + $2, unhelpful_count = unhelpful_count + $3
+		WHERE id = $1
+		RETURNING id, helpful_count, unhelpful_count
+	`
+	helpfulDelta := voteCount(value, 1) - voteCount(previous, 1)
+	unhelpfulDelta := voteCount(value, -1) - voteCount(previous, -1)
+
+	result := &entity.ReviewVoteResult{MyVote: value}
+	if err = tx.QueryRowxContext(ctx, query, reviewID, helpfulDelta, unhelpfulDelta).StructScan(result); err != nil {
+		r.log.Error("Ошибка обновления счетчиков полезности", "error", err.Error(), "review_id", reviewID)
+		return nil, errors.NewDatabaseError("Ошибка оценки рецензии", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка оценки рецензии", err)
+	}
+
+	return result, nil
+}
+
+// ListUserVotes возвращает оценки полезности, поставленные пользователем перечисленным рецензиям
+func (r *ReviewRepository) ListUserVotes(ctx context.Context, userID int64, reviewIDs []int64) (map[int64]int, error) {
+	votes := make(map[int64]int)
+	if len(reviewIDs) == 0 {
+		return votes, nil
+	}
+
+	query := "SELECT review_id, value FROM review_votes WHERE user_id = $1 AND review_id = ANY($2)"
+	rows, err := r.db.QueryxContext(ctx, query, userID, pq.Array(reviewIDs))
+	if err != nil {
+		r.log.Error("Ошибка получения оценок рецензий", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения оценок рецензий", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewID int64
+		var value int
+		if err = rows.Scan(&reviewID, &value); err != nil {
+			r.log.Error("Ошибка чтения оценки рецензии", "error", err.Error(), "user_id", userID)
+			return nil, errors.NewDatabaseError("Ошибка получения оценок рецензий", err)
+		}
+		votes[reviewID] = value
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error("Ошибка получения оценок рецензий", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения оценок рецензий", err)
+	}
+
+	return votes, nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// RoleRepository реализация интерфейса repository.RoleRepository для PostgreSQL
+type RoleRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewRoleRepository создает новый экземпляр RoleRepository
+func NewRoleRepository(db *sqlx.DB, log logger.Logger) repository.RoleRepository {
+	return &RoleRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// List получает список ролей с разрешениями
+func (r *RoleRepository) List(ctx context.Context) ([]*entity.Role, error) {
+	query := `
+		SELECT name, COALESCE(description, '') AS description
+		FROM roles
+		ORDER BY created_at, name
+	`
+
+	var roles []*entity.Role
+	if err := r.db.SelectContext(ctx, &roles, query); err != nil {
+		r.log.Error("Ошибка получения списка ролей", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка получения списка ролей", err)
+	}
+
+	for _, role := range roles {
+		permissions, err := r.GetPermissions(ctx, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+
+	return roles, nil
+}
+
+// Exists проверяет существование роли
+func (r *RoleRepository) Exists(ctx context.Context, role string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM roles WHERE name = $1)`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, role); err != nil {
+		r.log.Error("Ошибка проверки существования роли", "error", err.Error(), "role", role)
+		return false, errors.NewDatabaseError("Ошибка проверки существования роли", err)
+	}
+
+	return exists, nil
+}
+
+// GetPermissions получает список разрешений роли
+func (r *RoleRepository) GetPermissions(ctx context.Context, role string) ([]string, error) {
+	query := `
+		SELECT permission
+		FROM role_permissions
+		WHERE role = $1
+		ORDER BY permission
+	`
+
+	permissions := []string{}
+	if err := r.db.SelectContext(ctx, &permissions, query, role); err != nil {
+		r.log.Error("Ошибка получения разрешений роли", "error", err.Error(), "role", role)
+		return nil, errors.NewDatabaseError("Ошибка получения разрешений роли", err)
+	}
+
+	return permissions, nil
+}
+
+// SetPermissions заменяет набор разрешений роли
+func (r *RoleRepository) SetPermissions(ctx context.Context, role string, permissions []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка изменения разрешений роли", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE role = $1", role); err != nil {
+		r.log.Error("Ошибка удаления разрешений роли", "error", err.Error(), "role", role)
+		return errors.NewDatabaseError("Ошибка изменения разрешений роли", err)
+	}
+
+	for _, permission := range permissions {
+		if _, err = tx.ExecContext(ctx, "INSERT INTO role_permissions (role, permission) VALUES ($1, $2)", role, permission); err != nil {
+			r.log.Error("Ошибка добавления разрешения роли", "error", err.Error(), "role", role, "permission", permission)
+			return errors.NewDatabaseError("Ошибка изменения разрешений роли", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка изменения разрешений роли", err)
+	}
+
+	return nil
+}
+
+// ListPermissions получает список всех известных разрешений
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]string, error) {
+	permissions := []string{}
+	if err := r.db.SelectContext(ctx, &permissions, "SELECT name FROM permissions ORDER BY name"); err != nil {
+		r.log.Error("Ошибка получения списка разрешений", "error", err.Error())
+		return nil, errors.NewDatabaseError("Ошибка получения списка разрешений", err)
+	}
+
+	return permissions, nil
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	stderrors "errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"manga-reader2/internal/common/errors"
 	"manga-reader2/internal/common/logger"
 	"manga-reader2/internal/domain/entity"
 	"manga-reader2/internal/domain/repository"
+	"strings"
 	"time"
 )
 
@@ -29,8 +32,8 @@ func NewUserRepository(db *sqlx.DB, log logger.Logger) repository.UserRepository
 // Create создает нового пользователя в базе данных
 func (r *UserRepository) Create(ctx context.Context, user *entity.User) (int64, error) {
 	query := `
-		INSERT INTO users (username, email, password_hash, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		INSERT INTO users (username, email, email_verified, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
 		RETURNING id, created_at, updated_at
 	`
 
@@ -42,6 +45,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) (int64,
 		query,
 		user.Username,
 		user.Email,
+		user.EmailVerified,
 		user.Password,
 		user.Role,
 	).Scan(&id, &createdAt, &updatedAt)
@@ -68,7 +72,8 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) (int64,
 // GetByID получает пользователя по идентификатору
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, role, created_at, updated_at
+		SELECT id, username, email, email_verified, password_hash, role, ban_reason, banned_at, banned_until, banned_by,
+		       deleted_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -90,7 +95,8 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*entity.User, e
 // GetByUsername получает пользователя по имени пользователя
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, role, created_at, updated_at
+		SELECT id, username, email, email_verified, password_hash, role, ban_reason, banned_at, banned_until, banned_by,
+		       deleted_at, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -112,7 +118,8 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*e
 // GetByEmail получает пользователя по email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, role, created_at, updated_at
+		SELECT id, username, email, email_verified, password_hash, role, ban_reason, banned_at, banned_until, banned_by,
+		       deleted_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -135,7 +142,9 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.
 func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users 
-		SET username = $1, email = $2, password_hash = $3, role = $4, updated_at = NOW()
+		SET username = $1, email = $2, password_hash = $3, role = $4, updated_at = NOW(),
+		    -- смена адреса снимает подтверждение
+		    email_verified = email_verified AND email = $2
 		WHERE id = $5
 		RETURNING updated_at
 	`
@@ -200,3 +209,235 @@ func (r *UserRepository) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// List возвращает страницу пользователей по фильтру и общее количество найденных
+func (r *UserRepository) List(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int, error) {
+	var where []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Query != "" {
+		where = append(where, fmt.Sprintf("(username ILIKE $%d OR email ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+filter.Query+"%")
+		argIndex++
+	}
+
+	if filter.Role != "" {
+		where = append(where, fmt.Sprintf("role = $%d", argIndex))
+		args = append(args, filter.Role)
+		argIndex++
+	}
+
+	if filter.CreatedAfter != nil {
+		where = append(where, fmt.Sprintf("created_at >= $%d", argIndex))
+		args = append(args, *filter.CreatedAfter)
+		argIndex++
+	}
+
+	if filter.CreatedBefore != nil {
+		where = append(where, fmt.Sprintf("created_at < $%d", argIndex))
+		args = append(args, *filter.CreatedBefore)
+		argIndex++
+	}
+
+	switch filter.Status {
+	case entity.UserStatusDeleted:
+		where = append(where, "deleted_at IS NOT NULL")
+	case entity.UserStatusBanned:
+		where = append(where, "deleted_at IS NULL AND banned_at IS NOT NULL AND (banned_until IS NULL OR banned_until > NOW())")
+	case entity.UserStatusActive:
+		where = append(where, "deleted_at IS NULL AND (banned_at IS NULL OR banned_until <= NOW())")
+	default:
+		where = append(where, "deleted_at IS NULL")
+	}
+
+	whereClause := "WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM users "+whereClause, args...); err != nil {
+		r.log.Error("Ошибка подсчета пользователей", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения списка пользователей", err)
+	}
+
+	query := `
+		SELECT id, username, email, email_verified, password_hash, role, ban_reason, banned_at, banned_until, banned_by,
+		       deleted_at, created_at, updated_at
+		FROM users ` + whereClause + `
+		ORDER BY created_at DESC, id DESC`
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	var users []*entity.User
+	if err := r.db.SelectContext(ctx, &users, query, args...); err != nil {
+		r.log.Error("Ошибка получения списка пользователей", "error", err.Error())
+		return nil, 0, errors.NewDatabaseError("Ошибка получения списка пользователей", err)
+	}
+
+	return users, total, nil
+}
+
+// Ban блокирует пользователя до указанного времени или бессрочно
+func (r *UserRepository) Ban(ctx context.Context, id int64, ban *entity.UserBan, bannedBy int64) error {
+	return banUser(ctx, r.db, r.log, id, ban, bannedBy)
+}
+
+// Unban снимает блокировку с пользователя
+func (r *UserRepository) Unban(ctx context.Context, id int64) error {
+	query := `
+		UPDATE users
+		SET ban_reason = '', banned_at = NULL, banned_until = NULL, banned_by = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.log.Error("Ошибка снятия блокировки пользователя", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка снятия блокировки пользователя", err)
+	}
+
+	return r.checkAffected(result, id, "Ошибка снятия блокировки пользователя")
+}
+
+// Anonymize помечает пользователя удаленным и стирает его персональные данные.
+// Запись пользователя сохраняется, чтобы его комментарии остались привязаны к «удаленному пользователю»
+func (r *UserRepository) Anonymize(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.Error("Ошибка начала транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления пользователя", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET username = 'deleted_' || id,
+		    email = 'deleted_' || id || '@deleted.invalid', email_verified = FALSE,
+		    password_hash = '',
+		    role = 'reader',
+		    ban_reason = '', banned_at = NULL, banned_until = NULL, banned_by = NULL,
+		    deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		r.log.Error("Ошибка анонимизации пользователя", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка удаления пользователя", err)
+	}
+	if err = r.checkAffected(result, id, "Ошибка удаления пользователя"); err != nil {
+		return err
+	}
+
+	// Персональные данные, не нужные для атрибуции комментариев
+	cleanup := []string{
+		"DELETE FROM user_identities WHERE user_id = $1",
+		"DELETE FROM sessions WHERE user_id = $1",
+		"DELETE FROM bookmarks WHERE user_id = $1",
+		"DELETE FROM reading_history WHERE user_id = $1",
+		"DELETE FROM reading_progress WHERE user_id = $1",
+		"DELETE FROM chapter_reads WHERE user_id = $1",
+		"DELETE FROM manga_follows WHERE user_id = $1",
+		"DELETE FROM notifications WHERE user_id = $1",
+		"DELETE FROM library_entries WHERE user_id = $1",
+		"DELETE FROM library_shelves WHERE user_id = $1",
+		"DELETE FROM manga_uploaders WHERE user_id = $1",
+		"UPDATE api_keys SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL",
+		"UPDATE manga_views SET ip_address = NULL WHERE user_id = $1",
+		"UPDATE chapter_views SET ip_address = NULL WHERE user_id = $1",
+	}
+	for _, stmt := range cleanup {
+		if _, err = tx.ExecContext(ctx, stmt, id); err != nil {
+			r.log.Error("Ошибка удаления персональных данных пользователя", "error", err.Error(), "id", id)
+			return errors.NewDatabaseError("Ошибка удаления пользователя", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.log.Error("Ошибка фиксации транзакции", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка удаления пользователя", err)
+	}
+
+	return nil
+}
+
+// checkAffected возвращает ошибку "не найден", если запрос не изменил ни одной строки
+func (r *UserRepository) checkAffected(result sql.Result, id int64, msg string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return errors.NewDatabaseError(msg, err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewUserNotFoundError(id)
+	}
+
+	return nil
+}
+
+// GetPreferredLanguages возвращает предпочитаемые языки глав пользователя
+func (r *UserRepository) GetPreferredLanguages(ctx context.Context, id int64) ([]string, error) {
+	var languages pq.StringArray
+	err := r.db.GetContext(ctx, &languages, "SELECT preferred_languages FROM users WHERE id = $1", id)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewUserNotFoundError(id)
+		}
+		r.log.Error("Ошибка получения языков пользователя", "error", err.Error(), "id", id)
+		return nil, errors.NewDatabaseError("Ошибка получения языков пользователя", err)
+	}
+
+	return []string(languages), nil
+}
+
+// SetPreferredLanguages сохраняет предпочитаемые языки глав пользователя
+func (r *UserRepository) SetPreferredLanguages(ctx context.Context, id int64, languages []string) error {
+	query := "UPDATE users SET preferred_languages = $1, updated_at = NOW() WHERE id = $2"
+
+	result, err := r.db.ExecContext(ctx, query, pq.StringArray(languages), id)
+	if err != nil {
+		r.log.Error("Ошибка сохранения языков пользователя", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка сохранения языков пользователя", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error("Ошибка получения количества обновленных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка сохранения языков пользователя", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewUserNotFoundError(id)
+	}
+
+	return nil
+}
+
+// banUser блокирует пользователя запросом через db, которым может быть и транзакция
+func banUser(ctx context.Context, db sqlx.ExecerContext, log logger.Logger, id int64, ban *entity.UserBan, bannedBy int64) error {
+	query := `
+		UPDATE users
+		SET ban_reason = $1, banned_at = NOW(), banned_until = $2, banned_by = $3, updated_at = NOW()
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+
+	result, err := db.ExecContext(ctx, query, ban.Reason, ban.Until, bannedBy, id)
+	if err != nil {
+		log.Error("Ошибка блокировки пользователя", "error", err.Error(), "id", id)
+		return errors.NewDatabaseError("Ошибка блокировки пользователя", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества измененных строк", "error", err.Error())
+		return errors.NewDatabaseError("Ошибка блокировки пользователя", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewUserNotFoundError(id)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"github.com/jmoiron/sqlx"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// UserIdentityRepository реализация интерфейса repository.UserIdentityRepository для PostgreSQL
+type UserIdentityRepository struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+// NewUserIdentityRepository создает новый экземпляр UserIdentityRepository
+func NewUserIdentityRepository(db *sqlx.DB, log logger.Logger) repository.UserIdentityRepository {
+	return &UserIdentityRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Create привязывает внешнюю учетную запись к пользователю
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) (int64, error) {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+
+	var id int64
+	var createdAt, updatedAt time.Time
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+	).Scan(&id, &createdAt, &updatedAt)
+
+	if err != nil {
+		r.log.Error("Ошибка привязки внешней учетной записи", "error", err.Error(), "user_id", identity.UserID, "provider", identity.Provider)
+		return 0, errors.NewDatabaseError("Ошибка привязки внешней учетной записи", err)
+	}
+
+	identity.ID = id
+	identity.CreatedAt = createdAt
+	identity.UpdatedAt = updatedAt
+
+	return id, nil
+}
+
+// GetByProviderSubject получает внешнюю учетную запись по провайдеру и идентификатору субъекта
+func (r *UserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, COALESCE(email, '') AS email, created_at, updated_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	var identity entity.UserIdentity
+	err := r.db.GetContext(ctx, &identity, query, provider, subject)
+
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return nil, errors.NewNotFoundError("Внешняя учетная запись не найдена", nil)
+		}
+		r.log.Error("Ошибка получения внешней учетной записи", "error", err.Error(), "provider", provider)
+		return nil, errors.NewDatabaseError("Ошибка получения внешней учетной записи", err)
+	}
+
+	return &identity, nil
+}
+
+// ListByUser получает список внешних учетных записей пользователя
+func (r *UserIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]*entity.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, COALESCE(email, '') AS email, created_at, updated_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	var identities []*entity.UserIdentity
+	err := r.db.SelectContext(ctx, &identities, query, userID)
+
+	if err != nil {
+		r.log.Error("Ошибка получения внешних учетных записей", "error", err.Error(), "user_id", userID)
+		return nil, errors.NewDatabaseError("Ошибка получения внешних учетных записей", err)
+	}
+
+	return identities, nil
+}
@@ -1 +1,245 @@
 package redis
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"manga-reader2/internal/infrastructure/db"
+	"strconv"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Виды учитываемых просмотров
+const (
+	analyticsKindManga   = "manga"
+	analyticsKindChapter = "chapter"
+	analyticsKindPage    = "page"
+)
+
+// AnalyticsRepository реализация интерфейса repository.AnalyticsRepository для Redis.
+// Просмотры каждого вида хранятся в отсортированных множествах по периодам (analytics:{вид}:{период}),
+// а суммарные счетчики — в ключах analytics:total:{вид}:{период}. Сброс периода удаляет его ключи
+type AnalyticsRepository struct {
+	client *db.RedisClient
+	log    logger.Logger
+}
+
+// NewAnalyticsRepository создает новый экземпляр AnalyticsRepository
+func NewAnalyticsRepository(client *db.RedisClient, log logger.Logger) repository.AnalyticsRepository {
+	return &AnalyticsRepository{
+		client: client,
+		log:    log,
+	}
+}
+
+// RecordMangaView учитывает просмотр манги
+func (r *AnalyticsRepository) RecordMangaView(ctx context.Context, mangaID int64) error {
+	return r.record(ctx, analyticsKindManga, mangaID)
+}
+
+// RecordChapterView учитывает просмотр главы
+func (r *AnalyticsRepository) RecordChapterView(ctx context.Context, chapterID, mangaID int64) error {
+	return r.record(ctx, analyticsKindChapter, chapterID)
+}
+
+// RecordPageView учитывает просмотр страницы
+func (r *AnalyticsRepository) RecordPageView(ctx context.Context, pageID, chapterID, mangaID int64) error {
+	return r.record(ctx, analyticsKindPage, pageID)
+}
+
+// GetMangaViews возвращает число просмотров манги за все время
+func (r *AnalyticsRepository) GetMangaViews(ctx context.Context, mangaID int64) (int64, error) {
+	return r.views(ctx, analyticsKindManga, mangaID)
+}
+
+// GetChapterViews возвращает число просмотров главы за все время
+func (r *AnalyticsRepository) GetChapterViews(ctx context.Context, chapterID int64) (int64, error) {
+	return r.views(ctx, analyticsKindChapter, chapterID)
+}
+
+// GetChapterViewsBatch возвращает число просмотров за все время для каждой из глав одним обращением к Redis
+func (r *AnalyticsRepository) GetChapterViewsBatch(ctx context.Context, chapterIDs []int64) (map[int64]int64, error) {
+	views := make(map[int64]int64, len(chapterIDs))
+	if len(chapterIDs) == 0 {
+		return views, nil
+	}
+
+	key := analyticsKey(analyticsKindChapter, entity.StatsPeriodAllTime)
+	cmds := make([]*goredis.FloatCmd, len(chapterIDs))
+	err := r.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, id := range chapterIDs {
+			cmds[i] = pipe.ZScore(ctx, key, strconv.FormatInt(id, 10))
+		}
+		return nil
+	})
+	if err != nil && !stderrors.Is(err, goredis.Nil) {
+		r.log.Error("Ошибка получения числа просмотров глав", "error", err.Error())
+		return nil, errors.NewInternalError("Ошибка получения числа просмотров глав", err)
+	}
+
+	for i, cmd := range cmds {
+		// Глава без просмотров отсутствует в множестве
+		if score, err := cmd.Result(); err == nil {
+			views[chapterIDs[i]] = int64(score)
+		}
+	}
+
+	return views, nil
+}
+
+// GetTopManga возвращает самую просматриваемую мангу за период.
+// Названия не хранятся в Redis и заполняются вызывающей стороной
+func (r *AnalyticsRepository) GetTopManga(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.MangaStat, error) {
+	top, err := r.top(ctx, analyticsKindManga, period, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*entity.MangaStat, 0, len(top))
+	for _, item := range top {
+		stats = append(stats, &entity.MangaStat{MangaID: item.id, Views: item.views})
+	}
+
+	return stats, nil
+}
+
+// GetTopChapters возвращает самые просматриваемые главы за период.
+// Манга, номер и название главы заполняются вызывающей стороной
+func (r *AnalyticsRepository) GetTopChapters(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.ChapterStat, error) {
+	top, err := r.top(ctx, analyticsKindChapter, period, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*entity.ChapterStat, 0, len(top))
+	for _, item := range top {
+		stats = append(stats, &entity.ChapterStat{ChapterID: item.id, Views: item.views})
+	}
+
+	return stats, nil
+}
+
+// GetTotals возвращает суммарное число просмотров за период
+func (r *AnalyticsRepository) GetTotals(ctx context.Context, period entity.StatsPeriod) (*entity.AnalyticsTotals, error) {
+	totals := &entity.AnalyticsTotals{Period: period}
+	targets := map[string]*int64{
+		analyticsKindManga:   &totals.MangaViews,
+		analyticsKindChapter: &totals.ChapterViews,
+		analyticsKindPage:    &totals.PageViews,
+	}
+
+	for kind, target := range targets {
+		value, err := r.client.Get(ctx, analyticsTotalKey(kind, period))
+		if err != nil {
+			if stderrors.Is(err, goredis.Nil) {
+				continue
+			}
+			r.log.Error("Ошибка получения счетчика просмотров", "error", err.Error(), "kind", kind, "period", period)
+			return nil, errors.NewInternalError("Ошибка получения статистики", err)
+		}
+
+		if *target, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, errors.NewInternalError("Некорректный счетчик просмотров", err)
+		}
+	}
+
+	return totals, nil
+}
+
+// ResetStats удаляет статистику за период
+func (r *AnalyticsRepository) ResetStats(ctx context.Context, period entity.StatsPeriod) error {
+	keys := make([]string, 0, 6)
+	for _, kind := range []string{analyticsKindManga, analyticsKindChapter, analyticsKindPage} {
+		keys = append(keys, analyticsKey(kind, period), analyticsTotalKey(kind, period))
+	}
+
+	if err := r.client.Delete(ctx, keys...); err != nil {
+		r.log.Error("Ошибка сброса статистики", "error", err.Error(), "period", period)
+		return errors.NewInternalError("Ошибка сброса статистики", err)
+	}
+
+	return nil
+}
+
+// record увеличивает счетчики объекта и суммарные счетчики во всех периодах одним обменом с Redis
+func (r *AnalyticsRepository) record(ctx context.Context, kind string, id int64) error {
+	member := strconv.FormatInt(id, 10)
+
+	err := r.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, period := range entity.StatsPeriods {
+			pipe.ZIncrBy(ctx, analyticsKey(kind, period), 1, member)
+			pipe.Incr(ctx, analyticsTotalKey(kind, period))
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Ошибка учета просмотра", "error", err.Error(), "kind", kind, "id", id)
+		return errors.NewInternalError("Ошибка учета просмотра", err)
+	}
+
+	return nil
+}
+
+// views возвращает число просмотров объекта за все время
+func (r *AnalyticsRepository) views(ctx context.Context, kind string, id int64) (int64, error) {
+	score, err := r.client.ZScore(ctx, analyticsKey(kind, entity.StatsPeriodAllTime), strconv.FormatInt(id, 10))
+	if err != nil {
+		if stderrors.Is(err, goredis.Nil) {
+			return 0, nil
+		}
+		r.log.Error("Ошибка получения числа просмотров", "error", err.Error(), "kind", kind, "id", id)
+		return 0, errors.NewInternalError("Ошибка получения числа просмотров", err)
+	}
+
+	return int64(score), nil
+}
+
+// analyticsTopItem представляет объект из рейтинга просмотров
+type analyticsTopItem struct {
+	id    int64
+	views int64
+}
+
+// top возвращает limit объектов с наибольшим числом просмотров за период
+func (r *AnalyticsRepository) top(ctx context.Context, kind string, period entity.StatsPeriod, limit int) ([]analyticsTopItem, error) {
+	if limit <= 0 {
+		return []analyticsTopItem{}, nil
+	}
+
+	members, err := r.client.ZRevRangeWithScores(ctx, analyticsKey(kind, period), 0, int64(limit-1))
+	if err != nil {
+		r.log.Error("Ошибка получения рейтинга просмотров", "error", err.Error(), "kind", kind, "period", period)
+		return nil, errors.NewInternalError("Ошибка получения статистики", err)
+	}
+
+	items := make([]analyticsTopItem, 0, len(members))
+	for _, member := range members {
+		value, ok := member.Member.(string)
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			r.log.Warn("Некорректный элемент рейтинга просмотров", "kind", kind, "member", value)
+			continue
+		}
+		items = append(items, analyticsTopItem{id: id, views: int64(member.Score)})
+	}
+
+	return items, nil
+}
+
+// analyticsKey возвращает ключ отсортированного множества просмотров
+func analyticsKey(kind string, period entity.StatsPeriod) string {
+	return fmt.Sprintf("analytics:%s:%s", kind, period)
+}
+
+// analyticsTotalKey возвращает ключ суммарного счетчика просмотров
+func analyticsTotalKey(kind string, period entity.StatsPeriod) string {
+	return fmt.Sprintf("analytics:total:%s:%s", kind, period)
+}
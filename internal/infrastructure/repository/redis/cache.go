@@ -6,6 +6,8 @@ import (
 	"manga-reader2/internal/domain/repository"
 	"manga-reader2/internal/infrastructure/db"
 	"time"
+
+	goredis "github.com/redis/go-redis/v9"
 )
 
 // CacheRepository реализация интерфейса repository.CacheRepository для Redis
@@ -68,6 +70,28 @@ func (r *CacheRepository) Exists(ctx context.Context, key string) (bool, error)
 	return exists, nil
 }
 
+// Expire устанавливает время жизни ключа
+func (r *CacheRepository) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	_, err := r.client.Expire(ctx, key, expiration)
+	if err != nil {
+		r.log.Error("Ошибка установки времени жизни ключа в Redis", "key", key, "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// TTL возвращает оставшееся время жизни ключа
+func (r *CacheRepository) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key)
+	if err != nil {
+		r.log.Error("Ошибка получения времени жизни ключа из Redis", "key", key, "error", err.Error())
+		return 0, err
+	}
+
+	return ttl, nil
+}
+
 // Incr увеличивает значение ключа на 1
 func (r *CacheRepository) Incr(ctx context.Context, key string) (int64, error) {
 	value, err := r.client.Incr(ctx, key)
@@ -79,6 +103,23 @@ func (r *CacheRepository) Incr(ctx context.Context, key string) (int64, error) {
 	return value, nil
 }
 
+// IncrWithTTL увеличивает значение ключа на 1 и задает срок жизни, если он еще не задан.
+// INCR и EXPIRE NX отправляются одним обменом, поэтому срок жизни не теряется при сбое между командами
+func (r *CacheRepository) IncrWithTTL(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var incr *goredis.IntCmd
+	err := r.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Ошибка инкремента значения со сроком жизни в Redis", "key", key, "error", err.Error())
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
 // IncrBy увеличивает значение ключа на указанное число
 func (r *CacheRepository) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
 	result, err := r.client.IncrBy(ctx, key, value)
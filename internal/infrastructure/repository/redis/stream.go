@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"manga-reader2/internal/infrastructure/db"
+	"strconv"
+	"time"
+)
+
+// Ключи и канал шины событий
+const (
+	streamChannel = "stream:events"
+	streamSeqKey  = "stream:events:seq"
+	streamLogKey  = "stream:events:log"
+	// streamLogSize ограничивает журнал событий, доступных для возобновления по Last-Event-ID
+	streamLogSize = 10000
+)
+
+// StreamRepository реализация интерфейса repository.StreamRepository на Redis pub/sub.
+// Последние события дополнительно хранятся в отсортированном множестве по ID для возобновления потока
+type StreamRepository struct {
+	client *db.RedisClient
+	log    logger.Logger
+}
+
+// NewStreamRepository создает новый экземпляр StreamRepository
+func NewStreamRepository(client *db.RedisClient, log logger.Logger) repository.StreamRepository {
+	return &StreamRepository{
+		client: client,
+		log:    log,
+	}
+}
+
+// Publish присваивает событию ID, сохраняет его в журнале и публикует в канал
+func (r *StreamRepository) Publish(ctx context.Context, event *entity.StreamEvent) error {
+	id, err := r.client.Incr(ctx, streamSeqKey)
+	if err != nil {
+		r.log.Error("Ошибка получения ID события", "error", err.Error())
+		return errors.NewInternalError("Ошибка публикации события", err)
+	}
+	event.ID = id
+	event.CreatedAt = time.Now()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.NewInternalError("Ошибка сериализации события", err)
+	}
+
+	if err = r.client.ZAdd(ctx, streamLogKey, float64(id), string(payload)); err != nil {
+		r.log.Error("Ошибка сохранения события в журнал", "error", err.Error(), "event_id", id)
+		return errors.NewInternalError("Ошибка публикации события", err)
+	}
+	if err = r.client.ZRemRangeByRank(ctx, streamLogKey, 0, -streamLogSize-1); err != nil {
+		r.log.Warn("Ошибка очистки журнала событий", "error", err.Error())
+	}
+
+	if err = r.client.Publish(ctx, streamChannel, string(payload)); err != nil {
+		r.log.Error("Ошибка публикации события", "error", err.Error(), "event_id", id)
+		return errors.NewInternalError("Ошибка публикации события", err)
+	}
+
+	return nil
+}
+
+// Subscribe подписывается на канал событий
+func (r *StreamRepository) Subscribe(ctx context.Context) (<-chan *entity.StreamEvent, error) {
+	pubsub := r.client.Subscribe(ctx, streamChannel)
+	// Receive дожидается подтверждения подписки, чтобы не потерять события, опубликованные сразу после возврата
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		r.log.Error("Ошибка подписки на канал событий", "error", err.Error())
+		return nil, errors.NewInternalError("Ошибка подписки на события", err)
+	}
+
+	events := make(chan *entity.StreamEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event entity.StreamEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					r.log.Warn("Некорректное событие в канале", "error", err.Error())
+					continue
+				}
+
+				select {
+				case events <- &event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Since возвращает события из журнала после указанного ID
+func (r *StreamRepository) Since(ctx context.Context, afterID int64, limit int) ([]*entity.StreamEvent, bool, error) {
+	oldest, err := r.client.ZRangeWithScores(ctx, streamLogKey, 0, 0)
+	if err != nil {
+		r.log.Error("Ошибка чтения журнала событий", "error", err.Error())
+		return nil, false, errors.NewInternalError("Ошибка получения событий", err)
+	}
+	if len(oldest) == 0 {
+		return []*entity.StreamEvent{}, false, nil
+	}
+	truncated := int64(oldest[0].Score) > afterID+1
+
+	members, err := r.client.ZRangeByScore(ctx, streamLogKey, "("+strconv.FormatInt(afterID, 10), "+inf", int64(limit))
+	if err != nil {
+		r.log.Error("Ошибка чтения журнала событий", "error", err.Error())
+		return nil, false, errors.NewInternalError("Ошибка получения событий", err)
+	}
+
+	events := make([]*entity.StreamEvent, 0, len(members))
+	for _, member := range members {
+		var event entity.StreamEvent
+		if err = json.Unmarshal([]byte(member), &event); err != nil {
+			r.log.Warn("Некорректное событие в журнале", "error", err.Error())
+			continue
+		}
+		events = append(events, &event)
+	}
+
+	return events, truncated, nil
+}
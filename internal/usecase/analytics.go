@@ -1 +1,154 @@
 package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// analyticsMaxLimit ограничивает размер рейтингов просмотров
+const analyticsMaxLimit = 100
+
+// AnalyticsUseCase интерфейс, определяющий бизнес-логику статистики просмотров
+type AnalyticsUseCase interface {
+	GetTopManga(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.MangaStat, error)
+	GetTopChapters(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.ChapterStat, error)
+	GetStats(ctx context.Context) ([]*entity.AnalyticsTotals, error)
+	ResetStats(ctx context.Context, period entity.StatsPeriod) error
+}
+
+// analyticsUseCase реализация интерфейса AnalyticsUseCase
+type analyticsUseCase struct {
+	analyticsRepo repository.AnalyticsRepository
+	mangaRepo     repository.MangaRepository
+	chapterRepo   repository.ChapterRepository
+	log           logger.Logger
+}
+
+// NewAnalyticsUseCase создает новый экземпляр AnalyticsUseCase
+func NewAnalyticsUseCase(
+	analyticsRepo repository.AnalyticsRepository,
+	mangaRepo repository.MangaRepository,
+	chapterRepo repository.ChapterRepository,
+	log logger.Logger,
+) AnalyticsUseCase {
+	return &analyticsUseCase{
+		analyticsRepo: analyticsRepo,
+		mangaRepo:     mangaRepo,
+		chapterRepo:   chapterRepo,
+		log:           log,
+	}
+}
+
+// GetTopManga возвращает самую просматриваемую мангу за период с названиями
+func (uc *analyticsUseCase) GetTopManga(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.MangaStat, error) {
+	if err := validateStatsLimit(limit); err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.analyticsRepo.GetTopManga(ctx, period, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return fillMangaStats(ctx, uc.mangaRepo, uc.log, stats)
+}
+
+// GetTopChapters возвращает самые просматриваемые главы за период с номерами и названиями
+func (uc *analyticsUseCase) GetTopChapters(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.ChapterStat, error) {
+	if err := validateStatsLimit(limit); err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.analyticsRepo.GetTopChapters(ctx, period, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Главы, удаленные после учета просмотров, пропускаются
+	filled := make([]*entity.ChapterStat, 0, len(stats))
+	for _, stat := range stats {
+		chapter, err := uc.chapterRepo.GetByID(ctx, stat.ChapterID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		stat.MangaID = chapter.MangaID
+		stat.Number = chapter.Number
+		stat.Title = chapter.Title
+		filled = append(filled, stat)
+	}
+
+	return filled, nil
+}
+
+// GetStats возвращает суммарное число просмотров по всем периодам
+func (uc *analyticsUseCase) GetStats(ctx context.Context) ([]*entity.AnalyticsTotals, error) {
+	stats := make([]*entity.AnalyticsTotals, 0, len(entity.StatsPeriods))
+	for _, period := range entity.StatsPeriods {
+		totals, err := uc.analyticsRepo.GetTotals(ctx, period)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, totals)
+	}
+
+	return stats, nil
+}
+
+// ResetStats сбрасывает статистику за период. Статистика за все время не сбрасывается
+func (uc *analyticsUseCase) ResetStats(ctx context.Context, period entity.StatsPeriod) error {
+	switch period {
+	case entity.StatsPeriodDaily, entity.StatsPeriodWeekly, entity.StatsPeriodMonthly:
+	default:
+		return errors.NewValidationError("Некорректный период статистики", map[string]interface{}{
+			"period":  period,
+			"allowed": []entity.StatsPeriod{entity.StatsPeriodDaily, entity.StatsPeriodWeekly, entity.StatsPeriodMonthly},
+		})
+	}
+
+	if err := uc.analyticsRepo.ResetStats(ctx, period); err != nil {
+		return err
+	}
+
+	uc.log.Info("Статистика сброшена", "event", "analytics_reset", "period", period)
+	return nil
+}
+
+// validateStatsLimit проверяет размер запрашиваемого рейтинга
+func validateStatsLimit(limit int) error {
+	if limit < 1 || limit > analyticsMaxLimit {
+		return errors.NewValidationError("Некорректный лимит", map[string]interface{}{
+			"limit": limit,
+			"min":   1,
+			"max":   analyticsMaxLimit,
+		})
+	}
+	return nil
+}
+
+// fillMangaStats заполняет названия манги в статистике просмотров.
+// Манга, удаленная после учета просмотров, пропускается
+func fillMangaStats(ctx context.Context, mangaRepo repository.MangaRepository, log logger.Logger, stats []*entity.MangaStat) ([]*entity.MangaStat, error) {
+	filled := make([]*entity.MangaStat, 0, len(stats))
+	for _, stat := range stats {
+		manga, err := mangaRepo.GetByID(ctx, stat.MangaID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				log.Debug("Пропуск удаленной манги в статистике", "manga_id", stat.MangaID)
+				continue
+			}
+			return nil, err
+		}
+
+		stat.Title = manga.Title
+		filled = append(filled, stat)
+	}
+
+	return filled, nil
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"manga-reader2/internal/infrastructure/auth"
+	"strings"
+	"time"
+)
+
+// Параметры API ключей
+const (
+	apiKeyPrefix           = "mr"
+	apiKeyMaxPerUser       = 20
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// APIKeyUseCase интерфейс, определяющий бизнес-логику для работы с персональными API ключами
+type APIKeyUseCase interface {
+	Create(ctx context.Context, userID int64, req *entity.APIKeyCreate) (*entity.APIKeyWithSecret, error)
+	List(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+	Revoke(ctx context.Context, userID, keyID int64) error
+	Authenticate(ctx context.Context, rawKey string) (*entity.User, *entity.APIKey, error)
+}
+
+// apiKeyUseCase реализация интерфейса APIKeyUseCase
+type apiKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	cacheRepo  repository.CacheRepository
+	log        logger.Logger
+}
+
+// NewAPIKeyUseCase создает новый экземпляр APIKeyUseCase
+func NewAPIKeyUseCase(
+	apiKeyRepo repository.APIKeyRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		cacheRepo:  cacheRepo,
+		log:        log,
+	}
+}
+
+// Create создает новый API ключ; значение ключа возвращается только в этом ответе
+func (uc *apiKeyUseCase) Create(ctx context.Context, userID int64, req *entity.APIKeyCreate) (*entity.APIKeyWithSecret, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.NewValidationError("Название ключа не может быть пустым", nil)
+	}
+	if len(req.Name) > 100 {
+		return nil, errors.NewValidationError("Название ключа не может быть длиннее 100 символов", nil)
+	}
+
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.NewValidationError("Срок действия ключа должен быть в будущем", nil)
+	}
+
+	existing, err := uc.apiKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	active := 0
+	for _, key := range existing {
+		if key.RevokedAt == nil {
+			active++
+		}
+	}
+	if active >= apiKeyMaxPerUser {
+		return nil, errors.NewValidationError(fmt.Sprintf("Нельзя создать больше %d активных ключей", apiKeyMaxPerUser), nil)
+	}
+
+	prefix, err := auth.GenerateRandomString(6)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка генерации API ключа", err)
+	}
+	secret, err := auth.GenerateRandomString(32)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка генерации API ключа", err)
+	}
+
+	prefix = fmt.Sprintf("%s_%s", apiKeyPrefix, strings.NewReplacer("-", "", "_", "").Replace(prefix))
+	rawKey := fmt.Sprintf("%s_%s", prefix, secret)
+
+	key := &entity.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(rawKey),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if _, err = uc.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	uc.log.Info("Создан API ключ", "event", "api_key_created", "user_id", userID, "key_id", key.ID, "scopes", strings.Join(scopes, ","))
+
+	return &entity.APIKeyWithSecret{
+		APIKey: *key,
+		Key:    rawKey,
+	}, nil
+}
+
+// List возвращает список API ключей пользователя
+func (uc *apiKeyUseCase) List(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	return uc.apiKeyRepo.ListByUser(ctx, userID)
+}
+
+// Revoke отзывает API ключ пользователя
+func (uc *apiKeyUseCase) Revoke(ctx context.Context, userID, keyID int64) error {
+	if err := uc.apiKeyRepo.Revoke(ctx, keyID, userID); err != nil {
+		return err
+	}
+
+	uc.log.Info("API ключ отозван", "event", "api_key_revoked", "user_id", userID, "key_id", keyID)
+
+	return nil
+}
+
+// Authenticate проверяет API ключ и возвращает его владельца
+func (uc *apiKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*entity.User, *entity.APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix+"_") {
+		return nil, nil, errors.NewUnauthorizedError("Недействительный API ключ", nil)
+	}
+
+	key, err := uc.apiKeyRepo.GetByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, nil, errors.NewUnauthorizedError("Недействительный API ключ", nil)
+		}
+		return nil, nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return nil, nil, errors.NewUnauthorizedError("API ключ отозван", nil)
+	}
+
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		return nil, nil, errors.NewUnauthorizedError("Срок действия API ключа истек", nil)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return nil, nil, errors.NewUnauthorizedError("Недействительный API ключ", nil)
+		}
+		return nil, nil, err
+	}
+
+	// Время использования обновляем не чаще раза в минуту, чтобы не писать в БД на каждый запрос
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > apiKeyLastUsedInterval {
+		if err = uc.apiKeyRepo.UpdateLastUsed(ctx, key.ID); err != nil {
+			uc.log.Error("Ошибка обновления времени использования API ключа", "error", err.Error(), "key_id", key.ID)
+		}
+	}
+
+	if user.Permissions, err = resolvePermissions(ctx, uc.roleRepo, uc.cacheRepo, uc.log, user.Role); err != nil {
+		return nil, nil, err
+	}
+
+	user.Password = ""
+
+	return user, key, nil
+}
+
+// normalizeAPIKeyScopes проверяет области действия ключа и удаляет дубликаты
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, errors.NewValidationError("Необходимо указать хотя бы одну область действия ключа", nil)
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		valid := false
+		for _, allowed := range entity.APIKeyScopes {
+			if scope == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, errors.NewValidationError("Недопустимая область действия ключа", map[string]interface{}{
+				"scope":   scope,
+				"allowed": entity.APIKeyScopes,
+			})
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result, nil
+}
+
+// hashAPIKey возвращает SHA-256 хеш ключа в hex
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// BookmarkUseCase интерфейс, определяющий бизнес-логику для работы с закладками
+type BookmarkUseCase interface {
+	List(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]*entity.BookmarkWithManga, int, error)
+	Add(ctx context.Context, userID int64, req *entity.BookmarkCreate) (*entity.Bookmark, error)
+	Remove(ctx context.Context, userID, mangaID int64) error
+}
+
+// bookmarkUseCase реализация интерфейса BookmarkUseCase
+type bookmarkUseCase struct {
+	bookmarkRepo repository.BookmarkRepository
+	mangaRepo    repository.MangaRepository
+	chapterRepo  repository.ChapterRepository
+	log          logger.Logger
+}
+
+// NewBookmarkUseCase создает новый экземпляр BookmarkUseCase
+func NewBookmarkUseCase(
+	bookmarkRepo repository.BookmarkRepository,
+	mangaRepo repository.MangaRepository,
+	chapterRepo repository.ChapterRepository,
+	log logger.Logger,
+) BookmarkUseCase {
+	return &bookmarkUseCase{
+		bookmarkRepo: bookmarkRepo,
+		mangaRepo:    mangaRepo,
+		chapterRepo:  chapterRepo,
+		log:          log,
+	}
+}
+
+// List возвращает закладки пользователя с данными манги
+func (uc *bookmarkUseCase) List(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]*entity.BookmarkWithManga, int, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = entity.BookmarkSortAdded
+	case entity.BookmarkSortAdded, entity.BookmarkSortUpdated:
+	default:
+		return nil, 0, errors.NewValidationError("Некорректная сортировка", map[string]interface{}{
+			"sort":    filter.Sort,
+			"allowed": []string{entity.BookmarkSortAdded, entity.BookmarkSortUpdated},
+		})
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.bookmarkRepo.ListByUser(ctx, userID, filter)
+}
+
+// Add добавляет мангу в закладки или обновляет последнюю прочитанную главу
+func (uc *bookmarkUseCase) Add(ctx context.Context, userID int64, req *entity.BookmarkCreate) (*entity.Bookmark, error) {
+	if req.MangaID <= 0 {
+		return nil, errors.NewValidationError("Не указан ID манги", nil)
+	}
+
+	if _, err := uc.mangaRepo.GetByID(ctx, req.MangaID); err != nil {
+		return nil, err
+	}
+
+	if req.ChapterID != nil {
+		chapter, err := uc.chapterRepo.GetByID(ctx, *req.ChapterID)
+		if err != nil {
+			return nil, err
+		}
+		if chapter.MangaID != req.MangaID {
+			return nil, errors.NewValidationError("Глава не принадлежит указанной манге", nil)
+		}
+	}
+
+	bookmark := &entity.Bookmark{
+		UserID:    userID,
+		MangaID:   req.MangaID,
+		ChapterID: req.ChapterID,
+	}
+
+	if err := uc.bookmarkRepo.Upsert(ctx, bookmark); err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+// Remove удаляет мангу из закладок
+func (uc *bookmarkUseCase) Remove(ctx context.Context, userID, mangaID int64) error {
+	return uc.bookmarkRepo.Delete(ctx, userID, mangaID)
+}
@@ -8,6 +8,7 @@ import (
 	"manga-reader2/internal/common/logger"
 	"manga-reader2/internal/domain/entity"
 	"manga-reader2/internal/domain/repository"
+	"strings"
 	"time"
 )
 
@@ -19,31 +20,41 @@ type ChapterUseCase interface {
 	Update(ctx context.Context, chapter *entity.Chapter) (*entity.Chapter, error)
 	Delete(ctx context.Context, id int64) error
 	GetPages(ctx context.Context, chapterID int64) ([]*entity.Page, error)
+	Run(ctx context.Context)
 }
 
 // chapterUseCase реализация интерфейса ChapterUseCase
 type chapterUseCase struct {
-	chapterRepo   repository.ChapterRepository
-	mangaRepo     repository.MangaRepository
-	cacheRepo     repository.CacheRepository
-	analyticsRepo repository.AnalyticsRepository
-	log           logger.Logger
+	chapterRepo         repository.ChapterRepository
+	mangaRepo           repository.MangaRepository
+	pageRepo            repository.PageRepository
+	cacheRepo           repository.CacheRepository
+	analyticsRepo       repository.AnalyticsRepository
+	historyUseCase      ReadingHistoryUseCase
+	notificationUseCase NotificationUseCase
+	log                 logger.Logger
 }
 
 // NewChapterUseCase создает новый экземпляр ChapterUseCase
 func NewChapterUseCase(
 	chapterRepo repository.ChapterRepository,
 	mangaRepo repository.MangaRepository,
+	pageRepo repository.PageRepository,
 	cacheRepo repository.CacheRepository,
 	analyticsRepo repository.AnalyticsRepository,
+	historyUseCase ReadingHistoryUseCase,
+	notificationUseCase NotificationUseCase,
 	log logger.Logger,
 ) ChapterUseCase {
 	return &chapterUseCase{
-		chapterRepo:   chapterRepo,
-		mangaRepo:     mangaRepo,
-		cacheRepo:     cacheRepo,
-		analyticsRepo: analyticsRepo,
-		log:           log,
+		chapterRepo:         chapterRepo,
+		mangaRepo:           mangaRepo,
+		pageRepo:            pageRepo,
+		cacheRepo:           cacheRepo,
+		analyticsRepo:       analyticsRepo,
+		historyUseCase:      historyUseCase,
+		notificationUseCase: notificationUseCase,
+		log:                 log,
 	}
 }
 
@@ -57,11 +68,25 @@ func (uc *chapterUseCase) Create(ctx context.Context, chapter *entity.Chapter) (
 		return nil, errors.NewValidationError("Не указан ID манги", nil)
 	}
 
+	if chapter.Language == "" {
+		chapter.Language = entity.DefaultChapterLanguage
+	}
+	if err := normalizeChapterRelease(chapter); err != nil {
+		return nil, err
+	}
+	if err := normalizeChapterSchedule(chapter, time.Now()); err != nil {
+		return nil, err
+	}
+
 	_, err := uc.mangaRepo.GetByID(ctx, chapter.MangaID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID); err != nil {
+		return nil, err
+	}
+
 	id, err := uc.chapterRepo.Create(ctx, chapter)
 	if err != nil {
 		return nil, err
@@ -76,36 +101,22 @@ func (uc *chapterUseCase) Create(ctx context.Context, chapter *entity.Chapter) (
 		uc.log.Error("Ошибка инвалидации кеша списка глав", "error", err.Error(), "manga_id", chapter.MangaID)
 	}
 
+	// Черновики и запланированные главы рассылаются при публикации
+	if createdChapter.IsPublished() {
+		uc.onPublished(ctx, createdChapter)
+	}
+
 	return createdChapter, nil
 }
 
-// GetByID получает главу по ID с статистикой просмотров
+// GetByID получает главу по ID со статистикой страниц и просмотров
 func (uc *chapterUseCase) GetByID(ctx context.Context, id int64) (*entity.ChapterWithStats, error) {
-	cacheKey := fmt.Sprintf("chapter:%d", id)
-	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
-	if err == nil && cachedData != "" {
-		var chapter entity.Chapter
-		if err := json.Unmarshal([]byte(cachedData), &chapter); err == nil {
-			views, err := uc.analyticsRepo.GetChapterViews(ctx, id)
-			if err != nil {
-				uc.log.Error("Ошибка получения просмотров главы", "error", err.Error(), "chapter_id", id)
-				views = 0
-			}
-
-			if err := uc.analyticsRepo.RecordChapterView(ctx, id, chapter.MangaID); err != nil {
-				uc.log.Error("Ошибка записи просмотра главы", "error", err.Error(), "chapter_id", id)
-			}
-
-			return &entity.ChapterWithStats{
-				Chapter: chapter,
-				Views:   views,
-			}, nil
-		}
-		uc.log.Error("Ошибка декодирования главы из кеша", "error", err.Error())
+	chapter, err := uc.cachedChapter(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	chapter, err := uc.chapterRepo.GetByID(ctx, id)
-	if err != nil {
+	if err = ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
 		return nil, err
 	}
 
@@ -115,51 +126,64 @@ func (uc *chapterUseCase) GetByID(ctx context.Context, id int64) (*entity.Chapte
 		views = 0
 	}
 
+	pageCounts, err := uc.pageRepo.CountByChapters(ctx, []int64{id})
+	if err != nil {
+		uc.log.Error("Ошибка подсчета страниц главы", "error", err.Error(), "chapter_id", id)
+	}
+
 	if err := uc.analyticsRepo.RecordChapterView(ctx, id, chapter.MangaID); err != nil {
 		uc.log.Error("Ошибка записи просмотра главы", "error", err.Error(), "chapter_id", id)
 	}
 
-	if jsonData, err := json.Marshal(chapter); err == nil {
-		if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), 30*time.Minute); err != nil {
-			uc.log.Error("Ошибка кеширования главы", "error", err.Error())
-		}
-	}
+	uc.recordRead(ctx, chapter)
 
 	return &entity.ChapterWithStats{
-		Chapter: *chapter,
-		Views:   views,
+		Chapter:   *chapter,
+		PageCount: pageCounts[id],
+		Views:     views,
 	}, nil
 }
 
-// ListByManga возвращает список глав для манги
-func (uc *chapterUseCase) ListByManga(ctx context.Context, mangaID int64) ([]*entity.Chapter, error) {
-	_, err := uc.mangaRepo.GetByID(ctx, mangaID)
+// cachedChapter возвращает главу из кеша или базы. Кешируется и неопубликованная глава:
+// доступ проверяется при каждом чтении
+func (uc *chapterUseCase) cachedChapter(ctx context.Context, id int64) (*entity.Chapter, error) {
+	cacheKey := fmt.Sprintf("chapter:%d", id)
+	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var chapter entity.Chapter
+		if err := json.Unmarshal([]byte(cachedData), &chapter); err == nil {
+			return &chapter, nil
+		}
+		uc.log.Error("Ошибка декодирования главы из кеша", "error", err.Error())
+	}
+
+	chapter, err := uc.chapterRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	cacheKey := fmt.Sprintf("manga:%d:chapters", mangaID)
-	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
-	if err == nil && cachedData != "" {
-		var chapters []*entity.Chapter
-		if err := json.Unmarshal([]byte(cachedData), &chapters); err == nil {
-			return chapters, nil
+	if jsonData, err := json.Marshal(chapter); err == nil {
+		if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), 30*time.Minute); err != nil {
+			uc.log.Error("Ошибка кеширования главы", "error", err.Error())
 		}
-		uc.log.Error("Ошибка декодирования списка глав из кеша", "error", err.Error())
 	}
 
-	chapters, err := uc.chapterRepo.ListByManga(ctx, mangaID)
+	return chapter, nil
+}
+
+// ListByManga возвращает список глав для манги. Неопубликованные главы видят только загрузчики манги
+func (uc *chapterUseCase) ListByManga(ctx context.Context, mangaID int64) ([]*entity.Chapter, error) {
+	_, err := uc.mangaRepo.GetByID(ctx, mangaID)
 	if err != nil {
 		return nil, err
 	}
 
-	if jsonData, err := json.Marshal(chapters); err == nil {
-		if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), 15*time.Minute); err != nil {
-			uc.log.Error("Ошибка кеширования списка глав", "error", err.Error())
-		}
+	chapters, err := cachedChapterList(ctx, uc.chapterRepo, uc.cacheRepo, uc.log, mangaID)
+	if err != nil {
+		return nil, err
 	}
 
-	return chapters, nil
+	return visibleChapters(ctx, uc.mangaRepo, mangaID, chapters), nil
 }
 
 // Update обновляет главу
@@ -169,10 +193,42 @@ func (uc *chapterUseCase) Update(ctx context.Context, chapter *entity.Chapter) (
 		return nil, err
 	}
 
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, existingChapter.MangaID); err != nil {
+		return nil, err
+	}
+
 	if chapter.Title == "" {
 		return nil, errors.NewValidationError("Название главы не может быть пустым", nil)
 	}
 
+	// Не переданные язык, группа и том остаются прежними; group_id = 0 снимает группу, volume = 0 — том
+	if chapter.Language == "" {
+		chapter.Language = existingChapter.Language
+	}
+	if chapter.GroupID == nil {
+		chapter.GroupID = existingChapter.GroupID
+	}
+	if chapter.Volume == nil {
+		chapter.Volume = existingChapter.Volume
+	}
+	if err := normalizeChapterRelease(chapter); err != nil {
+		return nil, err
+	}
+
+	// Без статуса сохраняется прежнее расписание; ранний доступ завершается установкой прошедшего времени
+	if chapter.Status == "" {
+		chapter.Status = existingChapter.Status
+		if chapter.PublishAt == nil {
+			chapter.PublishAt = existingChapter.PublishAt
+		}
+	}
+	if chapter.EarlyAccessUntil == nil {
+		chapter.EarlyAccessUntil = existingChapter.EarlyAccessUntil
+	}
+	if err := normalizeChapterSchedule(chapter, time.Now()); err != nil {
+		return nil, err
+	}
+
 	if err := uc.chapterRepo.Update(ctx, chapter); err != nil {
 		return nil, err
 	}
@@ -190,6 +246,11 @@ func (uc *chapterUseCase) Update(ctx context.Context, chapter *entity.Chapter) (
 	if err := uc.invalidateChapterListCache(ctx, existingChapter.MangaID); err != nil {
 		uc.log.Error("Ошибка инвалидации кеша списка глав", "error", err.Error(), "manga_id", existingChapter.MangaID)
 	}
+	invalidateMangaReaderCache(ctx, uc.chapterRepo, uc.cacheRepo, uc.log, existingChapter.MangaID)
+
+	if !existingChapter.IsPublished() && updatedChapter.IsPublished() {
+		uc.onPublished(ctx, updatedChapter)
+	}
 
 	return updatedChapter, nil
 }
@@ -201,6 +262,10 @@ func (uc *chapterUseCase) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID); err != nil {
+		return err
+	}
+
 	if err := uc.chapterRepo.Delete(ctx, id); err != nil {
 		return err
 	}
@@ -213,15 +278,129 @@ func (uc *chapterUseCase) Delete(ctx context.Context, id int64) error {
 	if err := uc.invalidateChapterListCache(ctx, chapter.MangaID); err != nil {
 		uc.log.Error("Ошибка инвалидации кеша списка глав", "error", err.Error(), "manga_id", chapter.MangaID)
 	}
+	invalidateReaderCache(ctx, uc.cacheRepo, uc.log, id)
+	invalidateMangaReaderCache(ctx, uc.chapterRepo, uc.cacheRepo, uc.log, chapter.MangaID)
 
 	return nil
 }
 
-// GetPages возвращает список страниц для главы
+// GetPages возвращает страницы главы по порядку номеров. Страницы неопубликованной главы видят только ее загрузчики
 func (uc *chapterUseCase) GetPages(ctx context.Context, chapterID int64) ([]*entity.Page, error) {
-	// Этот метод будет реализован позже, когда мы добавим PageRepository
-	// Сейчас это заглушка
-	return []*entity.Page{}, nil
+	chapter, err := uc.chapterRepo.GetByID(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
+		return nil, err
+	}
+
+	return cachedPageList(ctx, uc.pageRepo, uc.cacheRepo, uc.log, chapterID)
+}
+
+// normalizeChapterRelease проверяет язык перевода и том главы, убирает пустые группу и том
+func normalizeChapterRelease(chapter *entity.Chapter) error {
+	if chapter.Volume != nil {
+		if *chapter.Volume < 0 {
+			return errors.NewValidationError("Номер тома не может быть отрицательным", map[string]interface{}{
+				"volume": *chapter.Volume,
+			})
+		}
+		if *chapter.Volume == 0 {
+			chapter.Volume = nil
+		}
+	}
+
+	language := NormalizeLanguage(chapter.Language)
+	if language == "" {
+		return errors.NewValidationError("Некорректный код языка", map[string]interface{}{
+			"language": chapter.Language,
+		})
+	}
+	chapter.Language = language
+
+	if chapter.GroupID != nil && *chapter.GroupID <= 0 {
+		chapter.GroupID = nil
+	}
+
+	return nil
+}
+
+// filterChaptersByLanguage оставляет главы на указанных языках. Язык с регионом
+// подходит и для основного языка: фильтр en включает главы en-us
+func filterChaptersByLanguage(chapters []*entity.Chapter, languages []string) []*entity.Chapter {
+	if len(languages) == 0 {
+		return chapters
+	}
+
+	allowed := make(map[string]bool, len(languages))
+	for _, language := range languages {
+		allowed[language] = true
+	}
+
+	filtered := make([]*entity.Chapter, 0, len(chapters))
+	for _, chapter := range chapters {
+		base, _, _ := strings.Cut(chapter.Language, "-")
+		if allowed[chapter.Language] || allowed[base] {
+			filtered = append(filtered, chapter)
+		}
+	}
+	return filtered
+}
+
+// cachedChapterList возвращает все главы манги, включая неопубликованные, из кеша или базы.
+// Порядок задает репозиторий: по тому, затем по номеру главы
+func cachedChapterList(ctx context.Context, chapterRepo repository.ChapterRepository, cacheRepo repository.CacheRepository, log logger.Logger, mangaID int64) ([]*entity.Chapter, error) {
+	cacheKey := fmt.Sprintf("manga:%d:chapters", mangaID)
+	cachedData, err := cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var chapters []*entity.Chapter
+		if err := json.Unmarshal([]byte(cachedData), &chapters); err == nil {
+			return chapters, nil
+		}
+		log.Error("Ошибка декодирования списка глав из кеша", "error", err.Error())
+	}
+
+	chapters, err := chapterRepo.ListByManga(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if jsonData, err := json.Marshal(chapters); err == nil {
+		if err := cacheRepo.Set(ctx, cacheKey, string(jsonData), 15*time.Minute); err != nil {
+			log.Error("Ошибка кеширования списка глав", "error", err.Error())
+		}
+	}
+
+	return chapters, nil
+}
+
+// chaptersWithStats дополняет главы числом страниц и просмотров. Статистика не кешируется вместе
+// со списком глав и запрашивается для всех глав сразу; при ошибке соответствующие значения остаются нулевыми
+func chaptersWithStats(ctx context.Context, pageRepo repository.PageRepository, analyticsRepo repository.AnalyticsRepository, log logger.Logger, chapters []*entity.Chapter) []*entity.ChapterWithStats {
+	ids := make([]int64, len(chapters))
+	for i, chapter := range chapters {
+		ids[i] = chapter.ID
+	}
+
+	pageCounts, err := pageRepo.CountByChapters(ctx, ids)
+	if err != nil {
+		log.Error("Ошибка подсчета страниц глав", "error", err.Error())
+	}
+	views, err := analyticsRepo.GetChapterViewsBatch(ctx, ids)
+	if err != nil {
+		log.Error("Ошибка получения просмотров глав", "error", err.Error())
+	}
+
+	result := make([]*entity.ChapterWithStats, len(chapters))
+	for i, chapter := range chapters {
+		result[i] = &entity.ChapterWithStats{
+			Chapter:   *chapter,
+			PageCount: pageCounts[chapter.ID],
+			Views:     views[chapter.ID],
+		}
+	}
+	return result
 }
 
 // invalidateChapterListCache инвалидирует кеш списка глав для манги
@@ -229,3 +408,17 @@ func (uc *chapterUseCase) invalidateChapterListCache(ctx context.Context, mangaI
 	cacheKey := fmt.Sprintf("manga:%d:chapters", mangaID)
 	return uc.cacheRepo.Delete(ctx, cacheKey)
 }
+
+// recordRead записывает открытие главы в историю чтения аутентифицированного пользователя
+func (uc *chapterUseCase) recordRead(ctx context.Context, chapter *entity.Chapter) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	uc.historyUseCase.RecordRead(ctx, &entity.ReadingHistoryEntry{
+		UserID:    actor.UserID,
+		MangaID:   chapter.MangaID,
+		ChapterID: chapter.ID,
+	})
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// chapterPublishInterval период проверки запланированных глав
+const chapterPublishInterval = time.Minute
+
+// canManageChapters проверяет, может ли пользователь из контекста управлять главами манги
+// и видеть ее неопубликованные главы
+func canManageChapters(ctx context.Context, mangaRepo repository.MangaRepository, mangaID int64) bool {
+	actor, ok := ActorFromContext(ctx)
+	if !ok || (!actor.HasPermission(entity.PermissionChapterUpload) && !actor.HasPermission(entity.PermissionMangaWrite)) {
+		return false
+	}
+	return ensureMangaAccess(ctx, mangaRepo, mangaID) == nil
+}
+
+// ensureChapterReadable проверяет, может ли пользователь из контекста читать главу.
+// Неопубликованная глава для читателей не существует, глава в раннем доступе доступна
+// только пользователям с разрешением раннего доступа и загрузчикам манги
+func ensureChapterReadable(ctx context.Context, mangaRepo repository.MangaRepository, chapter *entity.Chapter) error {
+	if !chapter.IsPublished() {
+		if canManageChapters(ctx, mangaRepo, chapter.MangaID) {
+			return nil
+		}
+		return errors.NewChapterNotFoundError(chapter.ID)
+	}
+
+	if !chapter.InEarlyAccess(time.Now()) {
+		return nil
+	}
+	if actor, ok := ActorFromContext(ctx); ok && actor.HasPermission(entity.PermissionChapterEarlyAccess) {
+		return nil
+	}
+	if canManageChapters(ctx, mangaRepo, chapter.MangaID) {
+		return nil
+	}
+
+	return errors.NewForbiddenError(
+		fmt.Sprintf("Глава в раннем доступе до %s", chapter.EarlyAccessUntil.Format(time.RFC3339)),
+		nil,
+	)
+}
+
+// visibleChapters убирает неопубликованные главы, если пользователь не может управлять главами манги.
+// Главы в раннем доступе остаются в списке, чтобы клиент мог показать их закрытыми
+func visibleChapters(ctx context.Context, mangaRepo repository.MangaRepository, mangaID int64, chapters []*entity.Chapter) []*entity.Chapter {
+	visible := make([]*entity.Chapter, 0, len(chapters))
+	checked, canManage := false, false
+	for _, chapter := range chapters {
+		if !chapter.IsPublished() {
+			if !checked {
+				canManage, checked = canManageChapters(ctx, mangaRepo, mangaID), true
+			}
+			if !canManage {
+				continue
+			}
+		}
+		visible = append(visible, chapter)
+	}
+	return visible
+}
+
+// normalizeChapterSchedule проверяет статус публикации главы. Без статуса глава с будущим
+// publish_at планируется, остальные публикуются сразу; наступившее время публикации публикует главу.
+// Время приводится к UTC, чтобы смещение из запроса клиента не влияло на сравнение с NOW() в БД
+func normalizeChapterSchedule(chapter *entity.Chapter, now time.Time) error {
+	if chapter.PublishAt != nil {
+		publishAt := chapter.PublishAt.UTC()
+		chapter.PublishAt = &publishAt
+	}
+	if chapter.EarlyAccessUntil != nil {
+		earlyAccessUntil := chapter.EarlyAccessUntil.UTC()
+		chapter.EarlyAccessUntil = &earlyAccessUntil
+	}
+
+	if chapter.Status == "" {
+		chapter.Status = entity.ChapterStatusPublished
+		if chapter.PublishAt != nil && chapter.PublishAt.After(now) {
+			chapter.Status = entity.ChapterStatusScheduled
+		}
+	}
+
+	switch chapter.Status {
+	case entity.ChapterStatusDraft:
+	case entity.ChapterStatusScheduled:
+		if chapter.PublishAt == nil {
+			return errors.NewValidationError("Для запланированной главы нужно время публикации", map[string]interface{}{
+				"status": chapter.Status,
+			})
+		}
+		if !chapter.PublishAt.After(now) {
+			chapter.Status = entity.ChapterStatusPublished
+			chapter.PublishAt = nil
+		}
+	case entity.ChapterStatusPublished:
+		chapter.PublishAt = nil
+	default:
+		return errors.NewValidationError("Некорректный статус главы", map[string]interface{}{
+			"status":  chapter.Status,
+			"allowed": entity.ChapterStatuses,
+		})
+	}
+
+	return nil
+}
+
+// Run периодически публикует запланированные главы до отмены контекста
+func (uc *chapterUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(chapterPublishInterval)
+	defer ticker.Stop()
+
+	for {
+		uc.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue публикует главы, время которых наступило, сбрасывает кеш и уведомляет подписчиков
+func (uc *chapterUseCase) publishDue(ctx context.Context) {
+	chapters, err := uc.chapterRepo.PublishDue(ctx)
+	if err != nil {
+		uc.log.Error("Ошибка публикации запланированных глав", "error", err.Error())
+		return
+	}
+
+	for _, chapter := range chapters {
+		uc.log.Info("Глава опубликована по расписанию", "event", "chapter_published", "chapter_id", chapter.ID, "manga_id", chapter.MangaID)
+		uc.onPublished(ctx, chapter)
+	}
+}
+
+// onPublished сбрасывает кеш главы и списка глав и ставит рассылку подписчикам
+func (uc *chapterUseCase) onPublished(ctx context.Context, chapter *entity.Chapter) {
+	cacheKey := fmt.Sprintf("chapter:%d", chapter.ID)
+	if err := uc.cacheRepo.Delete(ctx, cacheKey); err != nil {
+		uc.log.Error("Ошибка инвалидации кеша главы", "error", err.Error())
+	}
+
+	if err := uc.invalidateChapterListCache(ctx, chapter.MangaID); err != nil {
+		uc.log.Error("Ошибка инвалидации кеша списка глав", "error", err.Error(), "manga_id", chapter.MangaID)
+	}
+	// Опубликованная глава становится соседней для других глав манги
+	invalidateMangaReaderCache(ctx, uc.chapterRepo, uc.cacheRepo, uc.log, chapter.MangaID)
+
+	// Рассылка подписчикам выполняется в фоне и не задерживает загрузчика
+	if err := uc.notificationUseCase.NotifyNewChapter(ctx, chapter); err != nil {
+		uc.log.Error("Ошибка постановки рассылки уведомлений", "error", err.Error(), "chapter_id", chapter.ID)
+	}
+}
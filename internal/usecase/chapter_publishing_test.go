@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+func TestNormalizeChapterSchedule(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name          string
+		status        string
+		publishAt     *time.Time
+		wantStatus    string
+		wantPublishAt *time.Time
+		wantErr       bool
+	}{
+		{
+			name:       "без статуса и времени глава публикуется",
+			wantStatus: entity.ChapterStatusPublished,
+		},
+		{
+			name:          "без статуса с будущим временем глава планируется",
+			publishAt:     &future,
+			wantStatus:    entity.ChapterStatusScheduled,
+			wantPublishAt: &future,
+		},
+		{
+			name:       "без статуса с прошедшим временем глава публикуется",
+			publishAt:  &past,
+			wantStatus: entity.ChapterStatusPublished,
+		},
+		{
+			name:    "запланированная глава без времени",
+			status:  entity.ChapterStatusScheduled,
+			wantErr: true,
+		},
+		{
+			name:       "запланированная глава с наступившим временем публикуется",
+			status:     entity.ChapterStatusScheduled,
+			publishAt:  &past,
+			wantStatus: entity.ChapterStatusPublished,
+		},
+		{
+			name:          "черновик сохраняет время публикации",
+			status:        entity.ChapterStatusDraft,
+			publishAt:     &future,
+			wantStatus:    entity.ChapterStatusDraft,
+			wantPublishAt: &future,
+		},
+		{
+			name:       "опубликованная глава не хранит время публикации",
+			status:     entity.ChapterStatusPublished,
+			publishAt:  &future,
+			wantStatus: entity.ChapterStatusPublished,
+		},
+		{
+			name:    "неизвестный статус",
+			status:  "archived",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chapter := &entity.Chapter{Status: tt.status, PublishAt: tt.publishAt}
+
+			err := normalizeChapterSchedule(chapter, now)
+			if tt.wantErr {
+				if !errors.IsValidationError(err) {
+					t.Fatalf("ожидалась ошибка валидации, получено %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			if chapter.Status != tt.wantStatus {
+				t.Errorf("статус %q, ожидался %q", chapter.Status, tt.wantStatus)
+			}
+			switch {
+			case tt.wantPublishAt == nil && chapter.PublishAt != nil:
+				t.Errorf("время публикации %v, ожидалось nil", chapter.PublishAt)
+			case tt.wantPublishAt != nil && (chapter.PublishAt == nil || !chapter.PublishAt.Equal(*tt.wantPublishAt)):
+				t.Errorf("время публикации %v, ожидалось %v", chapter.PublishAt, tt.wantPublishAt)
+			}
+		})
+	}
+
+	t.Run("время со смещением приводится к UTC", func(t *testing.T) {
+		publishAt := future.In(moscow)
+		earlyAccessUntil := future.Add(24 * time.Hour).In(moscow)
+		chapter := &entity.Chapter{PublishAt: &publishAt, EarlyAccessUntil: &earlyAccessUntil}
+
+		if err := normalizeChapterSchedule(chapter, now); err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+
+		if chapter.Status != entity.ChapterStatusScheduled {
+			t.Errorf("статус %q, ожидался %q", chapter.Status, entity.ChapterStatusScheduled)
+		}
+		if chapter.PublishAt.Location() != time.UTC || !chapter.PublishAt.Equal(future) {
+			t.Errorf("время публикации %v, ожидалось %v", chapter.PublishAt, future)
+		}
+		if chapter.EarlyAccessUntil.Location() != time.UTC || !chapter.EarlyAccessUntil.Equal(earlyAccessUntil) {
+			t.Errorf("конец раннего доступа %v, ожидалось %v в UTC", chapter.EarlyAccessUntil, earlyAccessUntil)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// unreadCountsMaxManga максимальное количество манги в одном запросе счетчиков непрочитанного
+const unreadCountsMaxManga = 100
+
+// ChapterReadUseCase интерфейс, определяющий бизнес-логику отметок о прочтении глав
+type ChapterReadUseCase interface {
+	MarkChapter(ctx context.Context, userID, chapterID int64, read bool) (*entity.ChapterReadResult, error)
+	MarkRange(ctx context.Context, userID, mangaID int64, rng entity.ChapterRange, read bool) (*entity.ChapterReadResult, error)
+	Annotate(ctx context.Context, userID, mangaID int64, chapters []*entity.ChapterWithStats) ([]*entity.ChapterWithReadState, *entity.ChapterReadSummary, error)
+	UnreadCounts(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]int, error)
+}
+
+// chapterReadUseCase реализация интерфейса ChapterReadUseCase
+type chapterReadUseCase struct {
+	chapterReadRepo repository.ChapterReadRepository
+	mangaRepo       repository.MangaRepository
+	chapterRepo     repository.ChapterRepository
+	log             logger.Logger
+}
+
+// NewChapterReadUseCase создает новый экземпляр ChapterReadUseCase
+func NewChapterReadUseCase(
+	chapterReadRepo repository.ChapterReadRepository,
+	mangaRepo repository.MangaRepository,
+	chapterRepo repository.ChapterRepository,
+	log logger.Logger,
+) ChapterReadUseCase {
+	return &chapterReadUseCase{
+		chapterReadRepo: chapterReadRepo,
+		mangaRepo:       mangaRepo,
+		chapterRepo:     chapterRepo,
+		log:             log,
+	}
+}
+
+// MarkChapter отмечает одну главу прочитанной или непрочитанной. Отметить можно только доступную главу
+func (uc *chapterReadUseCase) MarkChapter(ctx context.Context, userID, chapterID int64, read bool) (*entity.ChapterReadResult, error) {
+	chapter, err := uc.chapterRepo.GetByID(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
+		return nil, err
+	}
+
+	changed, err := uc.chapterReadRepo.SetRead(ctx, userID, chapter, read)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.ChapterReadResult{MangaID: chapter.MangaID}
+	if changed {
+		result.Changed = 1
+	}
+
+	return uc.withUnread(ctx, userID, result)
+}
+
+// MarkRange отмечает прочитанными или непрочитанными главы манги в диапазоне номеров
+func (uc *chapterReadUseCase) MarkRange(ctx context.Context, userID, mangaID int64, rng entity.ChapterRange, read bool) (*entity.ChapterReadResult, error) {
+	if (rng.From != nil && *rng.From < 0) || (rng.To != nil && *rng.To < 0) {
+		return nil, errors.NewValidationError("Номер главы не может быть отрицательным", nil)
+	}
+	if rng.From != nil && rng.To != nil && *rng.From > *rng.To {
+		return nil, errors.NewValidationError("Начало диапазона больше конца", nil)
+	}
+
+	if _, err := uc.mangaRepo.GetByID(ctx, mangaID); err != nil {
+		return nil, err
+	}
+
+	changed, err := uc.chapterReadRepo.SetRangeRead(ctx, userID, mangaID, rng, read)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.withUnread(ctx, userID, &entity.ChapterReadResult{MangaID: mangaID, Changed: changed})
+}
+
+// Annotate дополняет главы манги отметками о прочтении пользователем
+func (uc *chapterReadUseCase) Annotate(ctx context.Context, userID, mangaID int64, chapters []*entity.ChapterWithStats) ([]*entity.ChapterWithReadState, *entity.ChapterReadSummary, error) {
+	readIDs, err := uc.chapterReadRepo.ListReadChapterIDs(ctx, userID, mangaID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	read := make(map[int64]bool, len(readIDs))
+	for _, id := range readIDs {
+		read[id] = true
+	}
+
+	// Сводка считает номера глав: переводы одной главы — одна глава, прочитанная, если прочитан любой перевод
+	readNumbers := make(map[float64]bool, len(chapters))
+	result := make([]*entity.ChapterWithReadState, 0, len(chapters))
+	for _, chapter := range chapters {
+		state := &entity.ChapterWithReadState{ChapterWithStats: *chapter, Read: read[chapter.ID]}
+		readNumbers[chapter.Number] = readNumbers[chapter.Number] || state.Read
+		result = append(result, state)
+	}
+
+	summary := &entity.ChapterReadSummary{Total: len(readNumbers)}
+	for _, numberRead := range readNumbers {
+		if numberRead {
+			summary.Read++
+		}
+	}
+	summary.Unread = summary.Total - summary.Read
+
+	return result, summary, nil
+}
+
+// UnreadCounts возвращает количество непрочитанных глав по каждой из указанных манг
+func (uc *chapterReadUseCase) UnreadCounts(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]int, error) {
+	if len(mangaIDs) == 0 {
+		return nil, errors.NewValidationError("Не указаны ID манги", nil)
+	}
+	if len(mangaIDs) > unreadCountsMaxManga {
+		return nil, errors.NewValidationError("Слишком много манги в запросе", map[string]interface{}{
+			"max_manga": unreadCountsMaxManga,
+		})
+	}
+
+	counts, err := uc.chapterReadRepo.CountUnread(ctx, userID, mangaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Манга без глав или несуществующая манга не имеет непрочитанного
+	for _, id := range mangaIDs {
+		if _, ok := counts[id]; !ok {
+			counts[id] = 0
+		}
+	}
+
+	return counts, nil
+}
+
+// withUnread дополняет результат количеством непрочитанных глав манги
+func (uc *chapterReadUseCase) withUnread(ctx context.Context, userID int64, result *entity.ChapterReadResult) (*entity.ChapterReadResult, error) {
+	counts, err := uc.chapterReadRepo.CountUnread(ctx, userID, []int64{result.MangaID})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Unread = counts[result.MangaID]
+
+	return result, nil
+}
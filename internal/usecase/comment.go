@@ -0,0 +1,531 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/common/markdown"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// commentMaxLength ограничивает длину текста комментария в символах
+	commentMaxLength = 10000
+	// reportDetailsMaxLength ограничивает длину пояснения к жалобе в символах
+	reportDetailsMaxLength = 1000
+	// commentAutoHideReports количество открытых жалоб, после которого комментарий скрывается до решения модератора
+	commentAutoHideReports = 3
+	// commentRateLimit и commentRateWindow ограничивают частоту создания комментариев одним пользователем
+	commentRateLimit  = 10
+	commentRateWindow = time.Minute
+)
+
+// CommentUseCase интерфейс, определяющий бизнес-логику комментариев
+type CommentUseCase interface {
+	Create(ctx context.Context, userID int64, input *entity.CommentCreate) (*entity.CommentWithUser, error)
+	Reply(ctx context.Context, userID, parentID int64, input *entity.CommentCreate) (*entity.CommentWithUser, error)
+	GetByID(ctx context.Context, id int64) (*entity.CommentWithUser, error)
+	List(ctx context.Context, filter entity.CommentFilter) ([]*entity.CommentWithUser, int, error)
+	Update(ctx context.Context, userID, id int64, input *entity.CommentUpdate) (*entity.CommentWithUser, error)
+	Delete(ctx context.Context, userID, id int64) error
+	History(ctx context.Context, id int64) ([]*entity.CommentEdit, error)
+	Vote(ctx context.Context, userID, id int64, value int) (*entity.CommentVoteResult, error)
+	Report(ctx context.Context, userID, id int64, input *entity.CommentReportCreate) (*entity.CommentReport, error)
+}
+
+// commentUseCase реализация интерфейса CommentUseCase
+type commentUseCase struct {
+	commentRepo    repository.CommentRepository
+	moderationRepo repository.ModerationRepository
+	mangaRepo      repository.MangaRepository
+	chapterRepo    repository.ChapterRepository
+	cacheRepo      repository.CacheRepository
+	log            logger.Logger
+}
+
+// NewCommentUseCase создает новый экземпляр CommentUseCase
+func NewCommentUseCase(
+	commentRepo repository.CommentRepository,
+	moderationRepo repository.ModerationRepository,
+	mangaRepo repository.MangaRepository,
+	chapterRepo repository.ChapterRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) CommentUseCase {
+	return &commentUseCase{
+		commentRepo:    commentRepo,
+		moderationRepo: moderationRepo,
+		mangaRepo:      mangaRepo,
+		chapterRepo:    chapterRepo,
+		cacheRepo:      cacheRepo,
+		log:            log,
+	}
+}
+
+// Create создает комментарий верхнего уровня к манге или главе
+func (uc *commentUseCase) Create(ctx context.Context, userID int64, input *entity.CommentCreate) (*entity.CommentWithUser, error) {
+	switch {
+	case input.MangaID != nil && input.ChapterID == nil:
+		if _, err := uc.mangaRepo.GetByID(ctx, *input.MangaID); err != nil {
+			return nil, err
+		}
+	case input.ChapterID != nil && input.MangaID == nil:
+		if err := uc.ensureChapterCommentsReadable(ctx, input.ChapterID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.NewValidationError("Комментарий должен относиться либо к манге, либо к главе", nil)
+	}
+
+	input.ParentID = nil
+	return uc.create(ctx, userID, input)
+}
+
+// Reply создает ответ на комментарий; ответ относится к тому же объекту, что и родитель
+func (uc *commentUseCase) Reply(ctx context.Context, userID, parentID int64, input *entity.CommentCreate) (*entity.CommentWithUser, error) {
+	parent, err := uc.commentRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.DeletedAt != nil {
+		return nil, errors.NewValidationError("Нельзя ответить на удаленный комментарий", nil)
+	}
+	if parent.HiddenAt != nil {
+		return nil, errors.NewValidationError("Нельзя ответить на скрытый комментарий", nil)
+	}
+
+	if err = uc.ensureCommentVisible(ctx, parent); err != nil {
+		return nil, err
+	}
+
+	input.MangaID = parent.MangaID
+	input.ChapterID = parent.ChapterID
+	input.ParentID = &parent.ID
+
+	return uc.create(ctx, userID, input)
+}
+
+// ensureChapterCommentsReadable проверяет, что глава, к которой относятся комментарии, доступна пользователю:
+// обсуждение неопубликованной главы или главы в раннем доступе не должно ее раскрывать
+func (uc *commentUseCase) ensureChapterCommentsReadable(ctx context.Context, chapterID *int64) error {
+	if chapterID == nil {
+		return nil
+	}
+
+	chapter, err := uc.chapterRepo.GetByID(ctx, *chapterID)
+	if err != nil {
+		return err
+	}
+
+	return ensureChapterReadable(ctx, uc.mangaRepo, chapter)
+}
+
+// ensureCommentVisible проверяет, что комментарий относится к доступной пользователю главе.
+// Для читателей без доступа комментарий не существует: ответ не должен раскрывать скрытую главу
+func (uc *commentUseCase) ensureCommentVisible(ctx context.Context, comment *entity.CommentWithUser) error {
+	err := uc.ensureChapterCommentsReadable(ctx, comment.ChapterID)
+	if err != nil && (errors.IsForbiddenError(err) || errors.IsNotFoundError(err)) {
+		return errors.NewNotFoundError("Комментарий не найден", nil)
+	}
+	return err
+}
+
+// create проверяет текст и частоту публикаций, рендерит Markdown и сохраняет комментарий
+func (uc *commentUseCase) create(ctx context.Context, userID int64, input *entity.CommentCreate) (*entity.CommentWithUser, error) {
+	content, err := validateCommentContent(input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = uc.checkRateLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	comment := &entity.Comment{
+		UserID:      userID,
+		MangaID:     input.MangaID,
+		ChapterID:   input.ChapterID,
+		ParentID:    input.ParentID,
+		Content:     content,
+		ContentHTML: markdown.Render(content),
+		Spoiler:     input.Spoiler,
+	}
+	if err = uc.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	invalidateCommentTargetCache(ctx, uc.cacheRepo, uc.log, comment)
+
+	return uc.GetByID(ctx, comment.ID)
+}
+
+// GetByID возвращает комментарий
+func (uc *commentUseCase) GetByID(ctx context.Context, id int64) (*entity.CommentWithUser, error) {
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.ensureCommentVisible(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	viewerID, moderator := commentViewer(ctx)
+	prepareComment(comment, moderator)
+	uc.annotateVotes(ctx, viewerID, comment)
+
+	return comment, nil
+}
+
+// List возвращает комментарии объекта или ответы на комментарий.
+// Ответы по умолчанию идут от старых к новым, комментарии верхнего уровня — от новых к старым
+func (uc *commentUseCase) List(ctx context.Context, filter entity.CommentFilter) ([]*entity.CommentWithUser, int, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.CommentSortNewest
+		if filter.ParentID != nil {
+			filter.Sort = entity.CommentSortOldest
+		}
+	}
+	switch filter.Sort {
+	case entity.CommentSortNewest, entity.CommentSortOldest, entity.CommentSortTop:
+	default:
+		return nil, 0, errors.NewValidationError("Некорректная сортировка", map[string]interface{}{
+			"sort":    filter.Sort,
+			"allowed": entity.CommentSorts,
+		})
+	}
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if filter.ParentID != nil {
+		parent, err := uc.commentRepo.GetByID(ctx, *filter.ParentID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err = uc.ensureCommentVisible(ctx, parent); err != nil {
+			return nil, 0, err
+		}
+	} else if err := uc.ensureChapterCommentsReadable(ctx, filter.ChapterID); err != nil {
+		return nil, 0, err
+	}
+
+	viewerID, moderator := commentViewer(ctx)
+	filter.IncludeHidden = moderator
+
+	comments, total, err := uc.commentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, comment := range comments {
+		prepareComment(comment, moderator)
+	}
+	uc.annotateVotes(ctx, viewerID, comments...)
+
+	return comments, total, nil
+}
+
+// Update редактирует собственный комментарий; предыдущая версия текста попадает в историю правок
+func (uc *commentUseCase) Update(ctx context.Context, userID, id int64, input *entity.CommentUpdate) (*entity.CommentWithUser, error) {
+	existing, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("Комментарий не найден", nil)
+	}
+	if existing.UserID != userID {
+		return nil, errors.NewForbiddenError("Можно редактировать только свои комментарии", nil)
+	}
+	if existing.HiddenAt != nil {
+		return nil, errors.NewForbiddenError("Комментарий скрыт модератором и не может быть изменен", nil)
+	}
+
+	content, err := validateCommentContent(input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := existing.Comment
+	comment.Content = content
+	comment.ContentHTML = markdown.Render(content)
+	if input.Spoiler != nil {
+		comment.Spoiler = *input.Spoiler
+	}
+
+	if err = uc.commentRepo.Update(ctx, &comment, userID); err != nil {
+		return nil, err
+	}
+
+	return uc.GetByID(ctx, id)
+}
+
+// Delete удаляет комментарий. Автор удаляет свои комментарии, модератор — любые
+func (uc *commentUseCase) Delete(ctx context.Context, userID, id int64) error {
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.DeletedAt != nil {
+		return errors.NewNotFoundError("Комментарий не найден", nil)
+	}
+
+	moderated := false
+	if comment.UserID != userID {
+		actor, ok := ActorFromContext(ctx)
+		if !ok || !actor.HasPermission(entity.PermissionCommentModerate) {
+			return errors.NewForbiddenError("Можно удалять только свои комментарии", nil)
+		}
+		moderated = true
+	}
+
+	if err = uc.commentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if moderated {
+		uc.log.Info("Комментарий удален модератором", "event", "comment_deleted",
+			"comment_id", id, "author_id", comment.UserID, "moderator_id", userID)
+	}
+
+	invalidateCommentTargetCache(ctx, uc.cacheRepo, uc.log, &comment.Comment)
+
+	return nil
+}
+
+// History возвращает предыдущие версии текста комментария
+func (uc *commentUseCase) History(ctx context.Context, id int64) ([]*entity.CommentEdit, error) {
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.ensureCommentVisible(ctx, comment); err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return []*entity.CommentEdit{}, nil
+	}
+	if _, moderator := commentViewer(ctx); comment.HiddenAt != nil && !moderator {
+		return []*entity.CommentEdit{}, nil
+	}
+
+	return uc.commentRepo.ListEdits(ctx, id)
+}
+
+// Vote ставит комментарию оценку 1 или -1; 0 снимает ранее поставленную оценку
+func (uc *commentUseCase) Vote(ctx context.Context, userID, id int64, value int) (*entity.CommentVoteResult, error) {
+	if value < -1 || value > 1 {
+		return nil, errors.NewValidationError("Оценка должна быть равна 1, -1 или 0", map[string]interface{}{
+			"value": value,
+		})
+	}
+
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.ensureCommentVisible(ctx, comment); err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil || comment.HiddenAt != nil {
+		return nil, errors.NewNotFoundError("Комментарий не найден", nil)
+	}
+	if comment.UserID == userID {
+		return nil, errors.NewForbiddenError("Нельзя оценивать собственные комментарии", nil)
+	}
+
+	return uc.commentRepo.Vote(ctx, userID, id, value)
+}
+
+// Report отправляет жалобу на комментарий. После commentAutoHideReports открытых жалоб
+// комментарий автоматически скрывается до решения модератора
+func (uc *commentUseCase) Report(ctx context.Context, userID, id int64, input *entity.CommentReportCreate) (*entity.CommentReport, error) {
+	switch input.Reason {
+	case entity.ReportReasonSpam, entity.ReportReasonAbuse, entity.ReportReasonSpoiler,
+		entity.ReportReasonOfftopic, entity.ReportReasonOther:
+	default:
+		return nil, errors.NewValidationError("Некорректная причина жалобы", map[string]interface{}{
+			"reason":  input.Reason,
+			"allowed": entity.ReportReasons,
+		})
+	}
+
+	details := strings.TrimSpace(input.Details)
+	if input.Reason == entity.ReportReasonOther && details == "" {
+		return nil, errors.NewValidationError("Для причины other необходимо пояснение", nil)
+	}
+	if utf8.RuneCountInString(details) > reportDetailsMaxLength {
+		return nil, errors.NewValidationError(fmt.Sprintf("Пояснение к жалобе не может быть длиннее %d символов", reportDetailsMaxLength), nil)
+	}
+
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.ensureCommentVisible(ctx, comment); err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("Комментарий не найден", nil)
+	}
+	if comment.UserID == userID {
+		return nil, errors.NewValidationError("Нельзя пожаловаться на собственный комментарий", nil)
+	}
+
+	report := &entity.CommentReport{
+		CommentID:  id,
+		ReporterID: userID,
+		Reason:     input.Reason,
+		Details:    details,
+	}
+	openReports, err := uc.moderationRepo.CreateReport(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if openReports >= commentAutoHideReports && comment.HiddenAt == nil {
+		uc.autoHide(ctx, &comment.Comment, openReports)
+	}
+
+	return report, nil
+}
+
+// autoHide скрывает комментарий по числу жалоб и записывает действие в журнал модерации.
+// Ошибки только логируются: жалоба уже сохранена, а комментарий останется в очереди модерации
+func (uc *commentUseCase) autoHide(ctx context.Context, comment *entity.Comment, openReports int) {
+	hidden, err := uc.commentRepo.SetHidden(ctx, comment.ID, true)
+	if err != nil {
+		uc.log.Error("Ошибка автоматического скрытия комментария", "error", err.Error(), "comment_id", comment.ID)
+		return
+	}
+	if !hidden {
+		return
+	}
+
+	entry := &entity.ModerationLogEntry{
+		Action:       entity.ModerationActionAutoHide,
+		CommentID:    &comment.ID,
+		TargetUserID: &comment.UserID,
+		Reason:       fmt.Sprintf("Открытых жалоб: %d", openReports),
+	}
+	if err = uc.moderationRepo.LogAction(ctx, entry); err != nil {
+		uc.log.Error("Ошибка записи в журнал модерации", "error", err.Error(), "comment_id", comment.ID)
+	}
+
+	uc.log.Warn("Комментарий скрыт по жалобам", "event", "comment_auto_hidden",
+		"comment_id", comment.ID, "author_id", comment.UserID, "open_reports", openReports)
+
+	invalidateCommentTargetCache(ctx, uc.cacheRepo, uc.log, comment)
+}
+
+// checkRateLimit ограничивает частоту создания комментариев пользователем.
+// Модераторы не ограничиваются; при недоступности кеша комментарий создается без проверки
+func (uc *commentUseCase) checkRateLimit(ctx context.Context, userID int64) error {
+	if _, moderator := commentViewer(ctx); moderator {
+		return nil
+	}
+
+	key := fmt.Sprintf("ratelimit:comments:%d", userID)
+	count, err := uc.cacheRepo.IncrWithTTL(ctx, key, commentRateWindow)
+	if err != nil {
+		uc.log.Error("Ошибка учета частоты комментариев", "error", err.Error(), "user_id", userID)
+		return nil
+	}
+
+	if count <= commentRateLimit {
+		return nil
+	}
+
+	retryAfter, err := uc.cacheRepo.TTL(ctx, key)
+	if err != nil || retryAfter <= 0 {
+		retryAfter = commentRateWindow
+	}
+	uc.log.Warn("Превышена частота создания комментариев", "event", "comment_rate_limited", "user_id", userID)
+
+	return errors.NewRateLimitError("Слишком много комментариев, повторите попытку позже", retryAfter)
+}
+
+// annotateVotes проставляет комментариям голоса текущего пользователя
+func (uc *commentUseCase) annotateVotes(ctx context.Context, viewerID int64, comments ...*entity.CommentWithUser) {
+	if viewerID == 0 || len(comments) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	votes, err := uc.commentRepo.ListUserVotes(ctx, viewerID, ids)
+	if err != nil {
+		uc.log.Error("Ошибка получения голосов пользователя", "error", err.Error(), "user_id", viewerID)
+		return
+	}
+
+	for _, comment := range comments {
+		if vote, ok := votes[comment.ID]; ok {
+			comment.MyVote = &vote
+		}
+	}
+}
+
+// invalidateCommentTargetCache сбрасывает кеш манги или главы, чтобы обновился счетчик комментариев.
+// Кешированные списки обновятся по истечении срока жизни
+func invalidateCommentTargetCache(ctx context.Context, cacheRepo repository.CacheRepository, log logger.Logger, comment *entity.Comment) {
+	var keys []string
+	if comment.MangaID != nil {
+		keys = append(keys, fmt.Sprintf("manga:%d", *comment.MangaID))
+	}
+	if comment.ChapterID != nil {
+		keys = append(keys, fmt.Sprintf("chapter:%d", *comment.ChapterID))
+	}
+
+	for _, key := range keys {
+		if err := cacheRepo.Delete(ctx, key); err != nil {
+			log.Error("Ошибка инвалидации кеша", "error", err.Error(), "key", key)
+		}
+	}
+}
+
+// validateCommentContent нормализует и проверяет текст комментария
+func validateCommentContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.NewValidationError("Текст комментария не может быть пустым", nil)
+	}
+	if utf8.RuneCountInString(content) > commentMaxLength {
+		return "", errors.NewValidationError(fmt.Sprintf("Текст комментария не может быть длиннее %d символов", commentMaxLength), nil)
+	}
+
+	return content, nil
+}
+
+// prepareComment рендерит HTML для комментариев, созданных до появления Markdown,
+// и убирает текст скрытых комментариев для всех, кроме модераторов
+func prepareComment(comment *entity.CommentWithUser, moderator bool) {
+	if comment.HiddenAt != nil && !moderator {
+		comment.Content = ""
+		comment.ContentHTML = ""
+		return
+	}
+	if comment.DeletedAt == nil && comment.ContentHTML == "" && comment.Content != "" {
+		comment.ContentHTML = markdown.Render(comment.Content)
+	}
+}
+
+// commentViewer возвращает идентификатор текущего пользователя (0 для анонимного) и признак модератора
+func commentViewer(ctx context.Context) (int64, bool) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return actor.UserID, actor.HasPermission(entity.PermissionCommentModerate)
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ограничения данных автора
+const (
+	creatorNameMaxLength = 100
+	creatorMaxAliases    = 20
+)
+
+// CreatorUseCase интерфейс, определяющий бизнес-логику авторов и художников
+type CreatorUseCase interface {
+	Get(ctx context.Context, id int64) (*entity.CreatorDetails, error)
+	List(ctx context.Context, filter entity.CreatorFilter) ([]*entity.Creator, int, error)
+	Create(ctx context.Context, input entity.CreatorInput) (*entity.Creator, error)
+	Update(ctx context.Context, id int64, input entity.CreatorInput) (*entity.Creator, error)
+	Merge(ctx context.Context, targetID, sourceID int64) (*entity.CreatorDetails, error)
+}
+
+// creatorUseCase реализация интерфейса CreatorUseCase
+type creatorUseCase struct {
+	creatorRepo repository.CreatorRepository
+	cacheRepo   repository.CacheRepository
+	log         logger.Logger
+}
+
+// NewCreatorUseCase создает новый экземпляр CreatorUseCase
+func NewCreatorUseCase(
+	creatorRepo repository.CreatorRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) CreatorUseCase {
+	return &creatorUseCase{
+		creatorRepo: creatorRepo,
+		cacheRepo:   cacheRepo,
+		log:         log,
+	}
+}
+
+// Get возвращает автора с библиографией
+func (uc *creatorUseCase) Get(ctx context.Context, id int64) (*entity.CreatorDetails, error) {
+	creator, err := uc.creatorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	works, err := uc.creatorRepo.ListWorks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.CreatorDetails{Creator: *creator, Works: works}, nil
+}
+
+// List ищет авторов по имени
+func (uc *creatorUseCase) List(ctx context.Context, filter entity.CreatorFilter) ([]*entity.Creator, int, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	return uc.creatorRepo.List(ctx, filter)
+}
+
+// Create создает автора
+func (uc *creatorUseCase) Create(ctx context.Context, input entity.CreatorInput) (*entity.Creator, error) {
+	input, err := normalizeCreatorInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uc.creatorRepo.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.creatorRepo.GetByID(ctx, id)
+}
+
+// Update изменяет автора. Кеш его манги сбрасывается, так как в ней выводится имя автора
+func (uc *creatorUseCase) Update(ctx context.Context, id int64, input entity.CreatorInput) (*entity.Creator, error) {
+	input, err := normalizeCreatorInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = uc.creatorRepo.Update(ctx, id, input); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateWorksCache(ctx, id)
+
+	return uc.creatorRepo.GetByID(ctx, id)
+}
+
+// Merge объединяет дубликат с автором и возвращает автора с объединенной библиографией
+func (uc *creatorUseCase) Merge(ctx context.Context, targetID, sourceID int64) (*entity.CreatorDetails, error) {
+	if targetID == sourceID {
+		return nil, errors.NewValidationError("Нельзя объединить автора с самим собой", map[string]interface{}{
+			"source_id": sourceID,
+		})
+	}
+
+	if err := uc.creatorRepo.Merge(ctx, targetID, sourceID); err != nil {
+		return nil, err
+	}
+
+	uc.log.Info("Авторы объединены", "event", "creators_merged", "target_id", targetID, "source_id", sourceID)
+	uc.invalidateWorksCache(ctx, targetID)
+
+	return uc.Get(ctx, targetID)
+}
+
+// invalidateWorksCache сбрасывает кеш манги автора
+func (uc *creatorUseCase) invalidateWorksCache(ctx context.Context, creatorID int64) {
+	works, err := uc.creatorRepo.ListWorks(ctx, creatorID)
+	if err != nil {
+		uc.log.Error("Ошибка получения манги автора для инвалидации кеша", "error", err.Error(), "creator_id", creatorID)
+		return
+	}
+
+	for _, work := range works {
+		key := fmt.Sprintf("manga:%d", work.MangaID)
+		if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+			uc.log.Error("Ошибка инвалидации кеша", "error", err.Error(), "key", key)
+		}
+	}
+}
+
+// normalizeCreatorInput проверяет данные автора, убирает пробелы и повторы в вариантах имени
+func normalizeCreatorInput(input entity.CreatorInput) (entity.CreatorInput, error) {
+	input.Name = strings.Join(strings.Fields(input.Name), " ")
+	input.Bio = strings.TrimSpace(input.Bio)
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > creatorNameMaxLength {
+		return input, errors.NewValidationError(
+			fmt.Sprintf("Имя автора должно содержать от 1 до %d символов", creatorNameMaxLength),
+			map[string]interface{}{"name": input.Name},
+		)
+	}
+
+	aliases := make([]string, 0, len(input.Aliases))
+	seen := map[string]bool{input.Name: true}
+	for _, alias := range input.Aliases {
+		alias = strings.Join(strings.Fields(alias), " ")
+		if alias == "" || seen[alias] {
+			continue
+		}
+		if utf8.RuneCountInString(alias) > creatorNameMaxLength {
+			return input, errors.NewValidationError(
+				fmt.Sprintf("Вариант имени должен содержать не более %d символов", creatorNameMaxLength),
+				map[string]interface{}{"alias": alias},
+			)
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+	if len(aliases) > creatorMaxAliases {
+		return input, errors.NewValidationError(
+			fmt.Sprintf("Не более %d вариантов имени", creatorMaxAliases),
+			map[string]interface{}{"aliases": len(aliases)},
+		)
+	}
+	input.Aliases = aliases
+
+	return input, nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+)
+
+// FollowUseCase интерфейс, определяющий бизнес-логику подписок на мангу
+type FollowUseCase interface {
+	Follow(ctx context.Context, userID, mangaID int64) (*entity.MangaFollow, error)
+	Unfollow(ctx context.Context, userID, mangaID int64) error
+	Status(ctx context.Context, userID, mangaID int64) (*entity.FollowStatus, error)
+	List(ctx context.Context, userID int64, filter entity.FollowFilter) ([]*entity.MangaFollowWithManga, int, error)
+}
+
+// followUseCase реализация интерфейса FollowUseCase
+type followUseCase struct {
+	followRepo repository.FollowRepository
+	mangaRepo  repository.MangaRepository
+	log        logger.Logger
+}
+
+// NewFollowUseCase создает новый экземпляр FollowUseCase
+func NewFollowUseCase(
+	followRepo repository.FollowRepository,
+	mangaRepo repository.MangaRepository,
+	log logger.Logger,
+) FollowUseCase {
+	return &followUseCase{
+		followRepo: followRepo,
+		mangaRepo:  mangaRepo,
+		log:        log,
+	}
+}
+
+// Follow подписывает пользователя на уведомления о новых главах манги
+func (uc *followUseCase) Follow(ctx context.Context, userID, mangaID int64) (*entity.MangaFollow, error) {
+	if _, err := uc.mangaRepo.GetByID(ctx, mangaID); err != nil {
+		return nil, err
+	}
+
+	follow := &entity.MangaFollow{
+		UserID:  userID,
+		MangaID: mangaID,
+	}
+	if err := uc.followRepo.Follow(ctx, follow); err != nil {
+		return nil, err
+	}
+
+	return follow, nil
+}
+
+// Unfollow отменяет подписку на мангу
+func (uc *followUseCase) Unfollow(ctx context.Context, userID, mangaID int64) error {
+	return uc.followRepo.Unfollow(ctx, userID, mangaID)
+}
+
+// Status возвращает состояние подписки пользователя на мангу
+func (uc *followUseCase) Status(ctx context.Context, userID, mangaID int64) (*entity.FollowStatus, error) {
+	following, err := uc.followRepo.IsFollowing(ctx, userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.FollowStatus{MangaID: mangaID, Following: following}, nil
+}
+
+// List возвращает подписки пользователя
+func (uc *followUseCase) List(ctx context.Context, userID int64, filter entity.FollowFilter) ([]*entity.MangaFollowWithManga, int, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.followRepo.ListByUser(ctx, userID, filter)
+}
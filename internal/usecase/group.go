@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ограничения данных группы переводчиков
+const (
+	groupNameMaxLength    = 100
+	groupWebsiteMaxLength = 255
+)
+
+// ScanlationGroupUseCase интерфейс, определяющий бизнес-логику групп переводчиков
+type ScanlationGroupUseCase interface {
+	Get(ctx context.Context, id int64) (*entity.ScanlationGroup, error)
+	List(ctx context.Context, filter entity.ScanlationGroupFilter) ([]*entity.ScanlationGroup, int, error)
+	Create(ctx context.Context, input entity.ScanlationGroupInput) (*entity.ScanlationGroup, error)
+	Update(ctx context.Context, id int64, input entity.ScanlationGroupInput) (*entity.ScanlationGroup, error)
+	ListReleases(ctx context.Context, groupID int64, limit, offset int) ([]*entity.GroupRelease, int, error)
+}
+
+// scanlationGroupUseCase реализация интерфейса ScanlationGroupUseCase
+type scanlationGroupUseCase struct {
+	groupRepo repository.ScanlationGroupRepository
+	cacheRepo repository.CacheRepository
+	log       logger.Logger
+}
+
+// NewScanlationGroupUseCase создает новый экземпляр ScanlationGroupUseCase
+func NewScanlationGroupUseCase(
+	groupRepo repository.ScanlationGroupRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) ScanlationGroupUseCase {
+	return &scanlationGroupUseCase{
+		groupRepo: groupRepo,
+		cacheRepo: cacheRepo,
+		log:       log,
+	}
+}
+
+// Get возвращает группу
+func (uc *scanlationGroupUseCase) Get(ctx context.Context, id int64) (*entity.ScanlationGroup, error) {
+	return uc.groupRepo.GetByID(ctx, id)
+}
+
+// List ищет группы по названию
+func (uc *scanlationGroupUseCase) List(ctx context.Context, filter entity.ScanlationGroupFilter) ([]*entity.ScanlationGroup, int, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	return uc.groupRepo.List(ctx, filter)
+}
+
+// Create создает группу
+func (uc *scanlationGroupUseCase) Create(ctx context.Context, input entity.ScanlationGroupInput) (*entity.ScanlationGroup, error) {
+	input, err := normalizeGroupInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uc.groupRepo.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.groupRepo.GetByID(ctx, id)
+}
+
+// Update изменяет группу
+func (uc *scanlationGroupUseCase) Update(ctx context.Context, id int64, input entity.ScanlationGroupInput) (*entity.ScanlationGroup, error) {
+	input, err := normalizeGroupInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = uc.groupRepo.Update(ctx, id, input); err != nil {
+		return nil, err
+	}
+
+	// Название группы выводится в главах, поэтому сбрасывается кеш списков глав, глав группы и читалки
+	chapterIDs, err := uc.groupRepo.ListChapterIDs(ctx, id)
+	if err != nil {
+		uc.log.Error("Ошибка получения глав группы для инвалидации кеша", "error", err.Error(), "group_id", id)
+	}
+	var keys []string
+	for mangaID, ids := range chapterIDs {
+		keys = append(keys, fmt.Sprintf("manga:%d:chapters", mangaID))
+		for _, chapterID := range ids {
+			keys = append(keys, fmt.Sprintf("chapter:%d", chapterID), readerCacheKey(chapterID))
+		}
+	}
+	for _, key := range keys {
+		if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+			uc.log.Error("Ошибка инвалидации кеша", "error", err.Error(), "key", key)
+		}
+	}
+
+	return uc.groupRepo.GetByID(ctx, id)
+}
+
+// ListReleases возвращает главы группы, начиная с последних
+func (uc *scanlationGroupUseCase) ListReleases(ctx context.Context, groupID int64, limit, offset int) ([]*entity.GroupRelease, int, error) {
+	if _, err := uc.groupRepo.GetByID(ctx, groupID); err != nil {
+		return nil, 0, err
+	}
+
+	return uc.groupRepo.ListReleases(ctx, groupID, limit, offset)
+}
+
+// normalizeGroupInput проверяет данные группы и убирает лишние пробелы
+func normalizeGroupInput(input entity.ScanlationGroupInput) (entity.ScanlationGroupInput, error) {
+	input.Name = strings.Join(strings.Fields(input.Name), " ")
+	input.Description = strings.TrimSpace(input.Description)
+	input.Website = strings.TrimSpace(input.Website)
+
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > groupNameMaxLength {
+		return input, errors.NewValidationError(
+			fmt.Sprintf("Название группы должно содержать от 1 до %d символов", groupNameMaxLength),
+			map[string]interface{}{"name": input.Name},
+		)
+	}
+
+	if input.Website != "" {
+		parsed, err := url.Parse(input.Website)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			len(input.Website) > groupWebsiteMaxLength {
+			return input, errors.NewValidationError("Некорректный адрес сайта группы", map[string]interface{}{
+				"website": input.Website,
+			})
+		}
+	}
+
+	return input, nil
+}
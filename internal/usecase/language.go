@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/domain/entity"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ограничения переводов манги
+const (
+	altTitleMaxLength    = 255
+	mangaMaxAltTitles    = 50
+	mangaMaxDescriptions = 20
+)
+
+// languageCodePattern допустимый код языка: ja, en, ja-ro, zh-hk
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// languagesContextKey ключ контекста для языков, предпочитаемых клиентом
+type languagesContextKey struct{}
+
+// WithLanguages сохраняет в контексте языки клиента в порядке предпочтения
+func WithLanguages(ctx context.Context, languages []string) context.Context {
+	return context.WithValue(ctx, languagesContextKey{}, languages)
+}
+
+// LanguagesFromContext возвращает языки клиента в порядке предпочтения
+func LanguagesFromContext(ctx context.Context) []string {
+	languages, _ := ctx.Value(languagesContextKey{}).([]string)
+	return languages
+}
+
+// NormalizeLanguage приводит код языка к нижнему регистру и проверяет его формат.
+// Возвращает пустую строку для некорректного кода
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+	if !languageCodePattern.MatchString(language) {
+		return ""
+	}
+	return language
+}
+
+// localizeManga подставляет описание и альтернативное название на первом подходящем языке клиента.
+// Для кода с регионом подходит и перевод на основной язык: для en-us — en
+func localizeManga(manga *entity.Manga, languages []string) {
+	for _, language := range languages {
+		for _, candidate := range languageCandidates(language) {
+			description, hasDescription := manga.Descriptions[candidate]
+			title := altTitleFor(manga, candidate)
+			if !hasDescription && title == "" {
+				continue
+			}
+
+			if hasDescription {
+				manga.Description = description
+			}
+			manga.LocalizedTitle = title
+			manga.Language = candidate
+			return
+		}
+	}
+}
+
+// languageCandidates возвращает код языка и его основную часть, если код содержит регион
+func languageCandidates(language string) []string {
+	if base, _, found := strings.Cut(language, "-"); found {
+		return []string{language, base}
+	}
+	return []string{language}
+}
+
+// altTitleFor возвращает первое альтернативное название на языке или пустую строку
+func altTitleFor(manga *entity.Manga, language string) string {
+	for _, altTitle := range manga.AltTitles {
+		if altTitle.Language == language {
+			return altTitle.Title
+		}
+	}
+	return ""
+}
+
+// normalizeMangaTranslations проверяет альтернативные названия и описания манги,
+// приводит коды языков к нижнему регистру и убирает повторы
+func normalizeMangaTranslations(manga *entity.Manga) error {
+	if manga.AltTitles != nil {
+		altTitles := make([]*entity.AltTitle, 0, len(manga.AltTitles))
+		seen := make(map[string]bool, len(manga.AltTitles))
+		for _, altTitle := range manga.AltTitles {
+			if altTitle == nil {
+				continue
+			}
+
+			title := strings.Join(strings.Fields(altTitle.Title), " ")
+			language := NormalizeLanguage(altTitle.Language)
+			if language == "" {
+				return errors.NewValidationError("Некорректный код языка", map[string]interface{}{
+					"language": altTitle.Language,
+				})
+			}
+			if title == "" || utf8.RuneCountInString(title) > altTitleMaxLength {
+				return errors.NewValidationError(
+					fmt.Sprintf("Альтернативное название должно содержать от 1 до %d символов", altTitleMaxLength),
+					map[string]interface{}{"title": altTitle.Title},
+				)
+			}
+
+			key := language + "\x00" + title
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			altTitles = append(altTitles, &entity.AltTitle{Title: title, Language: language})
+		}
+		if len(altTitles) > mangaMaxAltTitles {
+			return errors.NewValidationError(
+				fmt.Sprintf("Не более %d альтернативных названий", mangaMaxAltTitles),
+				map[string]interface{}{"alt_titles": len(altTitles)},
+			)
+		}
+		manga.AltTitles = altTitles
+	}
+
+	if manga.Descriptions != nil {
+		descriptions := make(map[string]string, len(manga.Descriptions))
+		for language, description := range manga.Descriptions {
+			normalized := NormalizeLanguage(language)
+			if normalized == "" {
+				return errors.NewValidationError("Некорректный код языка", map[string]interface{}{
+					"language": language,
+				})
+			}
+			if description = strings.TrimSpace(description); description != "" {
+				descriptions[normalized] = description
+			}
+		}
+		if len(descriptions) > mangaMaxDescriptions {
+			return errors.NewValidationError(
+				fmt.Sprintf("Не более %d описаний на разных языках", mangaMaxDescriptions),
+				map[string]interface{}{"descriptions": len(descriptions)},
+			)
+		}
+		manga.Descriptions = descriptions
+	}
+
+	return nil
+}
@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ограничения библиотеки
+const (
+	libraryMaxShelves      = 50
+	libraryShelfNameMaxLen = 50
+	libraryNotesMaxLen     = 5000
+	libraryImportMaxItems  = 10000
+)
+
+// LibraryUseCase интерфейс, определяющий бизнес-логику библиотеки пользователя
+type LibraryUseCase interface {
+	List(ctx context.Context, userID int64, filter entity.LibraryFilter) ([]*entity.LibraryEntryWithManga, int, error)
+	Stats(ctx context.Context, userID int64) (*entity.LibraryStats, error)
+	Set(ctx context.Context, userID, mangaID int64, req *entity.LibraryEntryUpsert) (*entity.LibraryEntry, error)
+	Remove(ctx context.Context, userID, mangaID int64) error
+
+	ListShelves(ctx context.Context, userID int64) ([]*entity.LibraryShelf, error)
+	CreateShelf(ctx context.Context, userID int64, req *entity.LibraryShelfCreate) (*entity.LibraryShelf, error)
+	RenameShelf(ctx context.Context, userID, shelfID int64, req *entity.LibraryShelfCreate) (*entity.LibraryShelf, error)
+	DeleteShelf(ctx context.Context, userID, shelfID int64) error
+	AddToShelf(ctx context.Context, userID, shelfID, mangaID int64) error
+	RemoveFromShelf(ctx context.Context, userID, shelfID, mangaID int64) error
+
+	Export(ctx context.Context, userID int64) ([]byte, error)
+	Import(ctx context.Context, userID int64, r io.Reader) (*entity.LibraryImportResult, error)
+}
+
+// libraryUseCase реализация интерфейса LibraryUseCase
+type libraryUseCase struct {
+	libraryRepo repository.LibraryRepository
+	mangaRepo   repository.MangaRepository
+	log         logger.Logger
+}
+
+// NewLibraryUseCase создает новый экземпляр LibraryUseCase
+func NewLibraryUseCase(
+	libraryRepo repository.LibraryRepository,
+	mangaRepo repository.MangaRepository,
+	log logger.Logger,
+) LibraryUseCase {
+	return &libraryUseCase{
+		libraryRepo: libraryRepo,
+		mangaRepo:   mangaRepo,
+		log:         log,
+	}
+}
+
+// List возвращает библиотеку пользователя по фильтру
+func (uc *libraryUseCase) List(ctx context.Context, userID int64, filter entity.LibraryFilter) ([]*entity.LibraryEntryWithManga, int, error) {
+	if filter.Status != "" && !isLibraryStatus(filter.Status) {
+		return nil, 0, errors.NewValidationError("Некорректный статус", map[string]interface{}{
+			"status":  filter.Status,
+			"allowed": entity.LibraryStatuses,
+		})
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = entity.LibrarySortUpdated
+	case entity.LibrarySortUpdated, entity.LibrarySortAdded, entity.LibrarySortTitle, entity.LibrarySortScore:
+	default:
+		return nil, 0, errors.NewValidationError("Некорректная сортировка", map[string]interface{}{
+			"sort":    filter.Sort,
+			"allowed": []string{entity.LibrarySortUpdated, entity.LibrarySortAdded, entity.LibrarySortTitle, entity.LibrarySortScore},
+		})
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.libraryRepo.List(ctx, userID, filter)
+}
+
+// Stats возвращает количество записей библиотеки по каждому статусу
+func (uc *libraryUseCase) Stats(ctx context.Context, userID int64) (*entity.LibraryStats, error) {
+	counts, err := uc.libraryRepo.CountByStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.LibraryStats{ByStatus: make(map[string]int, len(entity.LibraryStatuses))}
+	for _, status := range entity.LibraryStatuses {
+		stats.ByStatus[status] = counts[status]
+		stats.Total += counts[status]
+	}
+
+	return stats, nil
+}
+
+// Set добавляет мангу в библиотеку или заменяет статус, оценку и заметки.
+// Даты начала и окончания чтения проставляются автоматически при смене статуса
+func (uc *libraryUseCase) Set(ctx context.Context, userID, mangaID int64, req *entity.LibraryEntryUpsert) (*entity.LibraryEntry, error) {
+	if !isLibraryStatus(req.Status) {
+		return nil, errors.NewValidationError("Некорректный статус", map[string]interface{}{
+			"status":  req.Status,
+			"allowed": entity.LibraryStatuses,
+		})
+	}
+	if req.Score != nil && (*req.Score < 1 || *req.Score > 10) {
+		return nil, errors.NewValidationError("Оценка должна быть от 1 до 10", nil)
+	}
+	if utf8.RuneCountInString(req.Notes) > libraryNotesMaxLen {
+		return nil, errors.NewValidationError(fmt.Sprintf("Заметки не могут быть длиннее %d символов", libraryNotesMaxLen), nil)
+	}
+
+	if _, err := uc.mangaRepo.GetByID(ctx, mangaID); err != nil {
+		return nil, err
+	}
+
+	entry := &entity.LibraryEntry{
+		UserID:  userID,
+		MangaID: mangaID,
+		Status:  req.Status,
+		Score:   req.Score,
+		Notes:   req.Notes,
+	}
+
+	existing, err := uc.libraryRepo.Get(ctx, userID, mangaID)
+	if err != nil && !errors.IsNotFoundError(err) {
+		return nil, err
+	}
+	if existing != nil {
+		entry.StartedAt = existing.StartedAt
+		entry.FinishedAt = existing.FinishedAt
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if entry.StartedAt == nil && entry.Status != entity.LibraryStatusPlanned {
+		entry.StartedAt = &today
+	}
+	if entry.Status != entity.LibraryStatusCompleted {
+		entry.FinishedAt = nil
+	} else if entry.FinishedAt == nil {
+		entry.FinishedAt = &today
+	}
+
+	if err = uc.libraryRepo.Upsert(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Remove удаляет мангу из библиотеки
+func (uc *libraryUseCase) Remove(ctx context.Context, userID, mangaID int64) error {
+	return uc.libraryRepo.Delete(ctx, userID, mangaID)
+}
+
+// ListShelves возвращает полки пользователя
+func (uc *libraryUseCase) ListShelves(ctx context.Context, userID int64) ([]*entity.LibraryShelf, error) {
+	return uc.libraryRepo.ListShelves(ctx, userID)
+}
+
+// CreateShelf создает полку пользователя
+func (uc *libraryUseCase) CreateShelf(ctx context.Context, userID int64, req *entity.LibraryShelfCreate) (*entity.LibraryShelf, error) {
+	name, err := normalizeShelfName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	shelves, err := uc.libraryRepo.ListShelves(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(shelves) >= libraryMaxShelves {
+		return nil, errors.NewValidationError(fmt.Sprintf("Нельзя создать больше %d полок", libraryMaxShelves), nil)
+	}
+
+	shelf := &entity.LibraryShelf{
+		UserID: userID,
+		Name:   name,
+	}
+	if err = uc.libraryRepo.CreateShelf(ctx, shelf); err != nil {
+		return nil, err
+	}
+
+	return shelf, nil
+}
+
+// RenameShelf переименовывает полку пользователя
+func (uc *libraryUseCase) RenameShelf(ctx context.Context, userID, shelfID int64, req *entity.LibraryShelfCreate) (*entity.LibraryShelf, error) {
+	name, err := normalizeShelfName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	shelf := &entity.LibraryShelf{
+		ID:     shelfID,
+		UserID: userID,
+		Name:   name,
+	}
+	if err = uc.libraryRepo.RenameShelf(ctx, shelf); err != nil {
+		return nil, err
+	}
+
+	return shelf, nil
+}
+
+// DeleteShelf удаляет полку пользователя
+func (uc *libraryUseCase) DeleteShelf(ctx context.Context, userID, shelfID int64) error {
+	return uc.libraryRepo.DeleteShelf(ctx, userID, shelfID)
+}
+
+// AddToShelf кладет мангу из библиотеки на полку
+func (uc *libraryUseCase) AddToShelf(ctx context.Context, userID, shelfID, mangaID int64) error {
+	return uc.libraryRepo.AddToShelf(ctx, userID, shelfID, mangaID)
+}
+
+// RemoveFromShelf убирает мангу с полки
+func (uc *libraryUseCase) RemoveFromShelf(ctx context.Context, userID, shelfID, mangaID int64) error {
+	return uc.libraryRepo.RemoveFromShelf(ctx, userID, shelfID, mangaID)
+}
+
+// Export выгружает библиотеку пользователя в формате MyAnimeList
+func (uc *libraryUseCase) Export(ctx context.Context, userID int64) ([]byte, error) {
+	entries, _, err := uc.libraryRepo.List(ctx, userID, entity.LibraryFilter{Sort: entity.LibrarySortTitle})
+	if err != nil {
+		return nil, err
+	}
+
+	shelves, err := uc.libraryRepo.ListShelves(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	shelfNames := make(map[int64]string, len(shelves))
+	for _, shelf := range shelves {
+		shelfNames[shelf.ID] = shelf.Name
+	}
+
+	data, err := encodeMALExport(entries, shelfNames)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка формирования файла экспорта", err)
+	}
+
+	uc.log.Info("Экспорт библиотеки", "event", "library_exported", "user_id", userID, "entries", len(entries))
+
+	return data, nil
+}
+
+// Import загружает библиотеку из файла экспорта MyAnimeList.
+// Манга сопоставляется с каталогом по ID MyAnimeList, а без него — по основному или альтернативному названию; теги становятся полками
+func (uc *libraryUseCase) Import(ctx context.Context, userID int64, r io.Reader) (*entity.LibraryImportResult, error) {
+	malItems, err := decodeMALExport(r)
+	if err != nil {
+		return nil, errors.NewValidationError("Некорректный файл экспорта MyAnimeList", map[string]interface{}{
+			"reason": err.Error(),
+		})
+	}
+	if len(malItems) > libraryImportMaxItems {
+		return nil, errors.NewValidationError("Слишком много записей в файле импорта", map[string]interface{}{
+			"max_items": libraryImportMaxItems,
+		})
+	}
+
+	titles := make([]string, 0, len(malItems))
+	malIDs := make([]int64, 0, len(malItems))
+	for _, item := range malItems {
+		titles = append(titles, strings.TrimSpace(item.Title.Value))
+		if item.MangaDBID > 0 {
+			malIDs = append(malIDs, item.MangaDBID)
+		}
+	}
+	matches, err := uc.libraryRepo.MatchMangaByTitles(ctx, titles)
+	if err != nil {
+		return nil, err
+	}
+	malMatches, err := uc.libraryRepo.MatchMangaByMALIDs(ctx, malIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.LibraryImportResult{Unmatched: []string{}}
+	items := make([]*entity.LibraryImportItem, 0, len(malItems))
+	seen := make(map[int64]bool, len(malItems))
+	newShelves := make(map[string]bool)
+	for _, malItem := range malItems {
+		mangaID, ok := malMatches[malItem.MangaDBID]
+		if !ok {
+			mangaID, ok = matches[strings.ToLower(strings.TrimSpace(malItem.Title.Value))]
+		}
+		if !ok {
+			result.Unmatched = append(result.Unmatched, malItem.Title.Value)
+			continue
+		}
+		if seen[mangaID] {
+			result.Skipped++
+			continue
+		}
+		seen[mangaID] = true
+
+		item, err := malItem.toImportItem(userID, mangaID)
+		if err != nil {
+			return nil, errors.NewValidationError("Некорректная запись в файле экспорта MyAnimeList", map[string]interface{}{
+				"title":  malItem.Title.Value,
+				"reason": err.Error(),
+			})
+		}
+		for _, name := range item.Shelves {
+			newShelves[name] = true
+		}
+		items = append(items, item)
+	}
+
+	if len(newShelves) > 0 {
+		shelves, err := uc.libraryRepo.ListShelves(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, shelf := range shelves {
+			delete(newShelves, shelf.Name)
+		}
+		if len(shelves)+len(newShelves) > libraryMaxShelves {
+			return nil, errors.NewValidationError(fmt.Sprintf("После импорта полок станет больше %d", libraryMaxShelves), nil)
+		}
+	}
+
+	imported, updated, err := uc.libraryRepo.Import(ctx, userID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Imported = imported
+	result.Updated = updated
+	result.Skipped += len(items) - imported - updated
+
+	uc.log.Info("Импорт библиотеки", "event", "library_imported", "user_id", userID,
+		"imported", result.Imported, "updated", result.Updated, "skipped", result.Skipped, "unmatched", len(result.Unmatched))
+
+	return result, nil
+}
+
+// isLibraryStatus проверяет, является ли строка допустимым статусом чтения
+func isLibraryStatus(status string) bool {
+	for _, allowed := range entity.LibraryStatuses {
+		if status == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeShelfName проверяет и нормализует название полки
+func normalizeShelfName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.NewValidationError("Название полки не может быть пустым", nil)
+	}
+	if utf8.RuneCountInString(name) > libraryShelfNameMaxLen {
+		return "", errors.NewValidationError(fmt.Sprintf("Название полки не может быть длиннее %d символов", libraryShelfNameMaxLen), nil)
+	}
+	return name, nil
+}
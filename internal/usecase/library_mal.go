@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"manga-reader2/internal/domain/entity"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Формат экспорта списков MyAnimeList
+const (
+	malExportTypeManga = 2
+	malEmptyDate       = "0000-00-00"
+	malDateLayout      = "2006-01-02"
+)
+
+// Статусы MyAnimeList
+const (
+	malStatusReading    = "Reading"
+	malStatusCompleted  = "Completed"
+	malStatusOnHold     = "On-Hold"
+	malStatusDropped    = "Dropped"
+	malStatusPlanToRead = "Plan to Read"
+)
+
+// malStatusToLibrary соответствие статусов MyAnimeList статусам библиотеки.
+// Отдельного статуса «отложено» в библиотеке нет, такие записи считаются читаемыми.
+// Старые выгрузки используют числовые коды статусов
+var malStatusToLibrary = map[string]string{
+	malStatusReading:    entity.LibraryStatusReading,
+	malStatusCompleted:  entity.LibraryStatusCompleted,
+	malStatusOnHold:     entity.LibraryStatusReading,
+	malStatusDropped:    entity.LibraryStatusDropped,
+	malStatusPlanToRead: entity.LibraryStatusPlanned,
+	"1":                 entity.LibraryStatusReading,
+	"2":                 entity.LibraryStatusCompleted,
+	"3":                 entity.LibraryStatusReading,
+	"4":                 entity.LibraryStatusDropped,
+	"6":                 entity.LibraryStatusPlanned,
+}
+
+// libraryStatusToMAL соответствие статусов библиотеки статусам MyAnimeList
+var libraryStatusToMAL = map[string]string{
+	entity.LibraryStatusReading:   malStatusReading,
+	entity.LibraryStatusCompleted: malStatusCompleted,
+	entity.LibraryStatusDropped:   malStatusDropped,
+	entity.LibraryStatusPlanned:   malStatusPlanToRead,
+}
+
+// malCDATA строковое значение, записываемое в секции CDATA
+type malCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+// malExport корневой элемент файла экспорта MyAnimeList
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	MyInfo  malMyInfo  `xml:"myinfo"`
+	Manga   []malManga `xml:"manga"`
+}
+
+// malMyInfo сводка списка пользователя
+type malMyInfo struct {
+	ExportType int `xml:"user_export_type"`
+	Total      int `xml:"user_total_manga"`
+	Reading    int `xml:"user_total_reading"`
+	Completed  int `xml:"user_total_completed"`
+	OnHold     int `xml:"user_total_onhold"`
+	Dropped    int `xml:"user_total_dropped"`
+	PlanToRead int `xml:"user_total_plantoread"`
+}
+
+// malManga запись списка манги MyAnimeList
+type malManga struct {
+	MangaDBID      int64    `xml:"manga_mangadb_id,omitempty"` // ID манги на MyAnimeList; 0 — неизвестен
+	Title          malCDATA `xml:"manga_title"`
+	Volumes        int      `xml:"manga_volumes"`
+	Chapters       int      `xml:"manga_chapters"`
+	MyID           int64    `xml:"my_id"`
+	ReadVolumes    int      `xml:"my_read_volumes"`
+	ReadChapters   int      `xml:"my_read_chapters"`
+	StartDate      string   `xml:"my_start_date"`
+	FinishDate     string   `xml:"my_finish_date"`
+	Score          int      `xml:"my_score"`
+	Status         string   `xml:"my_status"`
+	Comments       malCDATA `xml:"my_comments"`
+	Tags           malCDATA `xml:"my_tags"`
+	UpdateOnImport int      `xml:"update_on_import"`
+}
+
+// encodeMALExport формирует файл экспорта MyAnimeList. Полки записываются в теги.
+// manga_mangadb_id выгружается, если ID манги на MyAnimeList известен из каталога или из импорта
+func encodeMALExport(entries []*entity.LibraryEntryWithManga, shelfNames map[int64]string) ([]byte, error) {
+	export := malExport{
+		MyInfo: malMyInfo{ExportType: malExportTypeManga, Total: len(entries)},
+		Manga:  make([]malManga, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		switch entry.Status {
+		case entity.LibraryStatusReading:
+			export.MyInfo.Reading++
+		case entity.LibraryStatusCompleted:
+			export.MyInfo.Completed++
+		case entity.LibraryStatusDropped:
+			export.MyInfo.Dropped++
+		case entity.LibraryStatusPlanned:
+			export.MyInfo.PlanToRead++
+		}
+
+		tags := make([]string, 0, len(entry.ShelfIDs))
+		for _, shelfID := range entry.ShelfIDs {
+			if name, ok := shelfNames[shelfID]; ok {
+				tags = append(tags, name)
+			}
+		}
+
+		item := malManga{
+			Title:          malCDATA{entry.MangaTitle},
+			Chapters:       entry.TotalChapters,
+			ReadChapters:   entry.ChaptersRead,
+			StartDate:      formatMALDate(entry.StartedAt),
+			FinishDate:     formatMALDate(entry.FinishedAt),
+			Status:         libraryStatusToMAL[entry.Status],
+			Comments:       malCDATA{entry.Notes},
+			Tags:           malCDATA{strings.Join(tags, ", ")},
+			UpdateOnImport: 1,
+		}
+		if entry.Score != nil {
+			item.Score = *entry.Score
+		}
+		if entry.MALID != nil {
+			item.MangaDBID = *entry.MALID
+		}
+
+		export.Manga = append(export.Manga, item)
+	}
+
+	data, err := xml.MarshalIndent(export, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// decodeMALExport разбирает файл экспорта MyAnimeList
+func decodeMALExport(r io.Reader) ([]malManga, error) {
+	var export malExport
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	if export.MyInfo.ExportType != 0 && export.MyInfo.ExportType != malExportTypeManga {
+		return nil, fmt.Errorf("файл содержит список аниме, а не манги")
+	}
+
+	return export.Manga, nil
+}
+
+// toImportItem преобразует запись MyAnimeList в запись библиотеки
+func (m malManga) toImportItem(userID, mangaID int64) (*entity.LibraryImportItem, error) {
+	status, ok := malStatusToLibrary[strings.TrimSpace(m.Status)]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный статус %q", m.Status)
+	}
+
+	item := &entity.LibraryImportItem{
+		Entry: entity.LibraryEntry{
+			UserID:  userID,
+			MangaID: mangaID,
+			Status:  status,
+			Notes:   m.Comments.Value,
+		},
+		Overwrite: m.UpdateOnImport == 1,
+	}
+	if m.MangaDBID > 0 {
+		malID := m.MangaDBID
+		item.Entry.MALID = &malID
+	}
+
+	if utf8.RuneCountInString(item.Entry.Notes) > libraryNotesMaxLen {
+		return nil, fmt.Errorf("заметки длиннее %d символов", libraryNotesMaxLen)
+	}
+
+	// Оценка 0 в MyAnimeList означает отсутствие оценки
+	if m.Score < 0 || m.Score > 10 {
+		return nil, fmt.Errorf("некорректная оценка %d", m.Score)
+	}
+	if m.Score > 0 {
+		score := m.Score
+		item.Entry.Score = &score
+	}
+
+	var err error
+	if item.Entry.StartedAt, err = parseMALDate(m.StartDate); err != nil {
+		return nil, err
+	}
+	if item.Entry.FinishedAt, err = parseMALDate(m.FinishDate); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(m.Tags.Value, ",") {
+		name, err := normalizeShelfName(tag)
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		item.Shelves = append(item.Shelves, name)
+	}
+
+	return item, nil
+}
+
+// parseMALDate разбирает дату MyAnimeList; 0000-00-00 означает отсутствие даты.
+// Неизвестные месяц или день (например, 2020-00-00) заменяются первым числом
+func parseMALDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == malEmptyDate {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("некорректная дата %q", value)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная дата %q", value)
+		}
+		numbers[i] = n
+	}
+	if numbers[0] == 0 {
+		return nil, nil
+	}
+	if numbers[1] == 0 {
+		numbers[1] = 1
+	}
+	if numbers[2] == 0 {
+		numbers[2] = 1
+	}
+
+	date, err := time.Parse(malDateLayout, fmt.Sprintf("%04d-%02d-%02d", numbers[0], numbers[1], numbers[2]))
+	if err != nil {
+		return nil, fmt.Errorf("некорректная дата %q", value)
+	}
+
+	return &date, nil
+}
+
+// formatMALDate форматирует дату для MyAnimeList
+func formatMALDate(date *time.Time) string {
+	if date == nil {
+		return malEmptyDate
+	}
+	return date.Format(malDateLayout)
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"bytes"
+	"manga-reader2/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+func TestParseMALDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *time.Time
+		wantErr bool
+	}{
+		{
+			name:  "пустая строка",
+			value: "",
+		},
+		{
+			name:  "нулевая дата",
+			value: "0000-00-00",
+		},
+		{
+			name:  "неизвестный год",
+			value: "0000-05-10",
+		},
+		{
+			name:  "полная дата",
+			value: "2020-05-10",
+			want:  dateUTC(2020, time.May, 10),
+		},
+		{
+			name:  "пробелы вокруг даты",
+			value: " 2020-05-10 ",
+			want:  dateUTC(2020, time.May, 10),
+		},
+		{
+			name:  "неизвестный день",
+			value: "2020-05-00",
+			want:  dateUTC(2020, time.May, 1),
+		},
+		{
+			name:  "неизвестные месяц и день",
+			value: "2020-00-00",
+			want:  dateUTC(2020, time.January, 1),
+		},
+		{
+			name:    "не число",
+			value:   "2020-ab-10",
+			wantErr: true,
+		},
+		{
+			name:    "не хватает частей",
+			value:   "2020-05",
+			wantErr: true,
+		},
+		{
+			name:    "несуществующий день",
+			value:   "2020-02-30",
+			wantErr: true,
+		},
+		{
+			name:    "месяц вне диапазона",
+			value:   "2020-13-01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMALDate(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ожидалась ошибка, получено %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("дата %v, ожидалось nil", got)
+			case tt.want != nil && (got == nil || !got.Equal(*tt.want)):
+				t.Errorf("дата %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMALExportMangaDBID(t *testing.T) {
+	malID := int64(2)
+	entries := []*entity.LibraryEntryWithManga{
+		{
+			LibraryEntry: entity.LibraryEntry{Status: entity.LibraryStatusReading, MALID: &malID},
+			MangaTitle:   "Berserk",
+		},
+		{
+			LibraryEntry: entity.LibraryEntry{Status: entity.LibraryStatusPlanned},
+			MangaTitle:   "Без ID",
+		},
+	}
+
+	data, err := encodeMALExport(entries, nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка экспорта: %v", err)
+	}
+	if bytes.Count(data, []byte("<manga_mangadb_id>")) != 1 {
+		t.Fatalf("manga_mangadb_id должен выгружаться только для известного ID:\n%s", data)
+	}
+
+	items, err := decodeMALExport(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка разбора: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("разобрано %d записей, ожидалось 2", len(items))
+	}
+
+	item, err := items[0].toImportItem(1, 10)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка импорта: %v", err)
+	}
+	if item.Entry.MALID == nil || *item.Entry.MALID != malID {
+		t.Errorf("ID MyAnimeList %v, ожидался %d", item.Entry.MALID, malID)
+	}
+
+	item, err = items[1].toImportItem(1, 11)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка импорта: %v", err)
+	}
+	if item.Entry.MALID != nil {
+		t.Errorf("ID MyAnimeList %d, ожидалось nil", *item.Entry.MALID)
+	}
+}
+
+func dateUTC(year int, month time.Month, day int) *time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
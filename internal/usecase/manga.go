@@ -8,9 +8,17 @@ import (
 	"manga-reader2/internal/common/logger"
 	"manga-reader2/internal/domain/entity"
 	"manga-reader2/internal/domain/repository"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// similarMangaLimit число похожих манг в разделе связанной манги
+const similarMangaLimit = 12
+
+// volumeFieldMaxLength ограничивает длину названия и адреса обложки тома
+const volumeFieldMaxLength = 255
+
 // MangaUseCase интерфейс, определяющий бизнес-логику для работы с мангой
 type MangaUseCase interface {
 	Create(ctx context.Context, manga *entity.Manga) (*entity.Manga, error)
@@ -18,13 +26,22 @@ type MangaUseCase interface {
 	List(ctx context.Context, filter entity.MangaFilter) ([]*entity.Manga, error)
 	Update(ctx context.Context, manga *entity.Manga) (*entity.Manga, error)
 	Delete(ctx context.Context, id int64) error
-	GetChapters(ctx context.Context, mangaID int64) ([]*entity.Chapter, error)
+	GetChapters(ctx context.Context, mangaID int64, filter entity.ChapterFilter) ([]*entity.ChapterWithStats, error)
+	GetVolumes(ctx context.Context, mangaID int64, filter entity.ChapterFilter) ([]*entity.Volume, error)
+	SetVolume(ctx context.Context, mangaID int64, number int, input entity.VolumeInput) (*entity.VolumeInfo, error)
 	GetPopular(ctx context.Context, period entity.StatsPeriod, limit int) ([]*entity.MangaStat, error)
+	SetRelation(ctx context.Context, userID, mangaID int64, input entity.MangaRelationInput) ([]*entity.MangaRelation, error)
+	RemoveRelation(ctx context.Context, mangaID, relatedID int64) error
 }
 
 // mangaUseCase реализация интерфейса MangaUseCase
 type mangaUseCase struct {
 	mangaRepo     repository.MangaRepository
+	chapterRepo   repository.ChapterRepository
+	pageRepo      repository.PageRepository
+	relationRepo  repository.MangaRelationRepository
+	creatorRepo   repository.CreatorRepository
+	userRepo      repository.UserRepository
 	cacheRepo     repository.CacheRepository
 	analyticsRepo repository.AnalyticsRepository
 	log           logger.Logger
@@ -33,12 +50,22 @@ type mangaUseCase struct {
 // NewMangaUseCase создает новый экземпляр MangaUseCase
 func NewMangaUseCase(
 	mangaRepo repository.MangaRepository,
+	chapterRepo repository.ChapterRepository,
+	pageRepo repository.PageRepository,
+	relationRepo repository.MangaRelationRepository,
+	creatorRepo repository.CreatorRepository,
+	userRepo repository.UserRepository,
 	cacheRepo repository.CacheRepository,
 	analyticsRepo repository.AnalyticsRepository,
 	log logger.Logger,
 ) MangaUseCase {
 	return &mangaUseCase{
 		mangaRepo:     mangaRepo,
+		chapterRepo:   chapterRepo,
+		pageRepo:      pageRepo,
+		relationRepo:  relationRepo,
+		creatorRepo:   creatorRepo,
+		userRepo:      userRepo,
 		cacheRepo:     cacheRepo,
 		analyticsRepo: analyticsRepo,
 		log:           log,
@@ -51,12 +78,29 @@ func (uc *mangaUseCase) Create(ctx context.Context, manga *entity.Manga) (*entit
 		return nil, errors.NewValidationError("Название манги не может быть пустым", nil)
 	}
 
+	if manga.MALID != nil && *manga.MALID <= 0 {
+		return nil, errors.NewValidationError("ID MyAnimeList должен быть положительным", nil)
+	}
+
+	if err := normalizeMangaTranslations(manga); err != nil {
+		return nil, err
+	}
+
+	credits, err := mangaCredits(manga)
+	if err != nil {
+		return nil, err
+	}
+
 	id, err := uc.mangaRepo.Create(ctx, manga)
 	if err != nil {
 		return nil, err
 	}
 
-	createdManga, err := uc.mangaRepo.GetByID(ctx, id)
+	if err = uc.creatorRepo.SetMangaCredits(ctx, id, credits); err != nil {
+		return nil, err
+	}
+
+	createdManga, err := uc.getWithCreators(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +112,7 @@ func (uc *mangaUseCase) Create(ctx context.Context, manga *entity.Manga) (*entit
 	return createdManga, nil
 }
 
-// GetByID получает мангу по ID
+// GetByID получает мангу по ID вместе с разделом связанной и похожей манги
 func (uc *mangaUseCase) GetByID(ctx context.Context, id int64) (*entity.Manga, error) {
 	cacheKey := fmt.Sprintf("manga:%d", id)
 	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
@@ -78,16 +122,21 @@ func (uc *mangaUseCase) GetByID(ctx context.Context, id int64) (*entity.Manga, e
 			if err = uc.analyticsRepo.RecordMangaView(ctx, id); err != nil {
 				uc.log.Error("Ошибка записи просмотра манги", "error", err.Error(), "manga_id", id)
 			}
+			localizeManga(&manga, LanguagesFromContext(ctx))
 			return &manga, nil
 		}
 		uc.log.Error("Ошибка декодирования манги из кеша", "error", err.Error())
 	}
 
-	manga, err := uc.mangaRepo.GetByID(ctx, id)
+	manga, err := uc.getWithCreators(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if manga.Related, err = uc.related(ctx, id); err != nil {
+		return nil, err
+	}
+
 	if err := uc.analyticsRepo.RecordMangaView(ctx, id); err != nil {
 		uc.log.Error("Ошибка записи просмотра манги", "error", err.Error(), "manga_id", id)
 	}
@@ -98,17 +147,31 @@ func (uc *mangaUseCase) GetByID(ctx context.Context, id int64) (*entity.Manga, e
 		}
 	}
 
+	localizeManga(manga, LanguagesFromContext(ctx))
 	return manga, nil
 }
 
-// List возвращает список манги с фильтрацией
+// List возвращает список манги с фильтрацией и сортировкой
 func (uc *mangaUseCase) List(ctx context.Context, filter entity.MangaFilter) ([]*entity.Manga, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.MangaSortUpdated
+	}
+	switch filter.Sort {
+	case entity.MangaSortUpdated, entity.MangaSortScore, entity.MangaSortAverage, entity.MangaSortRatings:
+	default:
+		return nil, errors.NewValidationError("Некорректная сортировка", map[string]interface{}{
+			"sort":    filter.Sort,
+			"allowed": entity.MangaSorts,
+		})
+	}
+
 	if filter.Title == "" && filter.Status == "" && len(filter.Genres) == 0 {
-		cacheKey := fmt.Sprintf("manga:list:%d:%d", filter.Limit, filter.Offset)
+		cacheKey := fmt.Sprintf("manga:list:%s:%d:%d", filter.Sort, filter.Limit, filter.Offset)
 		cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
 		if err == nil && cachedData != "" {
 			var mangas []*entity.Manga
 			if err := json.Unmarshal([]byte(cachedData), &mangas); err == nil {
+				uc.localizeList(ctx, mangas)
 				return mangas, nil
 			}
 			uc.log.Error("Ошибка декодирования списка манги из кеша", "error", err.Error())
@@ -121,7 +184,7 @@ func (uc *mangaUseCase) List(ctx context.Context, filter entity.MangaFilter) ([]
 	}
 
 	if filter.Title == "" && filter.Status == "" && len(filter.Genres) == 0 {
-		cacheKey := fmt.Sprintf("manga:list:%d:%d", filter.Limit, filter.Offset)
+		cacheKey := fmt.Sprintf("manga:list:%s:%d:%d", filter.Sort, filter.Limit, filter.Offset)
 		if jsonData, err := json.Marshal(mangas); err == nil {
 			if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), 10*time.Minute); err != nil {
 				uc.log.Error("Ошибка кеширования списка манги", "error", err.Error())
@@ -129,6 +192,7 @@ func (uc *mangaUseCase) List(ctx context.Context, filter entity.MangaFilter) ([]
 		}
 	}
 
+	uc.localizeList(ctx, mangas)
 	return mangas, nil
 }
 
@@ -143,11 +207,28 @@ func (uc *mangaUseCase) Update(ctx context.Context, manga *entity.Manga) (*entit
 		return nil, errors.NewValidationError("Название манги не может быть пустым", nil)
 	}
 
+	if manga.MALID != nil && *manga.MALID <= 0 {
+		return nil, errors.NewValidationError("ID MyAnimeList должен быть положительным", nil)
+	}
+
+	if err := normalizeMangaTranslations(manga); err != nil {
+		return nil, err
+	}
+
+	credits, err := mangaCredits(manga)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := uc.mangaRepo.Update(ctx, manga); err != nil {
 		return nil, err
 	}
 
-	updatedManga, err := uc.mangaRepo.GetByID(ctx, manga.ID)
+	if err = uc.creatorRepo.SetMangaCredits(ctx, manga.ID, credits); err != nil {
+		return nil, err
+	}
+
+	updatedManga, err := uc.getWithCreators(ctx, manga.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +241,7 @@ func (uc *mangaUseCase) Update(ctx context.Context, manga *entity.Manga) (*entit
 	if err := uc.invalidateMangaListCache(ctx); err != nil {
 		uc.log.Error("Ошибка инвалидации кеша списка манги", "error", err.Error())
 	}
+	invalidateMangaReaderCache(ctx, uc.chapterRepo, uc.cacheRepo, uc.log, manga.ID)
 
 	return updatedManga, nil
 }
@@ -171,6 +253,16 @@ func (uc *mangaUseCase) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
+	// Связи и главы удаляются каскадно, поэтому их нужно найти до удаления
+	relations, err := uc.relationRepo.ListRelations(ctx, id)
+	if err != nil {
+		return err
+	}
+	chapters, err := uc.chapterRepo.ListByManga(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	if err := uc.mangaRepo.Delete(ctx, id); err != nil {
 		return err
 	}
@@ -179,6 +271,12 @@ func (uc *mangaUseCase) Delete(ctx context.Context, id int64) error {
 	if err := uc.cacheRepo.Delete(ctx, cacheKey); err != nil {
 		uc.log.Error("Ошибка инвалидации кеша манги", "error", err.Error())
 	}
+	for _, relation := range relations {
+		uc.invalidateRelatedCache(ctx, relation.RelatedMangaID)
+	}
+	for _, chapter := range chapters {
+		invalidateReaderCache(ctx, uc.cacheRepo, uc.log, chapter.ID)
+	}
 
 	if err := uc.invalidateMangaListCache(ctx); err != nil {
 		uc.log.Error("Ошибка инвалидации кеша списка манги", "error", err.Error())
@@ -187,16 +285,141 @@ func (uc *mangaUseCase) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-// GetChapters возвращает список глав манги
-func (uc *mangaUseCase) GetChapters(ctx context.Context, mangaID int64) ([]*entity.Chapter, error) {
+// GetChapters возвращает список опубликованных глав манги на запрошенных языках с числом страниц и просмотров.
+// Если языки не переданы, используются предпочитаемые языки пользователя; когда на них нет ни одной главы,
+// возвращаются все переводы
+func (uc *mangaUseCase) GetChapters(ctx context.Context, mangaID int64, filter entity.ChapterFilter) ([]*entity.ChapterWithStats, error) {
+	chapters, err := uc.chapters(ctx, mangaID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return chaptersWithStats(ctx, uc.pageRepo, uc.analyticsRepo, uc.log, chapters), nil
+}
+
+// chapters возвращает видимые главы манги, отобранные по языкам, в порядке тома и номера
+func (uc *mangaUseCase) chapters(ctx context.Context, mangaID int64, filter entity.ChapterFilter) ([]*entity.Chapter, error) {
 	_, err := uc.mangaRepo.GetByID(ctx, mangaID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Заглушка
+	chapters, err := cachedChapterList(ctx, uc.chapterRepo, uc.cacheRepo, uc.log, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	chapters = visibleChapters(ctx, uc.mangaRepo, mangaID, chapters)
+
+	if len(filter.Languages) > 0 {
+		return filterChaptersByLanguage(chapters, filter.Languages), nil
+	}
+
+	actor, ok := ActorFromContext(ctx)
+	if filter.AllLanguages || !ok {
+		return chapters, nil
+	}
+
+	preferred, err := uc.userRepo.GetPreferredLanguages(ctx, actor.UserID)
+	if err != nil {
+		uc.log.Error("Ошибка получения языков пользователя", "error", err.Error(), "user_id", actor.UserID)
+		return chapters, nil
+	}
+	if filtered := filterChaptersByLanguage(chapters, preferred); len(filtered) > 0 {
+		return filtered, nil
+	}
+
+	return chapters, nil
+}
+
+// GetVolumes группирует главы манги по томам с их названиями и обложками.
+// Главы без тома собираются в последнюю группу с пустым номером
+func (uc *mangaUseCase) GetVolumes(ctx context.Context, mangaID int64, filter entity.ChapterFilter) ([]*entity.Volume, error) {
+	chapters, err := uc.chapters(ctx, mangaID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := uc.chapterRepo.ListVolumes(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	infoByNumber := make(map[int]*entity.VolumeInfo, len(infos))
+	for _, info := range infos {
+		infoByNumber[info.Number] = info
+	}
+
+	// Главы уже упорядочены по тому и номеру, поэтому группы собираются за один проход
+	volumes := []*entity.Volume{}
+	var current *entity.Volume
+	for _, chapter := range chapters {
+		if current == nil || !sameVolume(current.Number, chapter.Volume) {
+			current = &entity.Volume{Number: chapter.Volume}
+			if chapter.Volume != nil {
+				if info, ok := infoByNumber[*chapter.Volume]; ok {
+					current.Title = info.Title
+					current.CoverImage = info.CoverImage
+				}
+			}
+			volumes = append(volumes, current)
+		}
+		current.Chapters = append(current.Chapters, chapter)
+	}
+
+	return volumes, nil
+}
+
+// SetVolume задает название и обложку тома
+func (uc *mangaUseCase) SetVolume(ctx context.Context, mangaID int64, number int, input entity.VolumeInput) (*entity.VolumeInfo, error) {
+	if number <= 0 {
+		return nil, errors.NewValidationError("Номер тома должен быть положительным", map[string]interface{}{
+			"number": number,
+		})
+	}
+
+	input.Title = strings.Join(strings.Fields(input.Title), " ")
+	input.CoverImage = strings.TrimSpace(input.CoverImage)
+	if utf8.RuneCountInString(input.Title) > volumeFieldMaxLength || len(input.CoverImage) > volumeFieldMaxLength {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("Название и адрес обложки тома должны быть не длиннее %d символов", volumeFieldMaxLength),
+			nil,
+		)
+	}
+
+	if _, err := uc.mangaRepo.GetByID(ctx, mangaID); err != nil {
+		return nil, err
+	}
+
+	volume, err := uc.chapterRepo.SetVolume(ctx, mangaID, number, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Название тома выводится в главах, поэтому сбрасывается кеш списка глав и глав тома
+	keys := []string{fmt.Sprintf("manga:%d:chapters", mangaID)}
+	if chapters, err := uc.chapterRepo.ListByManga(ctx, mangaID); err == nil {
+		for _, chapter := range chapters {
+			if chapter.Volume != nil && *chapter.Volume == number {
+				keys = append(keys, fmt.Sprintf("chapter:%d", chapter.ID), readerCacheKey(chapter.ID))
+			}
+		}
+	} else {
+		uc.log.Error("Ошибка получения глав тома для инвалидации кеша", "error", err.Error(), "manga_id", mangaID)
+	}
+	for _, key := range keys {
+		if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+			uc.log.Error("Ошибка инвалидации кеша", "error", err.Error(), "key", key)
+		}
+	}
+
+	return volume, nil
+}
 
-	return []*entity.Chapter{}, nil
+// sameVolume сравнивает номера томов, учитывая главы без тома
+func sameVolume(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
 }
 
 // GetPopular возвращает список популярной манги
@@ -215,6 +438,9 @@ func (uc *mangaUseCase) GetPopular(ctx context.Context, period entity.StatsPerio
 	if err != nil {
 		return nil, err
 	}
+	if popular, err = fillMangaStats(ctx, uc.mangaRepo, uc.log, popular); err != nil {
+		return nil, err
+	}
 
 	var cacheTTL time.Duration
 	switch period {
@@ -237,6 +463,132 @@ func (uc *mangaUseCase) GetPopular(ctx context.Context, period entity.StatsPerio
 	return popular, nil
 }
 
+// SetRelation создает или изменяет связь манги с другой мангой. Обратная связь
+// (например, предыстория для продолжения) создается автоматически
+func (uc *mangaUseCase) SetRelation(ctx context.Context, userID, mangaID int64, input entity.MangaRelationInput) ([]*entity.MangaRelation, error) {
+	inverse, ok := entity.MangaRelationInverse[input.Type]
+	if !ok {
+		return nil, errors.NewValidationError("Некорректный тип связи", map[string]interface{}{
+			"type":    input.Type,
+			"allowed": entity.MangaRelationTypes,
+		})
+	}
+	if input.RelatedMangaID == mangaID {
+		return nil, errors.NewValidationError("Манга не может быть связана сама с собой", map[string]interface{}{
+			"related_manga_id": input.RelatedMangaID,
+		})
+	}
+
+	for _, id := range []int64{mangaID, input.RelatedMangaID} {
+		if _, err := uc.mangaRepo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.relationRepo.Set(ctx, mangaID, input.RelatedMangaID, input.Type, inverse, userID); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateRelatedCache(ctx, mangaID, input.RelatedMangaID)
+
+	return uc.relationRepo.ListRelations(ctx, mangaID)
+}
+
+// RemoveRelation удаляет связь манги вместе с обратной
+func (uc *mangaUseCase) RemoveRelation(ctx context.Context, mangaID, relatedID int64) error {
+	if err := uc.relationRepo.Delete(ctx, mangaID, relatedID); err != nil {
+		return err
+	}
+
+	uc.invalidateRelatedCache(ctx, mangaID, relatedID)
+	return nil
+}
+
+// getWithCreators получает мангу вместе с авторами
+func (uc *mangaUseCase) getWithCreators(ctx context.Context, id int64) (*entity.Manga, error) {
+	manga, err := uc.mangaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if manga.Creators, err = uc.creatorRepo.ListMangaCredits(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return manga, nil
+}
+
+// mangaCredits возвращает авторов манги из запроса: список creators, а если он не передан —
+// имена из author и artist через запятую
+func mangaCredits(manga *entity.Manga) ([]*entity.MangaCredit, error) {
+	if manga.Creators == nil {
+		var credits []*entity.MangaCredit
+		for role, names := range map[string]string{entity.CreatorRoleAuthor: manga.Author, entity.CreatorRoleArtist: manga.Artist} {
+			for _, name := range strings.Split(names, ",") {
+				if name = strings.Join(strings.Fields(name), " "); name != "" {
+					credits = append(credits, &entity.MangaCredit{Name: name, Role: role})
+				}
+			}
+		}
+		manga.Creators = credits
+	}
+
+	for _, credit := range manga.Creators {
+		switch credit.Role {
+		case entity.CreatorRoleAuthor, entity.CreatorRoleArtist:
+		default:
+			return nil, errors.NewValidationError("Некорректная роль автора", map[string]interface{}{
+				"role":    credit.Role,
+				"allowed": entity.CreatorRoles,
+			})
+		}
+
+		credit.Name = strings.Join(strings.Fields(credit.Name), " ")
+		if credit.CreatorID == 0 && (credit.Name == "" || utf8.RuneCountInString(credit.Name) > creatorNameMaxLength) {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("Для автора нужен ID или имя длиной до %d символов", creatorNameMaxLength),
+				map[string]interface{}{"name": credit.Name},
+			)
+		}
+	}
+
+	return manga.Creators, nil
+}
+
+// related собирает раздел связанной манги: связи, заданные вручную, и похожую мангу
+func (uc *mangaUseCase) related(ctx context.Context, mangaID int64) (*entity.MangaRelated, error) {
+	relations, err := uc.relationRepo.ListRelations(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	similar, err := uc.relationRepo.ListSimilar(ctx, mangaID, similarMangaLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.MangaRelated{Relations: relations, Similar: similar}, nil
+}
+
+// invalidateRelatedCache сбрасывает кеш обеих манг связи
+func (uc *mangaUseCase) invalidateRelatedCache(ctx context.Context, ids ...int64) {
+	for _, id := range ids {
+		key := fmt.Sprintf("manga:%d", id)
+		if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+			uc.log.Error("Ошибка инвалидации кеша манги", "error", err.Error(), "key", key)
+		}
+	}
+}
+
+// localizeList подставляет переводы в мангу списка. Кеш хранит все переводы, поэтому
+// язык выбирается после чтения из кеша
+func (uc *mangaUseCase) localizeList(ctx context.Context, mangas []*entity.Manga) {
+	languages := LanguagesFromContext(ctx)
+	for _, manga := range mangas {
+		localizeManga(manga, languages)
+	}
+}
+
 // invalidateMangaListCache инвалидирует кеш списка манги
 func (uc *mangaUseCase) invalidateMangaListCache(ctx context.Context) error {
 	return uc.cacheRepo.Delete(ctx, "manga:list:*")
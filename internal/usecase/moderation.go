@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+// moderationReasonMaxLength ограничивает длину причины действия модератора в символах
+const moderationReasonMaxLength = 500
+
+// ModerationUseCase интерфейс, определяющий бизнес-логику модерации комментариев
+type ModerationUseCase interface {
+	Queue(ctx context.Context, filter entity.ModerationQueueFilter) ([]*entity.ModerationQueueItem, int, error)
+	Act(ctx context.Context, moderatorID, commentID int64, input *entity.ModerationActionRequest) (*entity.CommentWithUser, error)
+	Log(ctx context.Context, filter entity.ModerationLogFilter) ([]*entity.ModerationLogEntry, int, error)
+}
+
+// moderationUseCase реализация интерфейса ModerationUseCase
+type moderationUseCase struct {
+	moderationRepo repository.ModerationRepository
+	commentRepo    repository.CommentRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	cacheRepo      repository.CacheRepository
+	log            logger.Logger
+}
+
+// NewModerationUseCase создает новый экземпляр ModerationUseCase
+func NewModerationUseCase(
+	moderationRepo repository.ModerationRepository,
+	commentRepo repository.CommentRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) ModerationUseCase {
+	return &moderationUseCase{
+		moderationRepo: moderationRepo,
+		commentRepo:    commentRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		cacheRepo:      cacheRepo,
+		log:            log,
+	}
+}
+
+// Queue возвращает комментарии с открытыми жалобами
+func (uc *moderationUseCase) Queue(ctx context.Context, filter entity.ModerationQueueFilter) ([]*entity.ModerationQueueItem, int, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	items, total, err := uc.moderationRepo.ListQueue(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, item := range items {
+		prepareComment(&item.CommentWithUser, true)
+	}
+
+	return items, total, nil
+}
+
+// Act выполняет действие модератора над комментарием, закрывает жалобы на него
+// и записывает действие в журнал модерации. Блокировка автора также скрывает комментарий
+func (uc *moderationUseCase) Act(ctx context.Context, moderatorID, commentID int64, input *entity.ModerationActionRequest) (*entity.CommentWithUser, error) {
+	input.Reason = strings.TrimSpace(input.Reason)
+	if utf8.RuneCountInString(input.Reason) > moderationReasonMaxLength {
+		return nil, errors.NewValidationError("Причина не может быть длиннее 500 символов", nil)
+	}
+
+	switch input.Action {
+	case entity.ModerationActionWarn, entity.ModerationActionBan:
+		if input.Reason == "" {
+			return nil, errors.NewValidationError("Необходимо указать причину", nil)
+		}
+	case entity.ModerationActionHide, entity.ModerationActionUnhide,
+		entity.ModerationActionDelete, entity.ModerationActionDismiss:
+	default:
+		return nil, errors.NewValidationError("Некорректное действие модератора", map[string]interface{}{
+			"action":  input.Action,
+			"allowed": entity.ModerationActions,
+		})
+	}
+
+	comment, err := uc.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("Комментарий не найден", nil)
+	}
+
+	// Скрытие, удаление и санкции подтверждают жалобы, отмена скрытия и отклонение — снимают их
+	reportStatus := entity.ReportStatusResolved
+	if input.Action == entity.ModerationActionUnhide || input.Action == entity.ModerationActionDismiss {
+		reportStatus = entity.ReportStatusDismissed
+	}
+
+	var ban *entity.UserBan
+	if input.Action == entity.ModerationActionBan {
+		if ban, err = uc.prepareBan(ctx, moderatorID, comment.UserID, input); err != nil {
+			return nil, err
+		}
+	}
+
+	// Действие, закрытие жалоб и запись в журнал выполняются одной транзакцией:
+	// действие не может быть применено без записи в журнал
+	entry := &entity.ModerationLogEntry{
+		ModeratorID:  &moderatorID,
+		Action:       input.Action,
+		CommentID:    &commentID,
+		TargetUserID: &comment.UserID,
+		Reason:       input.Reason,
+	}
+	targetChanged, resolved, err := uc.moderationRepo.Apply(ctx, entry, ban, reportStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if ban != nil {
+		invalidateUserAccess(ctx, uc.cacheRepo, uc.log, comment.UserID)
+	}
+
+	uc.log.Info("Действие модератора", "event", "comment_moderated", "action", input.Action,
+		"comment_id", commentID, "author_id", comment.UserID, "moderator_id", moderatorID, "resolved_reports", resolved)
+
+	if targetChanged {
+		invalidateCommentTargetCache(ctx, uc.cacheRepo, uc.log, &comment.Comment)
+	}
+
+	updated, err := uc.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	prepareComment(updated, true)
+
+	return updated, nil
+}
+
+// prepareBan проверяет блокировку автора комментария модератором. Модератор не может заблокировать себя,
+// других модераторов и администраторов пользователей: для этого нужно разрешение user:manage
+func (uc *moderationUseCase) prepareBan(ctx context.Context, moderatorID, authorID int64, input *entity.ModerationActionRequest) (*entity.UserBan, error) {
+	if authorID == moderatorID {
+		return nil, errors.NewValidationError("Нельзя заблокировать собственную учетную запись", nil)
+	}
+
+	author, err := uc.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := resolvePermissions(ctx, uc.roleRepo, uc.cacheRepo, uc.log, author.Role)
+	if err != nil {
+		return nil, err
+	}
+	target := &entity.Actor{UserID: authorID, Role: author.Role, Permissions: permissions}
+	if target.HasPermission(entity.PermissionUserManage) || target.HasPermission(entity.PermissionCommentModerate) {
+		if actor, ok := ActorFromContext(ctx); !ok || !actor.HasPermission(entity.PermissionUserManage) {
+			return nil, errors.NewForbiddenError("Модератор не может заблокировать другого модератора или администратора", nil)
+		}
+	}
+
+	ban := &entity.UserBan{Reason: input.Reason, Until: input.BanUntil}
+	if err = normalizeUserBan(ban); err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+// Log возвращает журнал действий модераторов
+func (uc *moderationUseCase) Log(ctx context.Context, filter entity.ModerationLogFilter) ([]*entity.ModerationLogEntry, int, error) {
+	switch filter.Action {
+	case "", entity.ModerationActionHide, entity.ModerationActionUnhide, entity.ModerationActionDelete,
+		entity.ModerationActionWarn, entity.ModerationActionBan, entity.ModerationActionDismiss,
+		entity.ModerationActionAutoHide:
+	default:
+		return nil, 0, errors.NewValidationError("Некорректное действие модератора", map[string]interface{}{
+			"action":  filter.Action,
+			"allowed": entity.ModerationLogActions,
+		})
+	}
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.moderationRepo.ListLog(ctx, filter)
+}
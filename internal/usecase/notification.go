@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// Параметры фоновой рассылки уведомлений
+const (
+	notificationBatchSize    = 1000
+	notificationPollInterval = 30 * time.Second
+	notificationBatchTimeout = 30 * time.Second
+)
+
+// NotificationUseCase интерфейс, определяющий бизнес-логику уведомлений
+type NotificationUseCase interface {
+	NotifyNewChapter(ctx context.Context, chapter *entity.Chapter) error
+	List(ctx context.Context, userID int64, filter entity.NotificationFilter) ([]*entity.NotificationItem, int, error)
+	UnreadCount(ctx context.Context, userID int64) (*entity.NotificationUnreadCount, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) (*entity.NotificationMarkResult, error)
+	Run(ctx context.Context)
+}
+
+// notificationUseCase реализация интерфейса NotificationUseCase
+type notificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+	streamUseCase    StreamUseCase
+	wake             chan struct{}
+	log              logger.Logger
+}
+
+// NewNotificationUseCase создает новый экземпляр NotificationUseCase.
+// Рассылка подписчикам выполняется в фоне, для этого нужно запустить Run
+func NewNotificationUseCase(
+	notificationRepo repository.NotificationRepository,
+	streamUseCase StreamUseCase,
+	log logger.Logger,
+) NotificationUseCase {
+	return &notificationUseCase{
+		notificationRepo: notificationRepo,
+		streamUseCase:    streamUseCase,
+		wake:             make(chan struct{}, 1),
+		log:              log,
+	}
+}
+
+// NotifyNewChapter ставит в очередь рассылку уведомлений о новой главе подписчикам манги.
+// Задание сохраняется в БД, поэтому не теряется при перезапуске; сама рассылка идет в Run.
+// Событие о выходе главы сразу уходит в поток реального времени
+func (uc *notificationUseCase) NotifyNewChapter(ctx context.Context, chapter *entity.Chapter) error {
+	chapterID := chapter.ID
+	job := &entity.NotificationJob{
+		Type:      entity.NotificationTypeNewChapter,
+		MangaID:   chapter.MangaID,
+		ChapterID: &chapterID,
+	}
+	if err := uc.notificationRepo.CreateJob(ctx, job); err != nil {
+		return err
+	}
+
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+
+	if err := uc.streamUseCase.PublishChapterReleased(ctx, chapter); err != nil {
+		uc.log.Error("Ошибка публикации события о выходе главы", "error", err.Error(), "chapter_id", chapter.ID)
+	}
+
+	return nil
+}
+
+// Run выполняет задания рассылки пакетами до отмены контекста.
+// Задания проверяются по сигналу NotifyNewChapter и периодически — для оставшихся после перезапуска
+func (uc *notificationUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		uc.processJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-uc.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// processJobs обрабатывает пакеты, пока есть незавершенные задания.
+// Получатели каждого пакета сразу оповещаются через поток реального времени
+func (uc *notificationUseCase) processJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		batchCtx, cancel := context.WithTimeout(ctx, notificationBatchTimeout)
+		job, userIDs, err := uc.notificationRepo.ProcessJobBatch(batchCtx, notificationBatchSize)
+		if err == nil && job != nil {
+			uc.publishBatch(batchCtx, job, userIDs)
+		}
+		cancel()
+
+		if err != nil {
+			uc.log.Error("Ошибка рассылки уведомлений", "error", err.Error())
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		if job.CompletedAt != nil {
+			uc.log.Info("Рассылка уведомлений завершена", "event", "notification_job_completed",
+				"job_id", job.ID, "type", job.Type, "manga_id", job.MangaID, "notified", job.Notified)
+		}
+	}
+}
+
+// publishBatch отправляет получателям пакета событие о новом уведомлении.
+// Ошибка публикации не прерывает рассылку: уведомления уже сохранены и доступны через API
+func (uc *notificationUseCase) publishBatch(ctx context.Context, job *entity.NotificationJob, userIDs []int64) {
+	err := uc.streamUseCase.PublishNotification(ctx, userIDs, &entity.StreamNotificationData{
+		Type:      job.Type,
+		MangaID:   job.MangaID,
+		ChapterID: job.ChapterID,
+	})
+	if err != nil {
+		uc.log.Error("Ошибка публикации события об уведомлениях", "error", err.Error(), "job_id", job.ID)
+	}
+}
+
+// List возвращает уведомления пользователя
+func (uc *notificationUseCase) List(ctx context.Context, userID int64, filter entity.NotificationFilter) ([]*entity.NotificationItem, int, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.notificationRepo.List(ctx, userID, filter)
+}
+
+// UnreadCount возвращает количество непрочитанных уведомлений
+func (uc *notificationUseCase) UnreadCount(ctx context.Context, userID int64) (*entity.NotificationUnreadCount, error) {
+	count, err := uc.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.NotificationUnreadCount{Unread: count}, nil
+}
+
+// MarkRead отмечает уведомление прочитанным
+func (uc *notificationUseCase) MarkRead(ctx context.Context, userID, id int64) error {
+	return uc.notificationRepo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя
+func (uc *notificationUseCase) MarkAllRead(ctx context.Context, userID int64) (*entity.NotificationMarkResult, error) {
+	updated, err := uc.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.NotificationMarkResult{Updated: updated}, nil
+}
@@ -0,0 +1,341 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"manga-reader2/internal/infrastructure/auth"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// oidcStateTTL время, в течение которого пользователь должен вернуться от провайдера
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCUseCase интерфейс, определяющий бизнес-логику входа через внешних OpenID Connect провайдеров
+type OIDCUseCase interface {
+	BeginLogin(ctx context.Context, provider string) (*entity.OIDCAuthorization, error)
+	BeginLink(ctx context.Context, provider string, userID int64) (*entity.OIDCAuthorization, error)
+	CompleteLogin(ctx context.Context, provider, state, code string) (*entity.TokenPair, error)
+}
+
+// oidcUseCase реализация интерфейса OIDCUseCase
+type oidcUseCase struct {
+	providers    map[string]*auth.OIDCProvider
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	roleRepo     repository.RoleRepository
+	cacheRepo    repository.CacheRepository
+	jwtService   *auth.JWTService
+	log          logger.Logger
+}
+
+// oidcLoginState хранит параметры незавершенного входа между редиректами
+type oidcLoginState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	LinkUserID   int64  `json:"link_user_id,omitempty"` // пользователь, начавший привязку из своей сессии
+}
+
+// NewOIDCUseCase создает новый экземпляр OIDCUseCase
+func NewOIDCUseCase(
+	providers []*auth.OIDCProvider,
+	userRepo repository.UserRepository,
+	identityRepo repository.UserIdentityRepository,
+	roleRepo repository.RoleRepository,
+	cacheRepo repository.CacheRepository,
+	jwtService *auth.JWTService,
+	log logger.Logger,
+) OIDCUseCase {
+	byName := make(map[string]*auth.OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &oidcUseCase{
+		providers:    byName,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		roleRepo:     roleRepo,
+		cacheRepo:    cacheRepo,
+		jwtService:   jwtService,
+		log:          log,
+	}
+}
+
+// BeginLogin готовит параметры PKCE и возвращает адрес страницы авторизации провайдера
+func (uc *oidcUseCase) BeginLogin(ctx context.Context, providerName string) (*entity.OIDCAuthorization, error) {
+	return uc.beginAuthorization(ctx, providerName, 0)
+}
+
+// BeginLink начинает привязку внешней учетной записи к аутентифицированному пользователю
+func (uc *oidcUseCase) BeginLink(ctx context.Context, providerName string, userID int64) (*entity.OIDCAuthorization, error) {
+	return uc.beginAuthorization(ctx, providerName, userID)
+}
+
+// beginAuthorization сохраняет состояние входа или привязки и возвращает адрес страницы авторизации провайдера
+func (uc *oidcUseCase) beginAuthorization(ctx context.Context, providerName string, linkUserID int64) (*entity.OIDCAuthorization, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("Провайдер %s не настроен", providerName), nil)
+	}
+
+	state, err := auth.GenerateRandomString(32)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка генерации state", err)
+	}
+	nonce, err := auth.GenerateRandomString(32)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка генерации nonce", err)
+	}
+	codeVerifier, err := auth.GenerateRandomString(48)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка генерации code_verifier", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		uc.log.Error("Ошибка подготовки входа через провайдера", "error", err.Error(), "provider", providerName)
+		return nil, errors.NewInternalError("Провайдер авторизации недоступен", err)
+	}
+
+	jsonData, err := json.Marshal(oidcLoginState{
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка сохранения состояния входа", err)
+	}
+
+	if err = uc.cacheRepo.Set(ctx, oidcStateKey(state), string(jsonData), oidcStateTTL); err != nil {
+		return nil, errors.NewInternalError("Ошибка сохранения состояния входа", err)
+	}
+
+	return &entity.OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            state,
+	}, nil
+}
+
+// CompleteLogin завершает вход: обменивает код, находит или создает пользователя и выдает токены
+func (uc *oidcUseCase) CompleteLogin(ctx context.Context, providerName, state, code string) (*entity.TokenPair, error) {
+	if state == "" || code == "" {
+		return nil, errors.NewValidationError("Не указаны state или code", nil)
+	}
+
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("Провайдер %s не настроен", providerName), nil)
+	}
+
+	cachedState, err := uc.cacheRepo.Get(ctx, oidcStateKey(state))
+	if err != nil || cachedState == "" {
+		return nil, errors.NewUnauthorizedError("Состояние входа не найдено или истекло", nil)
+	}
+
+	// state одноразовый, повторное использование запрещено
+	if err = uc.cacheRepo.Delete(ctx, oidcStateKey(state)); err != nil {
+		uc.log.Error("Ошибка удаления состояния входа", "error", err.Error())
+	}
+
+	var loginState oidcLoginState
+	if err = json.Unmarshal([]byte(cachedState), &loginState); err != nil || loginState.Provider != providerName {
+		return nil, errors.NewUnauthorizedError("Некорректное состояние входа", err)
+	}
+
+	claims, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		uc.log.Warn("Ошибка входа через провайдера", "event", "oidc_login_failed", "provider", providerName, "error", err.Error())
+		return nil, errors.NewUnauthorizedError("Не удалось подтвердить вход через провайдера", err)
+	}
+
+	var user *entity.User
+	if loginState.LinkUserID != 0 {
+		user, err = uc.linkIdentity(ctx, providerName, claims, loginState.LinkUserID)
+	} else {
+		user, err = uc.resolveUser(ctx, providerName, claims)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkUserAccess(user); err != nil {
+		uc.log.Warn("Попытка входа заблокированного пользователя", "event", "login_banned", "provider", providerName, "user_id", user.ID)
+		return nil, err
+	}
+
+	if user.Permissions, err = resolvePermissions(ctx, uc.roleRepo, uc.cacheRepo, uc.log, user.Role); err != nil {
+		return nil, err
+	}
+
+	tokenPair, err := uc.jwtService.GenerateTokenPair(user)
+	if err != nil {
+		uc.log.Error("Ошибка генерации токена", "error", err.Error(), "user_id", user.ID)
+		return nil, errors.NewInternalError("Ошибка генерации токена", err)
+	}
+
+	uc.log.Info("Успешный вход через провайдера", "event", "oidc_login_success", "provider", providerName, "user_id", user.ID)
+
+	return tokenPair, nil
+}
+
+// resolveUser находит пользователя по привязанной учетной записи,
+// привязывает ее к существующему пользователю с подтвержденным email или создает нового.
+// Локальный email без подтверждения не доказывает владение адресом, поэтому такую учетную запись
+// можно привязать только явно, из сессии пользователя (BeginLink)
+func (uc *oidcUseCase) resolveUser(ctx context.Context, providerName string, claims *auth.OIDCClaims) (*entity.User, error) {
+	identity, err := uc.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		return uc.userRepo.GetByID(ctx, identity.UserID)
+	}
+	if !errors.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	var user *entity.User
+	if claims.Email != "" && claims.EmailVerified {
+		user, err = uc.userRepo.GetByEmail(ctx, claims.Email)
+		if err != nil && !errors.IsNotFoundError(err) {
+			return nil, err
+		}
+		if user != nil && !user.EmailVerified {
+			uc.log.Warn("Автоматическая привязка к неподтвержденному email отклонена", "event", "oidc_link_rejected", "provider", providerName, "user_id", user.ID)
+			return nil, errors.NewConflictError("Пользователь с таким email уже существует. Войдите в учетную запись и привяжите провайдера в профиле", nil)
+		}
+	}
+
+	if user == nil {
+		user, err = uc.createUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = uc.createIdentity(ctx, providerName, claims, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// linkIdentity привязывает внешнюю учетную запись к пользователю, начавшему привязку из своей сессии
+func (uc *oidcUseCase) linkIdentity(ctx context.Context, providerName string, claims *auth.OIDCClaims, userID int64) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := uc.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		if identity.UserID != user.ID {
+			return nil, errors.NewConflictError("Учетная запись провайдера уже привязана к другому пользователю", nil)
+		}
+		return user, nil
+	}
+	if !errors.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	if err = uc.createIdentity(ctx, providerName, claims, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createIdentity сохраняет привязку внешней учетной записи к пользователю
+func (uc *oidcUseCase) createIdentity(ctx context.Context, providerName string, claims *auth.OIDCClaims, userID int64) error {
+	identity := &entity.UserIdentity{
+		UserID:   userID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if _, err := uc.identityRepo.Create(ctx, identity); err != nil {
+		return err
+	}
+
+	uc.log.Info("Внешняя учетная запись привязана", "event", "oidc_identity_linked", "provider", providerName, "user_id", userID)
+
+	return nil
+}
+
+// createUser создает нового пользователя по данным ID токена
+func (uc *oidcUseCase) createUser(ctx context.Context, claims *auth.OIDCClaims) (*entity.User, error) {
+	email := claims.Email
+	if email == "" || !claims.EmailVerified {
+		// email обязателен и уникален, поэтому без подтвержденного адреса используем технический
+		email = fmt.Sprintf("%s@users.noreply.local", strings.ToLower(sanitizeUsername(claims.Subject)))
+	}
+
+	base := claims.PreferredUsername
+	if base == "" && claims.Email != "" {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	base = sanitizeUsername(base)
+	if len(base) < 3 {
+		base = "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	// Пароль недоступен пользователю, вход возможен только через провайдера
+	randomPassword, err := auth.GenerateRandomString(32)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка генерации пароля", err)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.NewInternalError("Ошибка хеширования пароля", err)
+	}
+
+	username := base
+	for attempt := 0; attempt < 5; attempt++ {
+		if _, err = uc.userRepo.GetByUsername(ctx, username); err != nil {
+			if !errors.IsNotFoundError(err) {
+				return nil, err
+			}
+
+			user := &entity.User{
+				Username: username,
+				Email:    email,
+				// адрес подтвержден провайдером, к нему можно привязывать последующие входы
+				EmailVerified: email == claims.Email && claims.EmailVerified,
+				Password:      string(hashedPassword),
+				Role:          entity.RoleReader,
+			}
+			if _, err = uc.userRepo.Create(ctx, user); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+
+		suffix, err := auth.GenerateRandomString(4)
+		if err != nil {
+			return nil, errors.NewInternalError("Ошибка генерации имени пользователя", err)
+		}
+		username = fmt.Sprintf("%s_%s", base, sanitizeUsername(suffix))
+	}
+
+	return nil, errors.NewConflictError("Не удалось подобрать свободное имя пользователя", nil)
+}
+
+// sanitizeUsername оставляет в строке только допустимые для имени пользователя символы
+func sanitizeUsername(value string) string {
+	return regexp.MustCompile(`[^a-zA-Z0-9_]`).ReplaceAllString(value, "")
+}
+
+// oidcStateKey возвращает ключ кеша для состояния входа
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("oidc:state:%s", state)
+}
@@ -8,6 +8,7 @@ import (
 	"manga-reader2/internal/common/logger"
 	"manga-reader2/internal/domain/entity"
 	"manga-reader2/internal/domain/repository"
+	"manga-reader2/internal/infrastructure/auth"
 	"os"
 	"path/filepath"
 	"time"
@@ -17,35 +18,48 @@ import (
 type PageUseCase interface {
 	Create(ctx context.Context, page *entity.Page) (*entity.Page, error)
 	GetByID(ctx context.Context, id int64) (*entity.Page, error)
+	GetImage(ctx context.Context, id int64, signed *entity.PageImageSignature) (*entity.Page, error)
 	ListByChapter(ctx context.Context, chapterID int64) ([]*entity.Page, error)
 	Update(ctx context.Context, page *entity.Page) (*entity.Page, error)
 	Delete(ctx context.Context, id int64) error
 	UploadImage(ctx context.Context, chapterID int64, number int, filename string, imageData []byte) (*entity.Page, error)
+	Reorder(ctx context.Context, chapterID int64, pageIDs []int64) ([]*entity.Page, error)
+	Insert(ctx context.Context, chapterID int64, position int, image *entity.PageImage) (*entity.Page, error)
+	ReplaceAll(ctx context.Context, chapterID int64, images []*entity.PageImage) ([]*entity.Page, error)
 }
 
 // pageUseCase реализация интерфейса PageUseCase
 type pageUseCase struct {
-	pageRepo      repository.PageRepository
-	chapterRepo   repository.ChapterRepository
-	cacheRepo     repository.CacheRepository
-	analyticsRepo repository.AnalyticsRepository
-	log           logger.Logger
+	pageRepo       repository.PageRepository
+	chapterRepo    repository.ChapterRepository
+	mangaRepo      repository.MangaRepository
+	cacheRepo      repository.CacheRepository
+	analyticsRepo  repository.AnalyticsRepository
+	historyUseCase ReadingHistoryUseCase
+	jwtService     *auth.JWTService
+	log            logger.Logger
 }
 
 // NewPageUseCase создает новый экземпляр PageUseCase
 func NewPageUseCase(
 	pageRepo repository.PageRepository,
 	chapterRepo repository.ChapterRepository,
+	mangaRepo repository.MangaRepository,
 	cacheRepo repository.CacheRepository,
 	analyticsRepo repository.AnalyticsRepository,
+	historyUseCase ReadingHistoryUseCase,
+	jwtService *auth.JWTService,
 	log logger.Logger,
 ) PageUseCase {
 	return &pageUseCase{
-		pageRepo:      pageRepo,
-		chapterRepo:   chapterRepo,
-		cacheRepo:     cacheRepo,
-		analyticsRepo: analyticsRepo,
-		log:           log,
+		pageRepo:       pageRepo,
+		chapterRepo:    chapterRepo,
+		mangaRepo:      mangaRepo,
+		cacheRepo:      cacheRepo,
+		analyticsRepo:  analyticsRepo,
+		historyUseCase: historyUseCase,
+		jwtService:     jwtService,
+		log:            log,
 	}
 }
 
@@ -59,11 +73,15 @@ func (uc *pageUseCase) Create(ctx context.Context, page *entity.Page) (*entity.P
 		return nil, errors.NewValidationError("Не указан путь к изображению", nil)
 	}
 
-	_, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
+	chapter, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID); err != nil {
+		return nil, err
+	}
+
 	id, err := uc.pageRepo.Create(ctx, page)
 	if err != nil {
 		return nil, err
@@ -81,21 +99,63 @@ func (uc *pageUseCase) Create(ctx context.Context, page *entity.Page) (*entity.P
 	return createdPage, nil
 }
 
-// GetByID получает страницу по ID
+// GetByID возвращает страницу по ID и записывает ее просмотр и чтение главы
 func (uc *pageUseCase) GetByID(ctx context.Context, id int64) (*entity.Page, error) {
+	page, chapter, err := uc.getReadable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.analyticsRepo.RecordPageView(ctx, id, page.ChapterID, chapter.MangaID); err != nil {
+		uc.log.Error("Ошибка записи просмотра страницы", "error", err.Error(), "page_id", id)
+	}
+	uc.recordRead(ctx, chapter, page)
+
+	return page, nil
+}
+
+// GetImage возвращает страницу для отдачи изображения. Только проверяет доступ к главе:
+// браузер запрашивает изображения повторно и заранее, поэтому просмотры и история здесь не пишутся.
+// Действующая подпись ссылки из читалки заменяет проверку доступа
+func (uc *pageUseCase) GetImage(ctx context.Context, id int64, signed *entity.PageImageSignature) (*entity.Page, error) {
+	if signed == nil {
+		page, _, err := uc.getReadable(ctx, id)
+		return page, err
+	}
+
+	if !uc.jwtService.VerifyPageImage(id, signed.ExpiresAt, signed.Signature) {
+		return nil, errors.NewForbiddenError("Ссылка на изображение недействительна или устарела", nil)
+	}
+
+	return uc.getCached(ctx, id)
+}
+
+// getReadable возвращает страницу и ее главу, если глава доступна пользователю из контекста
+func (uc *pageUseCase) getReadable(ctx context.Context, id int64) (*entity.Page, *entity.Chapter, error) {
+	page, err := uc.getCached(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Глава нужна для проверки доступа, поэтому без нее страница не отдается
+	chapter, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
+		return nil, nil, err
+	}
+
+	return page, chapter, nil
+}
+
+// getCached возвращает страницу из кеша или из БД с последующим кешированием
+func (uc *pageUseCase) getCached(ctx context.Context, id int64) (*entity.Page, error) {
 	cacheKey := fmt.Sprintf("page:%d", id)
 	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
 	if err == nil && cachedData != "" {
 		var page entity.Page
 		if err := json.Unmarshal([]byte(cachedData), &page); err == nil {
-			chapter, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
-			if err == nil {
-				if err := uc.analyticsRepo.RecordPageView(ctx, id, page.ChapterID, chapter.MangaID); err != nil {
-					uc.log.Error("Ошибка записи просмотра страницы", "error", err.Error(), "page_id", id)
-				}
-			} else {
-				uc.log.Error("Ошибка получения главы для аналитики", "error", err.Error(), "chapter_id", page.ChapterID)
-			}
 			return &page, nil
 		}
 		uc.log.Error("Ошибка декодирования страницы из кеша", "error", err.Error())
@@ -106,15 +166,6 @@ func (uc *pageUseCase) GetByID(ctx context.Context, id int64) (*entity.Page, err
 		return nil, err
 	}
 
-	chapter, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
-	if err == nil {
-		if err := uc.analyticsRepo.RecordPageView(ctx, id, page.ChapterID, chapter.MangaID); err != nil {
-			uc.log.Error("Ошибка записи просмотра страницы", "error", err.Error(), "page_id", id)
-		}
-	} else {
-		uc.log.Error("Ошибка получения главы для аналитики", "error", err.Error(), "chapter_id", page.ChapterID)
-	}
-
 	if jsonData, err := json.Marshal(page); err == nil {
 		if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), 30*time.Minute); err != nil {
 			uc.log.Error("Ошибка кеширования страницы", "error", err.Error())
@@ -126,33 +177,16 @@ func (uc *pageUseCase) GetByID(ctx context.Context, id int64) (*entity.Page, err
 
 // ListByChapter возвращает список страниц для главы
 func (uc *pageUseCase) ListByChapter(ctx context.Context, chapterID int64) ([]*entity.Page, error) {
-	_, err := uc.chapterRepo.GetByID(ctx, chapterID)
+	chapter, err := uc.chapterRepo.GetByID(ctx, chapterID)
 	if err != nil {
 		return nil, err
 	}
 
-	cacheKey := fmt.Sprintf("chapter:%d:pages", chapterID)
-	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
-	if err == nil && cachedData != "" {
-		var pages []*entity.Page
-		if err := json.Unmarshal([]byte(cachedData), &pages); err == nil {
-			return pages, nil
-		}
-		uc.log.Error("Ошибка декодирования списка страниц из кеша", "error", err.Error())
-	}
-
-	pages, err := uc.pageRepo.ListByChapter(ctx, chapterID)
-	if err != nil {
+	if err = ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
 		return nil, err
 	}
 
-	if jsonData, err := json.Marshal(pages); err == nil {
-		if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), 15*time.Minute); err != nil {
-			uc.log.Error("Ошибка кеширования списка страниц", "error", err.Error())
-		}
-	}
-
-	return pages, nil
+	return cachedPageList(ctx, uc.pageRepo, uc.cacheRepo, uc.log, chapterID)
 }
 
 // Update обновляет страницу
@@ -170,11 +204,26 @@ func (uc *pageUseCase) Update(ctx context.Context, page *entity.Page) (*entity.P
 		return nil, errors.NewValidationError("Не указан путь к изображению", nil)
 	}
 
-	_, err = uc.chapterRepo.GetByID(ctx, page.ChapterID)
+	existingChapter, err := uc.chapterRepo.GetByID(ctx, existingPage.ChapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, existingChapter.MangaID); err != nil {
+		return nil, err
+	}
+
+	chapter, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
 	if err != nil {
 		return nil, err
 	}
 
+	if chapter.MangaID != existingChapter.MangaID {
+		if err = ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := uc.pageRepo.Update(ctx, page); err != nil {
 		return nil, err
 	}
@@ -208,10 +257,17 @@ func (uc *pageUseCase) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
-	if err := os.Remove(page.ImagePath); err != nil && !os.IsNotExist(err) {
-		uc.log.Error("Ошибка удаления файла изображения", "error", err.Error(), "path", page.ImagePath)
+	chapter, err := uc.chapterRepo.GetByID(ctx, page.ChapterID)
+	if err != nil {
+		return err
+	}
+
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID); err != nil {
+		return err
 	}
 
+	uc.removePageImages(page.ImagePath)
+
 	if err := uc.pageRepo.Delete(ctx, id); err != nil {
 		return err
 	}
@@ -230,40 +286,117 @@ func (uc *pageUseCase) Delete(ctx context.Context, id int64) error {
 
 // UploadImage загружает изображение и создает новую страницу
 func (uc *pageUseCase) UploadImage(ctx context.Context, chapterID int64, number int, filename string, imageData []byte) (*entity.Page, error) {
-	_, err := uc.chapterRepo.GetByID(ctx, chapterID)
+	chapter, err := uc.chapterRepo.GetByID(ctx, chapterID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID); err != nil {
+		return nil, err
+	}
+
+	imagePath, err := uc.savePageImage(chapterID, number, filename, imageData)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.Page{
+		ChapterID: chapterID,
+		Number:    number,
+		ImagePath: imagePath,
+	}
+
+	createdPage, err := uc.Create(ctx, page)
+	if err != nil {
+		uc.removePageImages(imagePath)
+		return nil, err
+	}
+
+	return createdPage, nil
+}
+
+// savePageImage сохраняет изображение страницы в каталог главы. Имя файла уникально,
+// поэтому после перенумерации страниц новые загрузки не перезаписывают прежние изображения
+func (uc *pageUseCase) savePageImage(chapterID int64, number int, filename string, imageData []byte) (string, error) {
 	uploadDir := fmt.Sprintf("uploads/chapters/%d", chapterID)
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		uc.log.Error("Ошибка создания директории для загрузки", "error", err.Error(), "dir", uploadDir)
-		return nil, errors.NewInternalError("Ошибка создания директории для загрузки", err)
+		return "", errors.NewInternalError("Ошибка создания директории для загрузки", err)
 	}
 
 	ext := filepath.Ext(filename)
 	if ext == "" {
 		ext = ".jpg"
 	}
-	newFilename := fmt.Sprintf("%d_%d%s", chapterID, number, ext)
+	newFilename := fmt.Sprintf("%d_%d_%d%s", chapterID, number, time.Now().UnixNano(), ext)
 	imagePath := filepath.Join(uploadDir, newFilename)
 
 	if err := os.WriteFile(imagePath, imageData, 0644); err != nil {
 		uc.log.Error("Ошибка записи файла", "error", err.Error(), "path", imagePath)
-		return nil, errors.NewInternalError("Ошибка записи файла", err)
+		return "", errors.NewInternalError("Ошибка записи файла", err)
 	}
 
-	page := &entity.Page{
-		ChapterID: chapterID,
-		Number:    number,
-		ImagePath: imagePath,
+	return imagePath, nil
+}
+
+// removePageImages удаляет файлы изображений; отсутствующие файлы пропускаются
+func (uc *pageUseCase) removePageImages(paths ...string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			uc.log.Error("Ошибка удаления файла изображения", "error", err.Error(), "path", path)
+		}
 	}
+}
 
-	return uc.Create(ctx, page)
+// cachedPageList возвращает страницы главы по порядку номеров из кеша или базы
+func cachedPageList(ctx context.Context, pageRepo repository.PageRepository, cacheRepo repository.CacheRepository, log logger.Logger, chapterID int64) ([]*entity.Page, error) {
+	cacheKey := fmt.Sprintf("chapter:%d:pages", chapterID)
+	cachedData, err := cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var pages []*entity.Page
+		if err := json.Unmarshal([]byte(cachedData), &pages); err == nil {
+			return pages, nil
+		}
+		log.Error("Ошибка декодирования списка страниц из кеша", "error", err.Error())
+	}
+
+	pages, err := pageRepo.ListByChapter(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if pages == nil {
+		pages = []*entity.Page{}
+	}
+
+	if jsonData, err := json.Marshal(pages); err == nil {
+		if err := cacheRepo.Set(ctx, cacheKey, string(jsonData), 15*time.Minute); err != nil {
+			log.Error("Ошибка кеширования списка страниц", "error", err.Error())
+		}
+	}
+
+	return pages, nil
 }
 
-// invalidatePageListCache инвалидирует кеш списка страниц для главы
+// invalidatePageListCache инвалидирует кеш списка страниц и кеш читалки для главы
 func (uc *pageUseCase) invalidatePageListCache(ctx context.Context, chapterID int64) error {
+	invalidateReaderCache(ctx, uc.cacheRepo, uc.log, chapterID)
+
 	cacheKey := fmt.Sprintf("chapter:%d:pages", chapterID)
 	return uc.cacheRepo.Delete(ctx, cacheKey)
 }
+
+// recordRead записывает открытие страницы в историю чтения аутентифицированного пользователя
+func (uc *pageUseCase) recordRead(ctx context.Context, chapter *entity.Chapter, page *entity.Page) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	pageNumber := page.Number
+	uc.historyUseCase.RecordRead(ctx, &entity.ReadingHistoryEntry{
+		UserID:     actor.UserID,
+		MangaID:    chapter.MangaID,
+		ChapterID:  chapter.ID,
+		PageNumber: &pageNumber,
+	})
+}
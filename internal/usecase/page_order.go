@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/domain/entity"
+)
+
+// chapterMaxPages максимальное число страниц в главе при замене всех страниц
+const chapterMaxPages = 1000
+
+// Reorder перенумеровывает страницы главы в порядке pageIDs одной транзакцией
+// и возвращает страницы в новом порядке
+func (uc *pageUseCase) Reorder(ctx context.Context, chapterID int64, pageIDs []int64) ([]*entity.Page, error) {
+	if len(pageIDs) == 0 {
+		return nil, errors.NewValidationError("Не указан порядок страниц", nil)
+	}
+
+	if err := uc.ensureChapterAccess(ctx, chapterID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.pageRepo.Reorder(ctx, chapterID, pageIDs); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateChapterPages(ctx, chapterID, pageIDs)
+
+	uc.log.Info("Порядок страниц изменен", "event", "pages_reordered", "chapter_id", chapterID, "pages", len(pageIDs))
+
+	return uc.pageRepo.ListByChapter(ctx, chapterID)
+}
+
+// Insert загружает изображение и вставляет страницу на позицию position, сдвигая последующие страницы
+func (uc *pageUseCase) Insert(ctx context.Context, chapterID int64, position int, image *entity.PageImage) (*entity.Page, error) {
+	if position < 1 {
+		return nil, errors.NewValidationError("Некорректная позиция страницы", map[string]interface{}{
+			"position": position,
+		})
+	}
+
+	if err := uc.ensureChapterAccess(ctx, chapterID); err != nil {
+		return nil, err
+	}
+
+	// Номера сдвинутых страниц меняются, поэтому их кеш сбрасывается вместе со списком
+	shifted, err := uc.pageRepo.ListByChapter(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePath, err := uc.savePageImage(chapterID, position, image.Filename, image.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.Page{
+		ChapterID: chapterID,
+		Number:    position,
+		ImagePath: imagePath,
+	}
+	if err := uc.pageRepo.Insert(ctx, page); err != nil {
+		uc.removePageImages(imagePath)
+		return nil, err
+	}
+
+	uc.invalidateChapterPages(ctx, chapterID, pageIDs(shifted))
+
+	uc.log.Info("Страница вставлена", "event", "page_inserted", "chapter_id", chapterID, "page_id", page.ID, "position", position)
+
+	return page, nil
+}
+
+// ReplaceAll загружает изображения и заменяет ими все страницы главы одной транзакцией.
+// Изображения прежних страниц удаляются после фиксации замены
+func (uc *pageUseCase) ReplaceAll(ctx context.Context, chapterID int64, images []*entity.PageImage) ([]*entity.Page, error) {
+	if len(images) == 0 {
+		return nil, errors.NewValidationError("Не переданы изображения страниц", nil)
+	}
+	if len(images) > chapterMaxPages {
+		return nil, errors.NewValidationError("Слишком много страниц в главе", map[string]interface{}{
+			"max_pages": chapterMaxPages,
+		})
+	}
+
+	if err := uc.ensureChapterAccess(ctx, chapterID); err != nil {
+		return nil, err
+	}
+
+	previous, err := uc.pageRepo.ListByChapter(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePaths := make([]string, 0, len(images))
+	for i, image := range images {
+		imagePath, err := uc.savePageImage(chapterID, i+1, image.Filename, image.Data)
+		if err != nil {
+			uc.removePageImages(imagePaths...)
+			return nil, err
+		}
+		imagePaths = append(imagePaths, imagePath)
+	}
+
+	removedPaths, err := uc.pageRepo.ReplaceAll(ctx, chapterID, imagePaths)
+	if err != nil {
+		uc.removePageImages(imagePaths...)
+		return nil, err
+	}
+	uc.removePageImages(removedPaths...)
+
+	uc.invalidateChapterPages(ctx, chapterID, pageIDs(previous))
+
+	uc.log.Info("Страницы главы заменены", "event", "pages_replaced", "chapter_id", chapterID, "pages", len(imagePaths))
+
+	return uc.pageRepo.ListByChapter(ctx, chapterID)
+}
+
+// ensureChapterAccess проверяет, что глава существует и пользователь может изменять страницы ее манги
+func (uc *pageUseCase) ensureChapterAccess(ctx context.Context, chapterID int64) error {
+	chapter, err := uc.chapterRepo.GetByID(ctx, chapterID)
+	if err != nil {
+		return err
+	}
+
+	return ensureMangaAccess(ctx, uc.mangaRepo, chapter.MangaID)
+}
+
+// invalidateChapterPages сбрасывает кеш списка страниц главы и кеш перенумерованных страниц
+func (uc *pageUseCase) invalidateChapterPages(ctx context.Context, chapterID int64, ids []int64) {
+	for _, id := range ids {
+		cacheKey := fmt.Sprintf("page:%d", id)
+		if err := uc.cacheRepo.Delete(ctx, cacheKey); err != nil {
+			uc.log.Error("Ошибка инвалидации кеша страницы", "error", err.Error(), "page_id", id)
+		}
+	}
+
+	if err := uc.invalidatePageListCache(ctx, chapterID); err != nil {
+		uc.log.Error("Ошибка инвалидации кеша списка страниц", "error", err.Error(), "chapter_id", chapterID)
+	}
+}
+
+// pageIDs возвращает ID страниц
+func pageIDs(pages []*entity.Page) []int64 {
+	ids := make([]int64, len(pages))
+	for i, page := range pages {
+		ids[i] = page.ID
+	}
+	return ids
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// ratingRefreshInterval период пересчета байесовских оценок по среднему каталога
+const ratingRefreshInterval = time.Hour
+
+// RatingUseCase интерфейс, определяющий бизнес-логику оценок манги
+type RatingUseCase interface {
+	Rate(ctx context.Context, userID, mangaID int64, score int) (*entity.MangaRatingSummary, error)
+	Remove(ctx context.Context, userID, mangaID int64) error
+	Summary(ctx context.Context, mangaID int64) (*entity.MangaRatingSummary, error)
+	Run(ctx context.Context)
+}
+
+// ratingUseCase реализация интерфейса RatingUseCase
+type ratingUseCase struct {
+	ratingRepo repository.RatingRepository
+	cacheRepo  repository.CacheRepository
+	log        logger.Logger
+}
+
+// NewRatingUseCase создает новый экземпляр RatingUseCase
+func NewRatingUseCase(
+	ratingRepo repository.RatingRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) RatingUseCase {
+	return &ratingUseCase{
+		ratingRepo: ratingRepo,
+		cacheRepo:  cacheRepo,
+		log:        log,
+	}
+}
+
+// Rate ставит или изменяет оценку манги пользователем и возвращает обновленную сводку
+func (uc *ratingUseCase) Rate(ctx context.Context, userID, mangaID int64, score int) (*entity.MangaRatingSummary, error) {
+	if score < entity.RatingMinScore || score > entity.RatingMaxScore {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("Оценка должна быть от %d до %d", entity.RatingMinScore, entity.RatingMaxScore),
+			map[string]interface{}{"score": score},
+		)
+	}
+
+	if err := uc.ratingRepo.Rate(ctx, userID, mangaID, score); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateMangaCache(ctx, mangaID)
+
+	summary, err := uc.ratingRepo.Summary(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	summary.MyScore = &score
+
+	return summary, nil
+}
+
+// Remove удаляет оценку манги пользователем
+func (uc *ratingUseCase) Remove(ctx context.Context, userID, mangaID int64) error {
+	if err := uc.ratingRepo.Remove(ctx, userID, mangaID); err != nil {
+		return err
+	}
+
+	uc.invalidateMangaCache(ctx, mangaID)
+
+	return nil
+}
+
+// Summary возвращает среднюю и байесовскую оценки манги с гистограммой.
+// Аутентифицированный пользователь также получает свою оценку
+func (uc *ratingUseCase) Summary(ctx context.Context, mangaID int64) (*entity.MangaRatingSummary, error) {
+	summary, err := uc.ratingRepo.Summary(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor, ok := ActorFromContext(ctx); ok {
+		rating, err := uc.ratingRepo.Get(ctx, actor.UserID, mangaID)
+		if err == nil {
+			summary.MyScore = &rating.Score
+		} else if !errors.IsNotFoundError(err) {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+// Run периодически пересчитывает байесовские оценки до отмены контекста.
+// Первый пересчет выполняется сразу при запуске
+func (uc *ratingUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(ratingRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		updated, err := uc.ratingRepo.RefreshScores(ctx)
+		if err != nil {
+			uc.log.Error("Ошибка пересчета байесовских оценок", "error", err.Error())
+		} else {
+			uc.log.Info("Байесовские оценки пересчитаны", "updated", updated)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// invalidateMangaCache сбрасывает кеш манги, чтобы обновились агрегаты оценок.
+// Кешированные списки каталога обновятся по истечении срока жизни
+func (uc *ratingUseCase) invalidateMangaCache(ctx context.Context, mangaID int64) {
+	key := fmt.Sprintf("manga:%d", mangaID)
+	if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+		uc.log.Error("Ошибка инвалидации кеша", "error", err.Error(), "key", key)
+	}
+}
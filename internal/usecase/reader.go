@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"manga-reader2/internal/infrastructure/auth"
+	"sort"
+	"time"
+)
+
+// Параметры данных читалки
+const (
+	readerCacheTTL       = 15 * time.Minute
+	pageImageURLFormat   = "/api/v1/pages/%d/image"
+	pageImageSignedQuery = "?expires=%d&signature=%s"
+	pageImageURLTTL      = 2 * time.Hour // срок действия подписанных ссылок на изображения
+)
+
+// ReaderUseCase интерфейс, определяющий бизнес-логику открытия главы в читалке
+type ReaderUseCase interface {
+	Get(ctx context.Context, chapterID int64) (*entity.ReaderBootstrap, error)
+}
+
+// readerUseCase реализация интерфейса ReaderUseCase
+type readerUseCase struct {
+	chapterRepo    repository.ChapterRepository
+	mangaRepo      repository.MangaRepository
+	pageRepo       repository.PageRepository
+	cacheRepo      repository.CacheRepository
+	analyticsRepo  repository.AnalyticsRepository
+	historyUseCase ReadingHistoryUseCase
+	jwtService     *auth.JWTService
+	log            logger.Logger
+}
+
+// NewReaderUseCase создает новый экземпляр ReaderUseCase
+func NewReaderUseCase(
+	chapterRepo repository.ChapterRepository,
+	mangaRepo repository.MangaRepository,
+	pageRepo repository.PageRepository,
+	cacheRepo repository.CacheRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	historyUseCase ReadingHistoryUseCase,
+	jwtService *auth.JWTService,
+	log logger.Logger,
+) ReaderUseCase {
+	return &readerUseCase{
+		chapterRepo:    chapterRepo,
+		mangaRepo:      mangaRepo,
+		pageRepo:       pageRepo,
+		cacheRepo:      cacheRepo,
+		analyticsRepo:  analyticsRepo,
+		historyUseCase: historyUseCase,
+		jwtService:     jwtService,
+		log:            log,
+	}
+}
+
+// Get возвращает главу, мангу, страницы и соседние главы одним ответом. Ответ кешируется целиком,
+// доступ к главе проверяется при каждом открытии; открытие считается просмотром главы.
+// Ссылки на изображения глав с ограниченным доступом подписываются после чтения кеша: кеш общий для всех пользователей
+func (uc *readerUseCase) Get(ctx context.Context, chapterID int64) (*entity.ReaderBootstrap, error) {
+	cacheKey := readerCacheKey(chapterID)
+
+	var bootstrap *entity.ReaderBootstrap
+	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var cached entity.ReaderBootstrap
+		if err := json.Unmarshal([]byte(cachedData), &cached); err == nil {
+			bootstrap = &cached
+		} else {
+			uc.log.Error("Ошибка декодирования данных читалки из кеша", "error", err.Error())
+		}
+	}
+
+	if bootstrap == nil {
+		if bootstrap, err = uc.build(ctx, chapterID); err != nil {
+			return nil, err
+		}
+
+		if jsonData, err := json.Marshal(bootstrap); err == nil {
+			if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), readerCacheTTL); err != nil {
+				uc.log.Error("Ошибка кеширования данных читалки", "error", err.Error())
+			}
+		}
+	}
+
+	chapter := bootstrap.Chapter
+	if err := ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !chapter.IsPublished() || chapter.InEarlyAccess(now) {
+		uc.signPageImages(bootstrap.Pages, now.Add(pageImageURLTTL))
+	}
+
+	if err := uc.analyticsRepo.RecordChapterView(ctx, chapter.ID, chapter.MangaID); err != nil {
+		uc.log.Error("Ошибка записи просмотра главы", "error", err.Error(), "chapter_id", chapter.ID)
+	}
+
+	if actor, ok := ActorFromContext(ctx); ok {
+		uc.historyUseCase.RecordRead(ctx, &entity.ReadingHistoryEntry{
+			UserID:    actor.UserID,
+			MangaID:   chapter.MangaID,
+			ChapterID: chapter.ID,
+		})
+	}
+
+	return bootstrap, nil
+}
+
+// signPageImages добавляет к ссылкам на изображения страниц подпись, действующую до expiresAt
+func (uc *readerUseCase) signPageImages(pages []*entity.ReaderPage, expiresAt time.Time) {
+	for _, page := range pages {
+		page.ImageURL += fmt.Sprintf(pageImageSignedQuery, expiresAt.Unix(), uc.jwtService.SignPageImage(page.ID, expiresAt))
+	}
+}
+
+// build собирает данные читалки из базы
+func (uc *readerUseCase) build(ctx context.Context, chapterID int64) (*entity.ReaderBootstrap, error) {
+	chapter, err := uc.chapterRepo.GetByID(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	manga, err := uc.mangaRepo.GetByID(ctx, chapter.MangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := uc.pageRepo.ListByChapter(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].Number < pages[j].Number })
+
+	readerPages := make([]*entity.ReaderPage, len(pages))
+	for i, page := range pages {
+		readerPages[i] = &entity.ReaderPage{
+			ID:       page.ID,
+			Number:   page.Number,
+			ImageURL: fmt.Sprintf(pageImageURLFormat, page.ID),
+		}
+	}
+
+	chapters, err := uc.chapterRepo.ListByManga(ctx, chapter.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	prev, next := chapterNeighbours(chapters, chapter)
+
+	return &entity.ReaderBootstrap{
+		Chapter: chapter,
+		Manga: &entity.ReaderManga{
+			ID:         manga.ID,
+			Title:      manga.Title,
+			CoverImage: manga.CoverImage,
+			Status:     manga.Status,
+		},
+		Pages:         readerPages,
+		PrevChapterID: prev,
+		NextChapterID: next,
+	}, nil
+}
+
+// chapterNeighbours находит опубликованные главы на языке текущей с ближайшими меньшим и большим номером.
+// Если у соседнего номера несколько переводов, предпочитается перевод той же группы
+func chapterNeighbours(chapters []*entity.Chapter, current *entity.Chapter) (prev, next *int64) {
+	var prevChapter, nextChapter *entity.Chapter
+	for _, chapter := range chapters {
+		if !chapter.IsPublished() || chapter.Language != current.Language || chapter.ID == current.ID {
+			continue
+		}
+
+		switch {
+		case chapter.Number < current.Number:
+			if prevChapter == nil || chapter.Number > prevChapter.Number ||
+				chapter.Number == prevChapter.Number && preferNeighbour(chapter, prevChapter, current) {
+				prevChapter = chapter
+			}
+		case chapter.Number > current.Number:
+			if nextChapter == nil || chapter.Number < nextChapter.Number ||
+				chapter.Number == nextChapter.Number && preferNeighbour(chapter, nextChapter, current) {
+				nextChapter = chapter
+			}
+		}
+	}
+
+	if prevChapter != nil {
+		prev = &prevChapter.ID
+	}
+	if nextChapter != nil {
+		next = &nextChapter.ID
+	}
+	return prev, next
+}
+
+// preferNeighbour проверяет, лучше ли перевод candidate текущего выбора chosen с тем же номером:
+// сначала перевод группы текущей главы, затем более ранний
+func preferNeighbour(candidate, chosen, current *entity.Chapter) bool {
+	candidateSame, chosenSame := sameGroup(candidate, current), sameGroup(chosen, current)
+	if candidateSame != chosenSame {
+		return candidateSame
+	}
+	return candidate.ID < chosen.ID
+}
+
+// sameGroup проверяет, выпущены ли главы одной группой
+func sameGroup(a, b *entity.Chapter) bool {
+	if a.GroupID == nil || b.GroupID == nil {
+		return a.GroupID == nil && b.GroupID == nil
+	}
+	return *a.GroupID == *b.GroupID
+}
+
+// readerCacheKey возвращает ключ кеша данных читалки для главы
+func readerCacheKey(chapterID int64) string {
+	return fmt.Sprintf("chapter:%d:reader", chapterID)
+}
+
+// invalidateReaderCache сбрасывает кеш читалки для глав
+func invalidateReaderCache(ctx context.Context, cacheRepo repository.CacheRepository, log logger.Logger, chapterIDs ...int64) {
+	for _, chapterID := range chapterIDs {
+		key := readerCacheKey(chapterID)
+		if err := cacheRepo.Delete(ctx, key); err != nil {
+			log.Error("Ошибка инвалидации кеша читалки", "error", err.Error(), "key", key)
+		}
+	}
+}
+
+// invalidateMangaReaderCache сбрасывает кеш читалки для всех глав манги: изменение одной главы
+// меняет соседей других, а изменение манги — ее данные во всех главах
+func invalidateMangaReaderCache(ctx context.Context, chapterRepo repository.ChapterRepository, cacheRepo repository.CacheRepository, log logger.Logger, mangaID int64) {
+	chapters, err := chapterRepo.ListByManga(ctx, mangaID)
+	if err != nil {
+		log.Error("Ошибка получения глав для инвалидации кеша читалки", "error", err.Error(), "manga_id", mangaID)
+		return
+	}
+
+	ids := make([]int64, len(chapters))
+	for i, chapter := range chapters {
+		ids[i] = chapter.ID
+	}
+	invalidateReaderCache(ctx, cacheRepo, log, ids...)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"manga-reader2/internal/domain/entity"
+	"testing"
+)
+
+func TestChapterNeighbours(t *testing.T) {
+	groupA, groupB := int64(1), int64(2)
+	chapter := func(id int64, number float64, language string, group *int64, status string) *entity.Chapter {
+		return &entity.Chapter{ID: id, Number: number, Language: language, GroupID: group, Status: status}
+	}
+	published := entity.ChapterStatusPublished
+
+	tests := []struct {
+		name     string
+		chapters []*entity.Chapter
+		current  *entity.Chapter
+		wantPrev int64 // 0 — соседа нет
+		wantNext int64
+	}{
+		{
+			name: "ближайшие номера",
+			chapters: []*entity.Chapter{
+				chapter(1, 1, "ru", nil, published),
+				chapter(2, 2, "ru", nil, published),
+				chapter(3, 3, "ru", nil, published),
+				chapter(4, 4, "ru", nil, published),
+			},
+			current:  chapter(3, 3, "ru", nil, published),
+			wantPrev: 2,
+			wantNext: 4,
+		},
+		{
+			name: "дробные номера",
+			chapters: []*entity.Chapter{
+				chapter(1, 1, "ru", nil, published),
+				chapter(2, 1.5, "ru", nil, published),
+				chapter(3, 2, "ru", nil, published),
+			},
+			current:  chapter(1, 1, "ru", nil, published),
+			wantNext: 2,
+		},
+		{
+			name: "другой язык и неопубликованные главы пропускаются",
+			chapters: []*entity.Chapter{
+				chapter(1, 1, "ru", nil, published),
+				chapter(2, 2, "en", nil, published),
+				chapter(3, 3, "ru", nil, entity.ChapterStatusDraft),
+				chapter(4, 4, "ru", nil, entity.ChapterStatusScheduled),
+				chapter(5, 5, "ru", nil, published),
+			},
+			current:  chapter(1, 1, "ru", nil, published),
+			wantNext: 5,
+		},
+		{
+			name: "предпочитается перевод той же группы",
+			chapters: []*entity.Chapter{
+				chapter(1, 1, "ru", &groupA, published),
+				chapter(2, 1, "ru", &groupB, published),
+				chapter(3, 2, "ru", &groupB, published),
+				chapter(4, 3, "ru", &groupA, published),
+				chapter(5, 3, "ru", &groupB, published),
+			},
+			current:  chapter(3, 2, "ru", &groupB, published),
+			wantPrev: 2,
+			wantNext: 5,
+		},
+		{
+			name: "без своей группы предпочитается более ранний перевод",
+			chapters: []*entity.Chapter{
+				chapter(7, 2, "ru", &groupB, published),
+				chapter(6, 2, "ru", &groupA, published),
+				chapter(1, 1, "ru", nil, published),
+			},
+			current:  chapter(1, 1, "ru", nil, published),
+			wantNext: 6,
+		},
+		{
+			name: "другой перевод того же номера не считается соседом",
+			chapters: []*entity.Chapter{
+				chapter(1, 1, "ru", &groupA, published),
+				chapter(2, 1, "ru", &groupB, published),
+			},
+			current: chapter(1, 1, "ru", &groupA, published),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev, next := chapterNeighbours(tt.chapters, tt.current)
+			if got := idOrZero(prev); got != tt.wantPrev {
+				t.Errorf("предыдущая глава %d, ожидалась %d", got, tt.wantPrev)
+			}
+			if got := idOrZero(next); got != tt.wantNext {
+				t.Errorf("следующая глава %d, ожидалась %d", got, tt.wantNext)
+			}
+		})
+	}
+}
+
+func idOrZero(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// Параметры асинхронной записи истории чтения
+const (
+	readingHistoryQueueSize    = 1024
+	readingHistoryWriteTimeout = 5 * time.Second
+)
+
+// ReadingHistoryUseCase интерфейс, определяющий бизнес-логику для работы с историей чтения
+type ReadingHistoryUseCase interface {
+	RecordRead(ctx context.Context, entry *entity.ReadingHistoryEntry)
+	List(ctx context.Context, userID int64, filter entity.ReadingHistoryFilter) ([]*entity.ReadingHistoryItem, int, error)
+	ContinueReading(ctx context.Context, userID int64, limit int) ([]*entity.ReadingHistoryItem, error)
+	Remove(ctx context.Context, userID, entryID int64) error
+	Clear(ctx context.Context, userID int64) error
+	Run(ctx context.Context)
+}
+
+// readingHistoryUseCase реализация интерфейса ReadingHistoryUseCase
+type readingHistoryUseCase struct {
+	historyRepo repository.ReadingHistoryRepository
+	reads       chan *entity.ReadingHistoryEntry
+	log         logger.Logger
+}
+
+// NewReadingHistoryUseCase создает новый экземпляр ReadingHistoryUseCase.
+// Записи чтения сохраняются в фоне, для этого нужно запустить Run
+func NewReadingHistoryUseCase(historyRepo repository.ReadingHistoryRepository, log logger.Logger) ReadingHistoryUseCase {
+	return &readingHistoryUseCase{
+		historyRepo: historyRepo,
+		reads:       make(chan *entity.ReadingHistoryEntry, readingHistoryQueueSize),
+		log:         log,
+	}
+}
+
+// RecordRead ставит чтение главы или страницы в очередь на запись, не блокируя запрос.
+// При переполнении очереди запись отбрасывается
+func (uc *readingHistoryUseCase) RecordRead(ctx context.Context, entry *entity.ReadingHistoryEntry) {
+	select {
+	case uc.reads <- entry:
+	default:
+		uc.log.Warn("Очередь истории чтения переполнена, запись пропущена", "user_id", entry.UserID, "chapter_id", entry.ChapterID)
+	}
+}
+
+// Run записывает чтения из очереди до отмены контекста, после чего дописывает оставшиеся
+func (uc *readingHistoryUseCase) Run(ctx context.Context) {
+	for {
+		select {
+		case entry := <-uc.reads:
+			uc.save(entry)
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-uc.reads:
+					uc.save(entry)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// save сохраняет запись истории чтения
+func (uc *readingHistoryUseCase) save(entry *entity.ReadingHistoryEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), readingHistoryWriteTimeout)
+	defer cancel()
+
+	if err := uc.historyRepo.Upsert(ctx, entry); err != nil {
+		uc.log.Error("Ошибка сохранения истории чтения", "error", err.Error(), "user_id", entry.UserID, "chapter_id", entry.ChapterID)
+	}
+}
+
+// List возвращает историю чтения пользователя
+func (uc *readingHistoryUseCase) List(ctx context.Context, userID int64, filter entity.ReadingHistoryFilter) ([]*entity.ReadingHistoryItem, int, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.historyRepo.ListByUser(ctx, userID, filter)
+}
+
+// ContinueReading возвращает последнюю прочитанную главу и страницу по каждой манге
+func (uc *readingHistoryUseCase) ContinueReading(ctx context.Context, userID int64, limit int) ([]*entity.ReadingHistoryItem, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	return uc.historyRepo.ListLatestPerManga(ctx, userID, limit)
+}
+
+// Remove удаляет запись из истории чтения пользователя
+func (uc *readingHistoryUseCase) Remove(ctx context.Context, userID, entryID int64) error {
+	return uc.historyRepo.Delete(ctx, entryID, userID)
+}
+
+// Clear удаляет всю историю чтения пользователя
+func (uc *readingHistoryUseCase) Clear(ctx context.Context, userID int64) error {
+	return uc.historyRepo.DeleteAllByUser(ctx, userID)
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	stderrors "errors"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+	"time"
+)
+
+// progressSyncMaxItems максимальное количество позиций в одном пакете синхронизации
+const progressSyncMaxItems = 500
+
+// ReadingProgressUseCase интерфейс, определяющий бизнес-логику синхронизации прогресса чтения между устройствами
+type ReadingProgressUseCase interface {
+	Get(ctx context.Context, userID, mangaID int64) (*entity.ReadingProgress, error)
+	List(ctx context.Context, userID int64, since *time.Time) ([]*entity.ReadingProgress, error)
+	Update(ctx context.Context, userID, mangaID int64, update *entity.ProgressUpdate) (*entity.ProgressSyncResult, error)
+	Sync(ctx context.Context, userID int64, req *entity.ProgressSyncRequest) ([]*entity.ProgressSyncResult, error)
+}
+
+// readingProgressUseCase реализация интерфейса ReadingProgressUseCase
+type readingProgressUseCase struct {
+	progressRepo   repository.ReadingProgressRepository
+	chapterRepo    repository.ChapterRepository
+	mangaRepo      repository.MangaRepository
+	historyUseCase ReadingHistoryUseCase
+	log            logger.Logger
+}
+
+// NewReadingProgressUseCase создает новый экземпляр ReadingProgressUseCase
+func NewReadingProgressUseCase(
+	progressRepo repository.ReadingProgressRepository,
+	chapterRepo repository.ChapterRepository,
+	mangaRepo repository.MangaRepository,
+	historyUseCase ReadingHistoryUseCase,
+	log logger.Logger,
+) ReadingProgressUseCase {
+	return &readingProgressUseCase{
+		progressRepo:   progressRepo,
+		chapterRepo:    chapterRepo,
+		mangaRepo:      mangaRepo,
+		historyUseCase: historyUseCase,
+		log:            log,
+	}
+}
+
+// Get возвращает позицию чтения манги
+func (uc *readingProgressUseCase) Get(ctx context.Context, userID, mangaID int64) (*entity.ReadingProgress, error) {
+	return uc.progressRepo.Get(ctx, userID, mangaID)
+}
+
+// List возвращает позиции чтения пользователя, при указании since — только измененные после этого времени
+func (uc *readingProgressUseCase) List(ctx context.Context, userID int64, since *time.Time) ([]*entity.ReadingProgress, error) {
+	return uc.progressRepo.ListByUser(ctx, userID, since)
+}
+
+// Update сохраняет позицию чтения манги с устройства с учетом политики разрешения конфликтов
+func (uc *readingProgressUseCase) Update(ctx context.Context, userID, mangaID int64, update *entity.ProgressUpdate) (*entity.ProgressSyncResult, error) {
+	if update.MangaID != 0 && update.MangaID != mangaID {
+		return nil, errors.NewValidationError("ID манги в теле запроса не совпадает с адресом", nil)
+	}
+	update.MangaID = mangaID
+
+	return uc.apply(ctx, userID, update, entity.ProgressPolicyLatestWrite)
+}
+
+// Sync применяет пакет позиций, накопленных устройством без сети.
+// Ошибка в одном элементе не прерывает обработку остальных
+func (uc *readingProgressUseCase) Sync(ctx context.Context, userID int64, req *entity.ProgressSyncRequest) ([]*entity.ProgressSyncResult, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.NewValidationError("Пакет синхронизации пуст", nil)
+	}
+	if len(req.Items) > progressSyncMaxItems {
+		return nil, errors.NewValidationError("Слишком много позиций в пакете синхронизации", map[string]interface{}{
+			"max_items": progressSyncMaxItems,
+		})
+	}
+	for i, item := range req.Items {
+		if item == nil {
+			return nil, errors.NewValidationError("Пустая позиция в пакете синхронизации", map[string]interface{}{
+				"index": i,
+			})
+		}
+	}
+
+	defaultPolicy := req.Policy
+	if defaultPolicy == "" {
+		defaultPolicy = entity.ProgressPolicyLatestWrite
+	}
+
+	results := make([]*entity.ProgressSyncResult, 0, len(req.Items))
+	for _, item := range req.Items {
+		result, err := uc.apply(ctx, userID, item, defaultPolicy)
+		if err != nil {
+			var appErr *errors.AppError
+			if !stderrors.As(err, &appErr) || errors.IsDatabaseError(err) {
+				return nil, err
+			}
+			result = &entity.ProgressSyncResult{MangaID: item.MangaID, Error: appErr.Message}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// apply проверяет позицию и сохраняет ее, если она побеждает сохраненную по политике.
+// Позиция в недоступной пользователю главе не сохраняется и не попадает в историю
+func (uc *readingProgressUseCase) apply(ctx context.Context, userID int64, update *entity.ProgressUpdate, defaultPolicy string) (*entity.ProgressSyncResult, error) {
+	policy := update.Policy
+	if policy == "" {
+		policy = defaultPolicy
+	}
+	if policy != entity.ProgressPolicyLatestWrite && policy != entity.ProgressPolicyFurthestRead {
+		return nil, errors.NewValidationError("Неизвестная политика синхронизации", map[string]interface{}{
+			"policy":  policy,
+			"allowed": []string{entity.ProgressPolicyLatestWrite, entity.ProgressPolicyFurthestRead},
+		})
+	}
+
+	if err := validateProgressUpdate(update); err != nil {
+		return nil, err
+	}
+
+	chapter, err := uc.chapterRepo.GetByID(ctx, update.ChapterID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter.MangaID != update.MangaID {
+		return nil, errors.NewValidationError("Глава не принадлежит указанной манге", nil)
+	}
+	if err = ensureChapterReadable(ctx, uc.mangaRepo, chapter); err != nil {
+		return nil, err
+	}
+
+	// Время из будущего (сбитые часы устройства) навсегда выигрывало бы по политике latest_write
+	clientUpdatedAt := update.ClientUpdatedAt.UTC()
+	if now := time.Now().UTC(); clientUpdatedAt.After(now) {
+		clientUpdatedAt = now
+	}
+
+	progress := &entity.ReadingProgress{
+		UserID:          userID,
+		MangaID:         update.MangaID,
+		ChapterID:       update.ChapterID,
+		ChapterNumber:   chapter.Number,
+		PageNumber:      update.PageNumber,
+		ScrollOffset:    update.ScrollOffset,
+		DeviceID:        update.DeviceID,
+		ClientUpdatedAt: clientUpdatedAt,
+	}
+
+	applied, err := uc.progressRepo.Save(ctx, progress, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if applied {
+		pageNumber := progress.PageNumber
+		uc.historyUseCase.RecordRead(ctx, &entity.ReadingHistoryEntry{
+			UserID:     userID,
+			MangaID:    progress.MangaID,
+			ChapterID:  progress.ChapterID,
+			PageNumber: &pageNumber,
+		})
+
+		return &entity.ProgressSyncResult{MangaID: progress.MangaID, Applied: true, Progress: progress}, nil
+	}
+
+	current, err := uc.progressRepo.Get(ctx, userID, update.MangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ProgressSyncResult{MangaID: update.MangaID, Applied: false, Progress: current}, nil
+}
+
+// validateProgressUpdate проверяет поля позиции чтения
+func validateProgressUpdate(update *entity.ProgressUpdate) error {
+	if update.MangaID <= 0 {
+		return errors.NewValidationError("Не указан ID манги", nil)
+	}
+	if update.ChapterID <= 0 {
+		return errors.NewValidationError("Не указан ID главы", nil)
+	}
+	if update.PageNumber < 1 {
+		return errors.NewValidationError("Номер страницы должен быть положительным", nil)
+	}
+	if update.ScrollOffset < 0 || update.ScrollOffset > 1 {
+		return errors.NewValidationError("Смещение прокрутки должно быть от 0 до 1", nil)
+	}
+
+	update.DeviceID = strings.TrimSpace(update.DeviceID)
+	if update.DeviceID == "" {
+		return errors.NewValidationError("Не указан ID устройства", nil)
+	}
+	if len(update.DeviceID) > 100 {
+		return errors.NewValidationError("ID устройства не может быть длиннее 100 символов", nil)
+	}
+
+	if update.ClientUpdatedAt.IsZero() {
+		return errors.NewValidationError("Не указано время изменения на устройстве", nil)
+	}
+
+	return nil
+}
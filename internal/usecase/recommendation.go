@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// Параметры подборки рекомендаций
+const (
+	// recommendationPoolSize число рекомендаций, подбираемых и кешируемых для пользователя за раз
+	recommendationPoolSize = 100
+	// recommendationCacheTTL срок жизни подборки: новые прочтения учитываются после его истечения
+	recommendationCacheTTL = 30 * time.Minute
+	// similarityRebuildInterval период пересчета таблицы похожести манги
+	similarityRebuildInterval = 6 * time.Hour
+	// similarityCheckInterval период проверки, не устарела ли таблица похожести
+	similarityCheckInterval = 30 * time.Minute
+)
+
+// RecommendationUseCase интерфейс, определяющий бизнес-логику персональных рекомендаций
+type RecommendationUseCase interface {
+	List(ctx context.Context, userID int64, limit, offset int) ([]*entity.Recommendation, int, error)
+	Run(ctx context.Context)
+}
+
+// recommendationUseCase реализация интерфейса RecommendationUseCase
+type recommendationUseCase struct {
+	recommendationRepo repository.RecommendationRepository
+	analyticsRepo      repository.AnalyticsRepository
+	cacheRepo          repository.CacheRepository
+	log                logger.Logger
+}
+
+// NewRecommendationUseCase создает новый экземпляр RecommendationUseCase
+func NewRecommendationUseCase(
+	recommendationRepo repository.RecommendationRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) RecommendationUseCase {
+	return &recommendationUseCase{
+		recommendationRepo: recommendationRepo,
+		analyticsRepo:      analyticsRepo,
+		cacheRepo:          cacheRepo,
+		log:                log,
+	}
+}
+
+// List возвращает страницу персональных рекомендаций и их общее количество.
+// Подборка строится целиком и кешируется, страницы берутся из нее
+func (uc *recommendationUseCase) List(ctx context.Context, userID int64, limit, offset int) ([]*entity.Recommendation, int, error) {
+	pool, err := uc.pool(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := len(pool)
+	if offset >= total {
+		return []*entity.Recommendation{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	return pool[offset:end], total, nil
+}
+
+// Run периодически пересчитывает таблицу похожести манги, если она устарела
+func (uc *recommendationUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(similarityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		rebuilt, err := uc.recommendationRepo.RebuildSimilarity(ctx, time.Now().Add(-similarityRebuildInterval))
+		if err != nil {
+			uc.log.Error("Ошибка пересчета похожести манги", "error", err.Error())
+		} else if rebuilt {
+			uc.log.Info("Похожесть манги пересчитана")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pool возвращает подборку пользователя из кеша или строит ее заново.
+// Пользователю без истории чтения (или прочитавшему все подходящее) подбирается популярная манга
+func (uc *recommendationUseCase) pool(ctx context.Context, userID int64) ([]*entity.Recommendation, error) {
+	cacheKey := fmt.Sprintf("user:%d:recommendations", userID)
+	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var pool []*entity.Recommendation
+		if err = json.Unmarshal([]byte(cachedData), &pool); err == nil {
+			return pool, nil
+		}
+		uc.log.Error("Ошибка декодирования рекомендаций из кеша", "error", err.Error())
+	}
+
+	pool, err := uc.recommendationRepo.ListForUser(ctx, userID, recommendationPoolSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pool) == 0 {
+		if pool, err = uc.recommendationRepo.ListPopularForUser(ctx, userID, uc.popularIDs(ctx), recommendationPoolSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if jsonData, err := json.Marshal(pool); err == nil {
+		if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), recommendationCacheTTL); err != nil {
+			uc.log.Error("Ошибка кеширования рекомендаций", "error", err.Error())
+		}
+	}
+
+	return pool, nil
+}
+
+// popularIDs возвращает самую просматриваемую мангу за неделю, а если статистики за неделю нет — за все время.
+// Ошибка аналитики не мешает подборке: популярная манга тогда упорядочивается по оценкам
+func (uc *recommendationUseCase) popularIDs(ctx context.Context) []int64 {
+	for _, period := range []entity.StatsPeriod{entity.StatsPeriodWeekly, entity.StatsPeriodAllTime} {
+		stats, err := uc.analyticsRepo.GetTopManga(ctx, period, recommendationPoolSize)
+		if err != nil {
+			uc.log.Warn("Ошибка получения популярной манги для рекомендаций", "error", err.Error(), "period", period)
+			return nil
+		}
+		if len(stats) == 0 {
+			continue
+		}
+
+		ids := make([]int64, 0, len(stats))
+		for _, stat := range stats {
+			ids = append(ids, stat.MangaID)
+		}
+		return ids
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/common/markdown"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// reviewTitleMaxLength ограничивает длину заголовка рецензии в символах
+	reviewTitleMaxLength = 200
+	// reviewMinLength и reviewMaxLength ограничивают длину текста рецензии в символах;
+	// короткие отзывы оставляются комментариями
+	reviewMinLength = 100
+	reviewMaxLength = 30000
+)
+
+// ReviewUseCase интерфейс, определяющий бизнес-логику рецензий
+type ReviewUseCase interface {
+	Create(ctx context.Context, userID, mangaID int64, input *entity.ReviewInput) (*entity.ReviewWithUser, error)
+	GetByID(ctx context.Context, id int64) (*entity.ReviewWithUser, error)
+	List(ctx context.Context, filter entity.ReviewFilter) ([]*entity.ReviewWithUser, int, error)
+	Update(ctx context.Context, userID, id int64, input *entity.ReviewInput) (*entity.ReviewWithUser, error)
+	Delete(ctx context.Context, userID, id int64) error
+	Vote(ctx context.Context, userID, id int64, value int) (*entity.ReviewVoteResult, error)
+}
+
+// reviewUseCase реализация интерфейса ReviewUseCase
+type reviewUseCase struct {
+	reviewRepo repository.ReviewRepository
+	mangaRepo  repository.MangaRepository
+	cacheRepo  repository.CacheRepository
+	log        logger.Logger
+}
+
+// NewReviewUseCase создает новый экземпляр ReviewUseCase
+func NewReviewUseCase(
+	reviewRepo repository.ReviewRepository,
+	mangaRepo repository.MangaRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) ReviewUseCase {
+	return &reviewUseCase{
+		reviewRepo: reviewRepo,
+		mangaRepo:  mangaRepo,
+		cacheRepo:  cacheRepo,
+		log:        log,
+	}
+}
+
+// Create публикует рецензию на мангу; у пользователя может быть только одна рецензия на мангу
+func (uc *reviewUseCase) Create(ctx context.Context, userID, mangaID int64, input *entity.ReviewInput) (*entity.ReviewWithUser, error) {
+	if _, err := uc.mangaRepo.GetByID(ctx, mangaID); err != nil {
+		return nil, err
+	}
+
+	title, content, err := validateReviewInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &entity.Review{
+		MangaID:     mangaID,
+		UserID:      userID,
+		Title:       title,
+		Content:     content,
+		ContentHTML: markdown.Render(content),
+		Spoiler:     input.Spoiler,
+	}
+	if err = uc.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateMangaCache(ctx, mangaID)
+
+	return uc.GetByID(ctx, review.ID)
+}
+
+// GetByID возвращает рецензию
+func (uc *reviewUseCase) GetByID(ctx context.Context, id int64) (*entity.ReviewWithUser, error) {
+	review, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.annotateVotes(ctx, review)
+
+	return review, nil
+}
+
+// List возвращает рецензии манги; по умолчанию сначала самые полезные
+func (uc *reviewUseCase) List(ctx context.Context, filter entity.ReviewFilter) ([]*entity.ReviewWithUser, int, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.ReviewSortHelpful
+	}
+	switch filter.Sort {
+	case entity.ReviewSortHelpful, entity.ReviewSortNewest:
+	default:
+		return nil, 0, errors.NewValidationError("Некорректная сортировка", map[string]interface{}{
+			"sort":    filter.Sort,
+			"allowed": entity.ReviewSorts,
+		})
+	}
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if _, err := uc.mangaRepo.GetByID(ctx, filter.MangaID); err != nil {
+		return nil, 0, err
+	}
+
+	reviews, total, err := uc.reviewRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	uc.annotateVotes(ctx, reviews...)
+
+	return reviews, total, nil
+}
+
+// Update редактирует собственную рецензию
+func (uc *reviewUseCase) Update(ctx context.Context, userID, id int64, input *entity.ReviewInput) (*entity.ReviewWithUser, error) {
+	existing, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID != userID {
+		return nil, errors.NewForbiddenError("Можно редактировать только свои рецензии", nil)
+	}
+
+	title, content, err := validateReviewInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	review := existing.Review
+	review.Title = title
+	review.Content = content
+	review.ContentHTML = markdown.Render(content)
+	review.Spoiler = input.Spoiler
+
+	if err = uc.reviewRepo.Update(ctx, &review); err != nil {
+		return nil, err
+	}
+
+	return uc.GetByID(ctx, id)
+}
+
+// Delete удаляет рецензию. Автор удаляет свои рецензии, модератор — любые
+func (uc *reviewUseCase) Delete(ctx context.Context, userID, id int64) error {
+	review, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	moderated := false
+	if review.UserID != userID {
+		actor, ok := ActorFromContext(ctx)
+		if !ok || !actor.HasPermission(entity.PermissionCommentModerate) {
+			return errors.NewForbiddenError("Можно удалять только свои рецензии", nil)
+		}
+		moderated = true
+	}
+
+	if err = uc.reviewRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if moderated {
+		uc.log.Info("Рецензия удалена модератором", "event", "review_deleted",
+			"review_id", id, "author_id", review.UserID, "moderator_id", userID)
+	}
+
+	uc.invalidateMangaCache(ctx, review.MangaID)
+
+	return nil
+}
+
+// Vote отмечает рецензию полезной (1) или бесполезной (-1); 0 снимает отметку
+func (uc *reviewUseCase) Vote(ctx context.Context, userID, id int64, value int) (*entity.ReviewVoteResult, error) {
+	if value < -1 || value > 1 {
+		return nil, errors.NewValidationError("Оценка должна быть равна 1, -1 или 0", map[string]interface{}{
+			"value": value,
+		})
+	}
+
+	review, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == userID {
+		return nil, errors.NewForbiddenError("Нельзя оценивать собственные рецензии", nil)
+	}
+
+	return uc.reviewRepo.Vote(ctx, userID, id, value)
+}
+
+// annotateVotes проставляет рецензиям оценки полезности текущего пользователя
+func (uc *reviewUseCase) annotateVotes(ctx context.Context, reviews ...*entity.ReviewWithUser) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok || len(reviews) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ID)
+	}
+
+	votes, err := uc.reviewRepo.ListUserVotes(ctx, actor.UserID, ids)
+	if err != nil {
+		uc.log.Error("Ошибка получения оценок рецензий", "error", err.Error(), "user_id", actor.UserID)
+		return
+	}
+
+	for _, review := range reviews {
+		if vote, ok := votes[review.ID]; ok {
+			review.MyVote = &vote
+		}
+	}
+}
+
+// invalidateMangaCache сбрасывает кеш манги, чтобы обновился счетчик рецензий
+func (uc *reviewUseCase) invalidateMangaCache(ctx context.Context, mangaID int64) {
+	key := fmt.Sprintf("manga:%d", mangaID)
+	if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+		uc.log.Error("Ошибка инвалидации кеша", "error", err.Error(), "key", key)
+	}
+}
+
+// validateReviewInput нормализует и проверяет заголовок и текст рецензии
+func validateReviewInput(input *entity.ReviewInput) (string, string, error) {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return "", "", errors.NewValidationError("Заголовок рецензии не может быть пустым", nil)
+	}
+	if utf8.RuneCountInString(title) > reviewTitleMaxLength {
+		return "", "", errors.NewValidationError(fmt.Sprintf("Заголовок рецензии не может быть длиннее %d символов", reviewTitleMaxLength), nil)
+	}
+
+	content := strings.TrimSpace(input.Content)
+	length := utf8.RuneCountInString(content)
+	if length < reviewMinLength {
+		return "", "", errors.NewValidationError(fmt.Sprintf("Текст рецензии должен содержать не менее %d символов", reviewMinLength), nil)
+	}
+	if length > reviewMaxLength {
+		return "", "", errors.NewValidationError(fmt.Sprintf("Текст рецензии не может быть длиннее %d символов", reviewMaxLength), nil)
+	}
+
+	return title, content, nil
+}
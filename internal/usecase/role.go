@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"time"
+)
+
+// actorContextKey ключ контекста для пользователя, выполняющего операцию
+type actorContextKey struct{}
+
+// WithActor сохраняет в контексте пользователя, от имени которого выполняется операция
+func WithActor(ctx context.Context, actor *entity.Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext возвращает пользователя, от имени которого выполняется операция
+func ActorFromContext(ctx context.Context) (*entity.Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(*entity.Actor)
+	return actor, ok
+}
+
+// RoleUseCase интерфейс, определяющий бизнес-логику для работы с ролями и разрешениями
+type RoleUseCase interface {
+	List(ctx context.Context) ([]*entity.Role, error)
+	UpdatePermissions(ctx context.Context, role string, permissions []string) (*entity.Role, error)
+	AssignUploader(ctx context.Context, mangaID, userID int64) error
+	UnassignUploader(ctx context.Context, mangaID, userID int64) error
+}
+
+// roleUseCase реализация интерфейса RoleUseCase
+type roleUseCase struct {
+	roleRepo  repository.RoleRepository
+	mangaRepo repository.MangaRepository
+	userRepo  repository.UserRepository
+	cacheRepo repository.CacheRepository
+	log       logger.Logger
+}
+
+// NewRoleUseCase создает новый экземпляр RoleUseCase
+func NewRoleUseCase(
+	roleRepo repository.RoleRepository,
+	mangaRepo repository.MangaRepository,
+	userRepo repository.UserRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+) RoleUseCase {
+	return &roleUseCase{
+		roleRepo:  roleRepo,
+		mangaRepo: mangaRepo,
+		userRepo:  userRepo,
+		cacheRepo: cacheRepo,
+		log:       log,
+	}
+}
+
+// List возвращает список ролей с разрешениями
+func (uc *roleUseCase) List(ctx context.Context) ([]*entity.Role, error) {
+	return uc.roleRepo.List(ctx)
+}
+
+// UpdatePermissions заменяет набор разрешений роли
+func (uc *roleUseCase) UpdatePermissions(ctx context.Context, role string, permissions []string) (*entity.Role, error) {
+	exists, err := uc.roleRepo.Exists(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("Роль %s не найдена", role), nil)
+	}
+
+	known, err := uc.roleRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make([]string, 0, len(permissions))
+	seen := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		valid := false
+		for _, k := range known {
+			if permission == k {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, errors.NewValidationError("Неизвестное разрешение", map[string]interface{}{
+				"permission": permission,
+				"allowed":    known,
+			})
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			unique = append(unique, permission)
+		}
+	}
+
+	if err = uc.roleRepo.SetPermissions(ctx, role, unique); err != nil {
+		return nil, err
+	}
+
+	if err = uc.cacheRepo.Delete(ctx, rolePermissionsCacheKey(role)); err != nil {
+		uc.log.Error("Ошибка инвалидации кеша разрешений роли", "error", err.Error(), "role", role)
+	}
+
+	uc.log.Info("Изменены разрешения роли", "event", "role_permissions_updated", "role", role, "permissions", unique)
+
+	return &entity.Role{Name: role, Permissions: unique}, nil
+}
+
+// AssignUploader назначает пользователя загрузчиком манги
+func (uc *roleUseCase) AssignUploader(ctx context.Context, mangaID, userID int64) error {
+	if _, err := uc.mangaRepo.GetByID(ctx, mangaID); err != nil {
+		return err
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.mangaRepo.AssignUploader(ctx, mangaID, userID)
+}
+
+// UnassignUploader снимает пользователя с роли загрузчика манги
+func (uc *roleUseCase) UnassignUploader(ctx context.Context, mangaID, userID int64) error {
+	return uc.mangaRepo.UnassignUploader(ctx, mangaID, userID)
+}
+
+// resolvePermissions возвращает разрешения роли, используя кеш
+func resolvePermissions(
+	ctx context.Context,
+	roleRepo repository.RoleRepository,
+	cacheRepo repository.CacheRepository,
+	log logger.Logger,
+	role string,
+) ([]string, error) {
+	cacheKey := rolePermissionsCacheKey(role)
+	cachedData, err := cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var permissions []string
+		if err = json.Unmarshal([]byte(cachedData), &permissions); err == nil {
+			return permissions, nil
+		}
+		log.Error("Ошибка декодирования разрешений роли из кеша", "error", err.Error())
+	}
+
+	permissions, err := roleRepo.GetPermissions(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if jsonData, err := json.Marshal(permissions); err == nil {
+		if err := cacheRepo.Set(ctx, cacheKey, string(jsonData), 10*time.Minute); err != nil {
+			log.Error("Ошибка кеширования разрешений роли", "error", err.Error())
+		}
+	}
+
+	return permissions, nil
+}
+
+// ensureMangaAccess проверяет, может ли текущий пользователь изменять главы и страницы манги.
+// Пользователи с разрешением manga:write могут изменять любую мангу, загрузчики — только назначенную.
+// Без пользователя в контексте изменение запрещено; внутренние вызовы выполняются от entity.SystemActor
+func ensureMangaAccess(ctx context.Context, mangaRepo repository.MangaRepository, mangaID int64) error {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return errors.NewForbiddenError("Недостаточно прав для изменения манги", nil)
+	}
+	if actor.HasPermission(entity.PermissionMangaWrite) {
+		return nil
+	}
+
+	if !actor.HasPermission(entity.PermissionChapterUpload) {
+		return errors.NewForbiddenError("Недостаточно прав для изменения манги", nil)
+	}
+
+	assigned, err := mangaRepo.IsUploaderAssigned(ctx, mangaID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return errors.NewForbiddenError("Вы не назначены загрузчиком этой манги", nil)
+	}
+
+	return nil
+}
+
+// rolePermissionsCacheKey возвращает ключ кеша разрешений роли
+func rolePermissionsCacheKey(role string) string {
+	return fmt.Sprintf("role:%s:permissions", role)
+}
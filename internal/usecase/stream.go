@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"manga-reader2/internal/common/errors"
+	"manga-reader2/internal/common/logger"
+	"manga-reader2/internal/domain/entity"
+	"manga-reader2/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+// Параметры доставки событий реального времени
+const (
+	streamReplayLimit      = 1000
+	streamSubscriberBuffer = 64
+	streamResubscribeDelay = 5 * time.Second
+)
+
+// StreamUseCase интерфейс, определяющий бизнес-логику доставки событий реального времени
+type StreamUseCase interface {
+	PublishNotification(ctx context.Context, userIDs []int64, data *entity.StreamNotificationData) error
+	PublishChapterReleased(ctx context.Context, chapter *entity.Chapter) error
+	Subscribe(ctx context.Context, userID, lastEventID int64) (*StreamSubscription, error)
+	Run(ctx context.Context)
+}
+
+// StreamSubscription представляет подписку соединения пользователя на события
+type StreamSubscription struct {
+	// Replay содержит пропущенные после Last-Event-ID события; при разрыве журнала первым идет событие reset
+	Replay []*entity.StreamEvent
+	// Events закрывается, когда подписка прекращена: клиент не успевает читать или экземпляр останавливается.
+	// Клиент должен переподключиться с Last-Event-ID
+	Events <-chan *entity.StreamEvent
+
+	subscriber *streamSubscriber
+	replayed   map[int64]struct{}
+	hub        *streamUseCase
+}
+
+// Skip проверяет, было ли событие уже отправлено при возобновлении
+func (s *StreamSubscription) Skip(event *entity.StreamEvent) bool {
+	_, ok := s.replayed[event.ID]
+	return ok
+}
+
+// Close отменяет подписку
+func (s *StreamSubscription) Close() {
+	s.hub.unsubscribe(s.subscriber)
+}
+
+// streamSubscriber представляет локальное соединение, получающее события
+type streamSubscriber struct {
+	userID int64
+	events chan *entity.StreamEvent
+}
+
+// streamUseCase реализация интерфейса StreamUseCase.
+// Каждый экземпляр приложения держит одну подписку на шину событий и раздает события своим соединениям
+type streamUseCase struct {
+	streamRepo  repository.StreamRepository
+	mu          sync.Mutex
+	subscribers map[*streamSubscriber]struct{}
+	closed      bool
+	log         logger.Logger
+}
+
+// NewStreamUseCase создает новый экземпляр StreamUseCase.
+// Для получения событий других экземпляров нужно запустить Run
+func NewStreamUseCase(streamRepo repository.StreamRepository, log logger.Logger) StreamUseCase {
+	return &streamUseCase{
+		streamRepo:  streamRepo,
+		subscribers: make(map[*streamSubscriber]struct{}),
+		log:         log,
+	}
+}
+
+// PublishNotification публикует событие о новых уведомлениях для указанных пользователей
+func (uc *streamUseCase) PublishNotification(ctx context.Context, userIDs []int64, data *entity.StreamNotificationData) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	return uc.publish(ctx, entity.StreamEventNotification, userIDs, data)
+}
+
+// PublishChapterReleased публикует событие о выходе главы для всех авторизованных пользователей
+func (uc *streamUseCase) PublishChapterReleased(ctx context.Context, chapter *entity.Chapter) error {
+	return uc.publish(ctx, entity.StreamEventChapterReleased, nil, &entity.StreamChapterData{
+		MangaID:   chapter.MangaID,
+		ChapterID: chapter.ID,
+		Number:    chapter.Number,
+		Title:     chapter.Title,
+	})
+}
+
+// publish сериализует данные события и отправляет его в шину
+func (uc *streamUseCase) publish(ctx context.Context, eventType string, userIDs []int64, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return errors.NewInternalError("Ошибка сериализации события", err)
+	}
+
+	return uc.streamRepo.Publish(ctx, &entity.StreamEvent{
+		Type:    eventType,
+		UserIDs: userIDs,
+		Data:    payload,
+	})
+}
+
+// Subscribe регистрирует соединение пользователя и возвращает события, пропущенные после lastEventID.
+// Соединение регистрируется до чтения журнала, поэтому события на стыке не теряются;
+// повторы отсекаются через StreamSubscription.Skip
+func (uc *streamUseCase) Subscribe(ctx context.Context, userID, lastEventID int64) (*StreamSubscription, error) {
+	subscriber := &streamSubscriber{
+		userID: userID,
+		events: make(chan *entity.StreamEvent, streamSubscriberBuffer),
+	}
+
+	uc.mu.Lock()
+	if uc.closed {
+		uc.mu.Unlock()
+		return nil, errors.NewInternalError("Сервер останавливается", nil)
+	}
+	uc.subscribers[subscriber] = struct{}{}
+	uc.mu.Unlock()
+
+	subscription := &StreamSubscription{
+		Replay:     []*entity.StreamEvent{},
+		Events:     subscriber.events,
+		subscriber: subscriber,
+		replayed:   make(map[int64]struct{}),
+		hub:        uc,
+	}
+
+	if lastEventID <= 0 {
+		return subscription, nil
+	}
+
+	events, truncated, err := uc.streamRepo.Since(ctx, lastEventID, streamReplayLimit)
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+
+	// Если пропущено больше, чем можно восстановить, клиенту проще перезапросить состояние целиком
+	if truncated || len(events) >= streamReplayLimit {
+		subscription.Replay = append(subscription.Replay, &entity.StreamEvent{
+			Type:      entity.StreamEventReset,
+			Data:      json.RawMessage(`{}`),
+			CreatedAt: time.Now(),
+		})
+		if len(events) >= streamReplayLimit {
+			events = nil
+		}
+	}
+
+	for _, event := range events {
+		subscription.replayed[event.ID] = struct{}{}
+		if event.IsFor(userID) {
+			subscription.Replay = append(subscription.Replay, event)
+		}
+	}
+
+	return subscription, nil
+}
+
+// Run получает события из шины и раздает их локальным соединениям до отмены контекста.
+// После остановки все подписки закрываются, чтобы потоковые соединения завершились
+func (uc *streamUseCase) Run(ctx context.Context) {
+	defer uc.closeAll()
+
+	for ctx.Err() == nil {
+		events, err := uc.streamRepo.Subscribe(ctx)
+		if err != nil {
+			uc.log.Error("Ошибка подписки на события, повтор позже", "error", err.Error())
+		} else {
+			for event := range events {
+				uc.dispatch(event)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamResubscribeDelay):
+		}
+	}
+}
+
+// dispatch отправляет событие адресатам. Соединения с переполненным буфером отключаются
+func (uc *streamUseCase) dispatch(event *entity.StreamEvent) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for subscriber := range uc.subscribers {
+		if !event.IsFor(subscriber.userID) {
+			continue
+		}
+
+		select {
+		case subscriber.events <- event:
+		default:
+			uc.log.Warn("Соединение не успевает получать события, подписка закрыта", "user_id", subscriber.userID)
+			delete(uc.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+}
+
+// unsubscribe удаляет соединение из рассылки
+func (uc *streamUseCase) unsubscribe(subscriber *streamSubscriber) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.subscribers[subscriber]; ok {
+		delete(uc.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
+
+// closeAll закрывает все подписки и запрещает новые
+func (uc *streamUseCase) closeAll() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.closed = true
+	for subscriber := range uc.subscribers {
+		delete(uc.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	stderrors "errors"
+	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"manga-reader2/internal/common/errors"
 	"manga-reader2/internal/common/logger"
@@ -11,35 +13,87 @@ import (
 	"manga-reader2/internal/infrastructure/auth"
 	"regexp"
 	"strings"
+	"time"
 )
 
+// Параметры защиты от перебора паролей
+const (
+	loginMaxUserAttempts = 5
+	loginMaxIPAttempts   = 20
+	loginAttemptsWindow  = 15 * time.Minute
+	loginBaseLockout     = time.Minute
+	loginMaxLockout      = 24 * time.Hour
+)
+
+// maxPreferredLanguages ограничивает число предпочитаемых языков глав
+const maxPreferredLanguages = 10
+
+// userAccessCacheTTL время, в течение которого статус доступа пользователя берется из кеша
+const userAccessCacheTTL = time.Minute
+
 // UserUseCase интерфейс, определяющий бизнес-логику для работы с пользователями
 type UserUseCase interface {
 	Register(ctx context.Context, reg *entity.UserRegistration) (*entity.User, error)
-	Login(ctx context.Context, cred *entity.UserCredentials) (*entity.TokenPair, error)
+	Login(ctx context.Context, cred *entity.UserCredentials, ip string) (*entity.TokenPair, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
 	GetProfile(ctx context.Context, userID int64) (*entity.User, error)
 	UpdateProfile(ctx context.Context, user *entity.User) (*entity.User, error)
 	ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error
+	GetPreferredLanguages(ctx context.Context, userID int64) (*entity.PreferredLanguages, error)
+	SetPreferredLanguages(ctx context.Context, userID int64, languages []string) (*entity.PreferredLanguages, error)
+	UnlockUser(ctx context.Context, userID, adminID int64) error
+
+	// Администрирование
+	ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int, error)
+	GetUser(ctx context.Context, userID int64) (*entity.User, error)
+	UpdateUser(ctx context.Context, userID int64, update *entity.UserAdminUpdate, adminID int64) (*entity.User, error)
+	BanUser(ctx context.Context, userID int64, ban *entity.UserBan, adminID int64) (*entity.User, error)
+	UnbanUser(ctx context.Context, userID, adminID int64) error
+	DeleteUser(ctx context.Context, userID, adminID int64) error
+	CheckAccess(ctx context.Context, userID int64) (*entity.Actor, error)
+}
+
+// userAccessStatus кешируемый статус доступа и роль пользователя, проверяемые на каждом запросе
+type userAccessStatus struct {
+	Role        string     `json:"role"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	BanReason   string     `json:"ban_reason,omitempty"`
+	BannedAt    *time.Time `json:"banned_at,omitempty"`
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
 }
 
 // userUseCase реализация интерфейса UserUseCase
 type userUseCase struct {
 	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	cacheRepo  repository.CacheRepository
 	jwtService *auth.JWTService
 	log        logger.Logger
+	dummyHash  []byte
 }
 
 // NewUserUseCase создает новый экземпляр UserUseCase
 func NewUserUseCase(
 	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	cacheRepo repository.CacheRepository,
 	jwtService *auth.JWTService,
 	log logger.Logger,
 ) UserUseCase {
+	// Хеш-заглушка нужен, чтобы вход несуществующего пользователя занимал столько же времени,
+	// сколько проверка реального пароля
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("Ошибка генерации хеша-заглушки", "error", err.Error())
+	}
+
 	return &userUseCase{
 		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		cacheRepo:  cacheRepo,
 		jwtService: jwtService,
 		log:        log,
+		dummyHash:  dummyHash,
 	}
 }
 
@@ -95,7 +149,7 @@ func (uc *userUseCase) Register(ctx context.Context, reg *entity.UserRegistratio
 		Username: reg.Username,
 		Email:    reg.Email,
 		Password: string(hashedPassword),
-		Role:     "user",
+		Role:     entity.RoleReader,
 	}
 
 	id, err := uc.userRepo.Create(ctx, user)
@@ -114,7 +168,7 @@ func (uc *userUseCase) Register(ctx context.Context, reg *entity.UserRegistratio
 }
 
 // Login аутентифицирует пользователя и возвращает токен
-func (uc *userUseCase) Login(ctx context.Context, cred *entity.UserCredentials) (*entity.TokenPair, error) {
+func (uc *userUseCase) Login(ctx context.Context, cred *entity.UserCredentials, ip string) (*entity.TokenPair, error) {
 	if cred.Username == "" {
 		return nil, errors.NewValidationError("Имя пользователя не может быть пустым", nil)
 	}
@@ -122,28 +176,74 @@ func (uc *userUseCase) Login(ctx context.Context, cred *entity.UserCredentials)
 		return nil, errors.NewValidationError("Пароль не может быть пустым", nil)
 	}
 
-	user, err := uc.userRepo.GetByUsername(ctx, cred.Username)
+	ipKey := fmt.Sprintf("ip:%s", ip)
+	if retryAfter := uc.lockoutRemaining(ctx, ipKey); retryAfter > 0 {
+		uc.log.Warn("Попытка входа с заблокированного IP", "event", "login_blocked", "ip", ip, "login", cred.Username)
+		return nil, errors.NewAccountLockedError(retryAfter)
+	}
+
+	var user *entity.User
+	var err error
+	if strings.Contains(cred.Username, "@") {
+		user, err = uc.userRepo.GetByEmail(ctx, cred.Username)
+	} else {
+		user, err = uc.userRepo.GetByUsername(ctx, cred.Username)
+	}
+
 	if err != nil {
-		if errors.IsErrorCode(err, errors.ErrorCodeUserNotFound) {
-			user, err = uc.userRepo.GetByEmail(ctx, cred.Username)
-			if err != nil {
-				return nil, errors.NewInvalidCredentialsError()
-			}
-		} else {
+		if !errors.IsErrorCode(err, errors.ErrorCodeUserNotFound) {
 			return nil, err
 		}
+
+		// Несуществующее имя блокируется так же, как аккаунт, иначе по ACCOUNT_LOCKED можно перечислять пользователей
+		_ = bcrypt.CompareHashAndPassword(uc.dummyHash, []byte(cred.Password))
+		loginKey := fmt.Sprintf("login:%s", cred.Username)
+		if retryAfter := uc.lockoutRemaining(ctx, loginKey); retryAfter > 0 {
+			uc.log.Warn("Попытка входа в заблокированный аккаунт", "event", "login_blocked", "reason", "unknown_user", "ip", ip, "login", cred.Username)
+			return nil, errors.NewAccountLockedError(retryAfter)
+		}
+
+		uc.registerLoginFailure(ctx, loginKey, loginMaxUserAttempts)
+		uc.registerLoginFailure(ctx, ipKey, loginMaxIPAttempts)
+		uc.log.Warn("Неудачная попытка входа", "event", "login_failed", "reason", "unknown_user", "ip", ip, "login", cred.Username)
+		return nil, errors.NewInvalidCredentialsError()
+	}
+
+	userKey := fmt.Sprintf("user:%d", user.ID)
+	if retryAfter := uc.lockoutRemaining(ctx, userKey); retryAfter > 0 {
+		_ = bcrypt.CompareHashAndPassword(uc.dummyHash, []byte(cred.Password))
+		uc.log.Warn("Попытка входа в заблокированный аккаунт", "event", "login_blocked", "user_id", user.ID, "ip", ip)
+		return nil, errors.NewAccountLockedError(retryAfter)
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(cred.Password)); err != nil {
+		uc.registerLoginFailure(ctx, userKey, loginMaxUserAttempts)
+		uc.registerLoginFailure(ctx, ipKey, loginMaxIPAttempts)
+		uc.log.Warn("Неудачная попытка входа", "event", "login_failed", "reason", "invalid_password", "user_id", user.ID, "ip", ip)
 		return nil, errors.NewInvalidCredentialsError()
 	}
 
+	if err = checkUserAccess(user); err != nil {
+		uc.log.Warn("Попытка входа заблокированного пользователя", "event", "login_banned", "user_id", user.ID, "ip", ip)
+		return nil, err
+	}
+
+	if err := uc.cacheRepo.Delete(ctx, loginAttemptsKey(userKey)); err != nil {
+		uc.log.Error("Ошибка сброса счетчика попыток входа", "error", err.Error(), "user_id", user.ID)
+	}
+
+	if user.Permissions, err = resolvePermissions(ctx, uc.roleRepo, uc.cacheRepo, uc.log, user.Role); err != nil {
+		return nil, err
+	}
+
 	tokenPair, err := uc.jwtService.GenerateTokenPair(user)
 	if err != nil {
 		uc.log.Error("Ошибка генерации токена", "error", err.Error(), "user_id", user.ID)
 		return nil, errors.NewInternalError("Ошибка генерации токена", err)
 	}
 
+	uc.log.Info("Успешный вход", "event", "login_success", "user_id", user.ID, "ip", ip)
+
 	return tokenPair, nil
 }
 
@@ -162,6 +262,14 @@ func (uc *userUseCase) RefreshToken(ctx context.Context, refreshToken string) (*
 		return nil, err
 	}
 
+	if err = checkUserAccess(user); err != nil {
+		return nil, err
+	}
+
+	if user.Permissions, err = resolvePermissions(ctx, uc.roleRepo, uc.cacheRepo, uc.log, user.Role); err != nil {
+		return nil, err
+	}
+
 	tokenPair, err := uc.jwtService.GenerateTokenPair(user)
 	if err != nil {
 		uc.log.Error("Ошибка генерации токена", "error", err.Error(), "user_id", user.ID)
@@ -243,3 +351,349 @@ func (uc *userUseCase) ChangePassword(ctx context.Context, userID int64, oldPass
 
 	return nil
 }
+
+// GetPreferredLanguages возвращает предпочитаемые языки глав пользователя
+func (uc *userUseCase) GetPreferredLanguages(ctx context.Context, userID int64) (*entity.PreferredLanguages, error) {
+	languages, err := uc.userRepo.GetPreferredLanguages(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.PreferredLanguages{Languages: languages}, nil
+}
+
+// SetPreferredLanguages сохраняет предпочитаемые языки глав пользователя в порядке приоритета.
+// Пустой список отключает фильтрацию глав по языку
+func (uc *userUseCase) SetPreferredLanguages(ctx context.Context, userID int64, languages []string) (*entity.PreferredLanguages, error) {
+	normalized := make([]string, 0, len(languages))
+	seen := make(map[string]bool, len(languages))
+	for _, language := range languages {
+		code := NormalizeLanguage(language)
+		if code == "" {
+			return nil, errors.NewValidationError("Некорректный код языка", map[string]interface{}{
+				"language": language,
+			})
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	if len(normalized) > maxPreferredLanguages {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("Не более %d предпочитаемых языков", maxPreferredLanguages),
+			map[string]interface{}{"languages": len(normalized)},
+		)
+	}
+
+	if err := uc.userRepo.SetPreferredLanguages(ctx, userID, normalized); err != nil {
+		return nil, err
+	}
+
+	return &entity.PreferredLanguages{Languages: normalized}, nil
+}
+
+// UnlockUser снимает временную блокировку входа с аккаунта
+func (uc *userUseCase) UnlockUser(ctx context.Context, userID, adminID int64) error {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	userKey := fmt.Sprintf("user:%d", userID)
+	for _, key := range []string{loginAttemptsKey(userKey), loginLockKey(userKey)} {
+		if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+			return errors.NewInternalError("Ошибка снятия блокировки", err)
+		}
+	}
+
+	uc.log.Info("Блокировка входа снята", "event", "login_unlocked", "user_id", userID, "admin_id", adminID)
+
+	return nil
+}
+
+// ListUsers возвращает страницу пользователей по фильтру и общее количество найденных
+func (uc *userUseCase) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int, error) {
+	switch filter.Status {
+	case "", entity.UserStatusActive, entity.UserStatusBanned, entity.UserStatusDeleted:
+	default:
+		return nil, 0, errors.NewValidationError("Некорректный статус пользователя", map[string]interface{}{
+			"status":  filter.Status,
+			"allowed": []string{entity.UserStatusActive, entity.UserStatusBanned, entity.UserStatusDeleted},
+		})
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	users, total, err := uc.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, user := range users {
+		user.Password = ""
+	}
+
+	return users, total, nil
+}
+
+// GetUser получает пользователя по ID для администратора
+func (uc *userUseCase) GetUser(ctx context.Context, userID int64) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+
+	return user, nil
+}
+
+// UpdateUser изменяет роль пользователя. Новая роль действует со следующего запроса:
+// аутентификация берет роль из статуса доступа, а не из токена
+func (uc *userUseCase) UpdateUser(ctx context.Context, userID int64, update *entity.UserAdminUpdate, adminID int64) (*entity.User, error) {
+	if userID == adminID {
+		return nil, errors.NewValidationError("Нельзя изменить роль собственной учетной записи", nil)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
+		return nil, errors.NewUserNotFoundError(userID)
+	}
+
+	if update.Role != "" && update.Role != user.Role {
+		exists, err := uc.roleRepo.Exists(ctx, update.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.NewValidationError(fmt.Sprintf("Роль %s не существует", update.Role), nil)
+		}
+
+		previousRole := user.Role
+		user.Role = update.Role
+		if err = uc.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+
+		uc.invalidateAccessStatus(ctx, userID)
+
+		uc.log.Info("Изменена роль пользователя", "event", "user_role_changed", "user_id", userID, "admin_id", adminID, "from", previousRole, "to", update.Role)
+	}
+
+	return uc.GetUser(ctx, userID)
+}
+
+// BanUser блокирует пользователя до указанного времени или бессрочно
+func (uc *userUseCase) BanUser(ctx context.Context, userID int64, ban *entity.UserBan, adminID int64) (*entity.User, error) {
+	if userID == adminID {
+		return nil, errors.NewValidationError("Нельзя заблокировать собственную учетную запись", nil)
+	}
+
+	if err := normalizeUserBan(ban); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Ban(ctx, userID, ban, adminID); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateAccessStatus(ctx, userID)
+
+	until := "permanent"
+	if ban.Until != nil {
+		until = ban.Until.UTC().Format(time.RFC3339)
+	}
+	uc.log.Warn("Пользователь заблокирован", "event", "user_banned", "user_id", userID, "admin_id", adminID, "reason", ban.Reason, "until", until)
+
+	return uc.GetUser(ctx, userID)
+}
+
+// UnbanUser снимает блокировку с пользователя
+func (uc *userUseCase) UnbanUser(ctx context.Context, userID, adminID int64) error {
+	if err := uc.userRepo.Unban(ctx, userID); err != nil {
+		return err
+	}
+
+	uc.invalidateAccessStatus(ctx, userID)
+
+	uc.log.Info("Блокировка пользователя снята", "event", "user_unbanned", "user_id", userID, "admin_id", adminID)
+
+	return nil
+}
+
+// DeleteUser удаляет пользователя с анонимизацией персональных данных.
+// Комментарии пользователя сохраняются и отображаются от имени удаленного пользователя
+func (uc *userUseCase) DeleteUser(ctx context.Context, userID, adminID int64) error {
+	if userID == adminID {
+		return errors.NewValidationError("Нельзя удалить собственную учетную запись", nil)
+	}
+
+	if err := uc.userRepo.Anonymize(ctx, userID); err != nil {
+		return err
+	}
+
+	uc.invalidateAccessStatus(ctx, userID)
+
+	uc.log.Warn("Пользователь удален", "event", "user_deleted", "user_id", userID, "admin_id", adminID)
+
+	return nil
+}
+
+// CheckAccess проверяет, что пользователь не удален и не заблокирован, и возвращает его текущие роль
+// и разрешения. Вызывается на каждом аутентифицированном запросе, поэтому статус кешируется,
+// а роль и разрешения берутся не из токена: изменение роли действует сразу, а не после его обновления
+func (uc *userUseCase) CheckAccess(ctx context.Context, userID int64) (*entity.Actor, error) {
+	cacheKey := userAccessCacheKey(userID)
+
+	var status userAccessStatus
+	cachedData, err := uc.cacheRepo.Get(ctx, cacheKey)
+	if err != nil || cachedData == "" || json.Unmarshal([]byte(cachedData), &status) != nil || status.Role == "" {
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				return nil, errors.NewUnauthorizedError("Пользователь не найден", nil)
+			}
+			return nil, err
+		}
+
+		status = userAccessStatus{
+			Role:        user.Role,
+			DeletedAt:   user.DeletedAt,
+			BanReason:   user.BanReason,
+			BannedAt:    user.BannedAt,
+			BannedUntil: user.BannedUntil,
+		}
+
+		if jsonData, err := json.Marshal(status); err == nil {
+			if err := uc.cacheRepo.Set(ctx, cacheKey, string(jsonData), userAccessCacheTTL); err != nil {
+				uc.log.Error("Ошибка кеширования статуса пользователя", "error", err.Error(), "user_id", userID)
+			}
+		}
+	}
+
+	err = checkUserAccess(&entity.User{
+		BanReason:   status.BanReason,
+		BannedAt:    status.BannedAt,
+		BannedUntil: status.BannedUntil,
+		DeletedAt:   status.DeletedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Кеш разрешений роли сбрасывается при их изменении
+	permissions, err := resolvePermissions(ctx, uc.roleRepo, uc.cacheRepo, uc.log, status.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Actor{UserID: userID, Role: status.Role, Permissions: permissions}, nil
+}
+
+// invalidateAccessStatus удаляет закешированный статус доступа пользователя
+func (uc *userUseCase) invalidateAccessStatus(ctx context.Context, userID int64) {
+	invalidateUserAccess(ctx, uc.cacheRepo, uc.log, userID)
+}
+
+// invalidateUserAccess удаляет закешированный статус доступа пользователя,
+// чтобы блокировка или смена роли действовали со следующего запроса
+func invalidateUserAccess(ctx context.Context, cacheRepo repository.CacheRepository, log logger.Logger, userID int64) {
+	if err := cacheRepo.Delete(ctx, userAccessCacheKey(userID)); err != nil {
+		log.Error("Ошибка инвалидации кеша статуса пользователя", "error", err.Error(), "user_id", userID)
+	}
+}
+
+// normalizeUserBan проверяет причину и срок блокировки пользователя
+func normalizeUserBan(ban *entity.UserBan) error {
+	ban.Reason = strings.TrimSpace(ban.Reason)
+	if ban.Reason == "" {
+		return errors.NewValidationError("Необходимо указать причину блокировки", nil)
+	}
+	if len(ban.Reason) > 500 {
+		return errors.NewValidationError("Причина блокировки не может быть длиннее 500 символов", nil)
+	}
+	if ban.Until != nil && !ban.Until.After(time.Now()) {
+		return errors.NewValidationError("Срок блокировки должен быть в будущем", nil)
+	}
+	return nil
+}
+
+// lockoutRemaining возвращает оставшееся время блокировки или 0, если блокировки нет
+func (uc *userUseCase) lockoutRemaining(ctx context.Context, subject string) time.Duration {
+	ttl, err := uc.cacheRepo.TTL(ctx, loginLockKey(subject))
+	if err != nil || ttl <= 0 {
+		return 0
+	}
+	return ttl
+}
+
+// registerLoginFailure увеличивает счетчик неудачных попыток и при превышении порога
+// блокирует вход с экспоненциально растущим временем блокировки
+func (uc *userUseCase) registerLoginFailure(ctx context.Context, subject string, maxAttempts int64) {
+	attemptsKey := loginAttemptsKey(subject)
+
+	attempts, err := uc.cacheRepo.IncrWithTTL(ctx, attemptsKey, loginAttemptsWindow)
+	if err != nil {
+		uc.log.Error("Ошибка учета неудачной попытки входа", "error", err.Error(), "subject", subject)
+		return
+	}
+
+	if attempts < maxAttempts {
+		return
+	}
+
+	lockout := loginBaseLockout
+	for i := maxAttempts; i < attempts && lockout < loginMaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > loginMaxLockout {
+		lockout = loginMaxLockout
+	}
+
+	if err := uc.cacheRepo.Set(ctx, loginLockKey(subject), "1", lockout); err != nil {
+		uc.log.Error("Ошибка блокировки входа", "error", err.Error(), "subject", subject)
+		return
+	}
+
+	// Счетчик живет дольше блокировки, чтобы следующие неудачи удлиняли ее
+	if err := uc.cacheRepo.Expire(ctx, attemptsKey, lockout+loginAttemptsWindow); err != nil {
+		uc.log.Error("Ошибка продления окна попыток входа", "error", err.Error(), "subject", subject)
+	}
+
+	uc.log.Warn("Вход временно заблокирован", "event", "login_locked", "subject", subject, "attempts", attempts, "lockout", lockout.String())
+}
+
+// loginAttemptsKey возвращает ключ счетчика неудачных попыток входа
+func loginAttemptsKey(subject string) string {
+	return fmt.Sprintf("login:attempts:%s", subject)
+}
+
+// loginLockKey возвращает ключ блокировки входа
+func loginLockKey(subject string) string {
+	return fmt.Sprintf("login:lock:%s", subject)
+}
+
+// userAccessCacheKey возвращает ключ кеша статуса доступа пользователя
+func userAccessCacheKey(userID int64) string {
+	return fmt.Sprintf("user:%d:access", userID)
+}
+
+// checkUserAccess возвращает ошибку, если пользователь удален или заблокирован
+func checkUserAccess(user *entity.User) error {
+	if user.IsDeleted() {
+		return errors.NewUnauthorizedError("Учетная запись удалена", nil)
+	}
+	if user.IsBanned(time.Now()) {
+		return errors.NewUserBannedError(user.BanReason, user.BannedUntil)
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+-- migrations/000003_create_api_keys.down.sql
+
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+
+DROP TABLE IF EXISTS api_keys;
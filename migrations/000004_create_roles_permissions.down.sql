@@ -0,0 +1,12 @@
+-- migrations/000004_create_roles_permissions.down.sql
+
+DROP INDEX IF EXISTS idx_manga_uploaders_user_id;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+UPDATE users SET role = 'user' WHERE role <> 'admin';
+
+DROP TABLE IF EXISTS manga_uploaders;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
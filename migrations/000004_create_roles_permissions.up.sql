@@ -0,0 +1,68 @@
+-- migrations/000004_create_roles_permissions.up.sql
+
+-- Таблица ролей
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(20) PRIMARY KEY,
+    description VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Таблица разрешений
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255)
+);
+
+-- Таблица связи ролей и разрешений
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission),
+    FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (permission) REFERENCES permissions(name) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Таблица назначений загрузчиков на мангу
+CREATE TABLE IF NOT EXISTS manga_uploaders (
+    manga_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (manga_id, user_id),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('reader', 'Читатель'),
+    ('uploader', 'Загрузчик глав назначенной манги'),
+    ('moderator', 'Модератор комментариев'),
+    ('editor', 'Редактор каталога'),
+    ('admin', 'Администратор')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('manga:write', 'Создание и редактирование любой манги'),
+    ('chapter:upload', 'Загрузка глав и страниц'),
+    ('comment:moderate', 'Модерация комментариев'),
+    ('user:manage', 'Управление пользователями и ролями'),
+    ('analytics:manage', 'Управление статистикой')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('uploader', 'chapter:upload'),
+    ('moderator', 'comment:moderate'),
+    ('editor', 'manga:write'),
+    ('editor', 'chapter:upload'),
+    ('admin', 'manga:write'),
+    ('admin', 'chapter:upload'),
+    ('admin', 'comment:moderate'),
+    ('admin', 'user:manage'),
+    ('admin', 'analytics:manage')
+ON CONFLICT (role, permission) DO NOTHING;
+
+-- Роль "user" заменяется ролью "reader", роли пользователей ссылаются на таблицу ролей
+UPDATE users SET role = 'reader' WHERE role = 'user' OR role NOT IN (SELECT name FROM roles);
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'reader';
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+CREATE INDEX idx_manga_uploaders_user_id ON manga_uploaders(user_id);
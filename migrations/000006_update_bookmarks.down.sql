@@ -0,0 +1,7 @@
+-- migrations/000006_update_bookmarks.down.sql
+
+ALTER TABLE bookmarks DROP CONSTRAINT IF EXISTS bookmarks_chapter_id_fkey;
+ALTER TABLE bookmarks ADD CONSTRAINT bookmarks_chapter_id_fkey
+    FOREIGN KEY (chapter_id) REFERENCES chapters(id) ON DELETE CASCADE;
+
+ALTER TABLE bookmarks DROP COLUMN IF EXISTS updated_at;
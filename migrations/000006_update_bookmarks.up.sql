@@ -0,0 +1,9 @@
+-- migrations/000006_update_bookmarks.up.sql
+
+-- Время последнего изменения закладки (смена последней прочитанной главы)
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Удаление главы не должно удалять закладку на мангу
+ALTER TABLE bookmarks DROP CONSTRAINT IF EXISTS bookmarks_chapter_id_fkey;
+ALTER TABLE bookmarks ADD CONSTRAINT bookmarks_chapter_id_fkey
+    FOREIGN KEY (chapter_id) REFERENCES chapters(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- migrations/000007_update_reading_history.down.sql
+
+DROP INDEX IF EXISTS idx_reading_history_user_read_at;
+
+ALTER TABLE reading_history DROP CONSTRAINT IF EXISTS reading_history_user_chapter_key;
@@ -0,0 +1,12 @@
+-- migrations/000007_update_reading_history.up.sql
+
+-- Повторное чтение главы обновляет запись, а не создает новую: оставляем только последнюю запись
+DELETE FROM reading_history a
+USING reading_history b
+WHERE a.user_id = b.user_id
+  AND a.chapter_id = b.chapter_id
+  AND (a.read_at < b.read_at OR (a.read_at = b.read_at AND a.id < b.id));
+
+ALTER TABLE reading_history ADD CONSTRAINT reading_history_user_chapter_key UNIQUE (user_id, chapter_id);
+
+CREATE INDEX idx_reading_history_user_read_at ON reading_history(user_id, read_at DESC);
@@ -0,0 +1,5 @@
+-- migrations/000008_create_reading_progress.down.sql
+
+DROP INDEX IF EXISTS idx_reading_progress_user_updated_at;
+
+DROP TABLE IF EXISTS reading_progress;
@@ -0,0 +1,19 @@
+-- migrations/000008_create_reading_progress.up.sql
+
+-- Текущая позиция чтения манги, синхронизируемая между устройствами пользователя
+CREATE TABLE IF NOT EXISTS reading_progress (
+    user_id INTEGER NOT NULL,
+    manga_id INTEGER NOT NULL,
+    chapter_id INTEGER NOT NULL,
+    page_number INTEGER NOT NULL DEFAULT 1,
+    scroll_offset DOUBLE PRECISION NOT NULL DEFAULT 0, -- Доля прокрутки страницы от 0 до 1
+    device_id VARCHAR(100) NOT NULL,
+    client_updated_at TIMESTAMP NOT NULL, -- Время изменения на устройстве
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, manga_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (chapter_id) REFERENCES chapters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_progress_user_updated_at ON reading_progress(user_id, updated_at);
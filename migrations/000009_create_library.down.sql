@@ -0,0 +1,9 @@
+-- migrations/000009_create_library.down.sql
+
+DROP INDEX IF EXISTS idx_library_shelf_entries_entry_id;
+DROP INDEX IF EXISTS idx_library_entries_manga_id;
+DROP INDEX IF EXISTS idx_library_entries_user_status;
+
+DROP TABLE IF EXISTS library_shelf_entries;
+DROP TABLE IF EXISTS library_shelves;
+DROP TABLE IF EXISTS library_entries;
@@ -0,0 +1,41 @@
+-- migrations/000009_create_library.up.sql
+
+-- Записи библиотеки пользователя: статус чтения, личная оценка и заметки
+CREATE TABLE IF NOT EXISTS library_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    manga_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL, -- reading, planned, completed, dropped
+    score SMALLINT CHECK (score BETWEEN 1 AND 10),
+    notes TEXT NOT NULL DEFAULT '',
+    started_at DATE,
+    finished_at DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    UNIQUE (user_id, manga_id)
+);
+
+-- Пользовательские полки
+CREATE TABLE IF NOT EXISTS library_shelves (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (user_id, name)
+);
+
+-- Связь полок и записей библиотеки
+CREATE TABLE IF NOT EXISTS library_shelf_entries (
+    shelf_id INTEGER NOT NULL,
+    entry_id INTEGER NOT NULL,
+    PRIMARY KEY (shelf_id, entry_id),
+    FOREIGN KEY (shelf_id) REFERENCES library_shelves(id) ON DELETE CASCADE,
+    FOREIGN KEY (entry_id) REFERENCES library_entries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_library_entries_user_status ON library_entries(user_id, status);
+CREATE INDEX idx_library_entries_manga_id ON library_entries(manga_id);
+CREATE INDEX idx_library_shelf_entries_entry_id ON library_shelf_entries(entry_id);
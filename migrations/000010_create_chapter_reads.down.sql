@@ -0,0 +1,5 @@
+-- migrations/000010_create_chapter_reads.down.sql
+
+DROP INDEX IF EXISTS idx_chapter_reads_user_manga;
+
+DROP TABLE IF EXISTS chapter_reads;
@@ -0,0 +1,21 @@
+-- migrations/000010_create_chapter_reads.up.sql
+
+-- Прочитанные пользователем главы
+CREATE TABLE IF NOT EXISTS chapter_reads (
+    user_id INTEGER NOT NULL,
+    chapter_id INTEGER NOT NULL,
+    manga_id INTEGER NOT NULL, -- Дублируется из главы для подсчета непрочитанных по манге
+    read_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, chapter_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (chapter_id) REFERENCES chapters(id) ON DELETE CASCADE,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_chapter_reads_user_manga ON chapter_reads(user_id, manga_id);
+
+-- Главы из истории чтения считаются прочитанными
+INSERT INTO chapter_reads (user_id, chapter_id, manga_id, read_at)
+SELECT user_id, chapter_id, manga_id, read_at
+FROM reading_history
+ON CONFLICT (user_id, chapter_id) DO NOTHING;
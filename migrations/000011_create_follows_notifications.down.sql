@@ -0,0 +1,10 @@
+-- migrations/000011_create_follows_notifications.down.sql
+
+DROP INDEX IF EXISTS idx_notification_jobs_pending;
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+DROP INDEX IF EXISTS idx_notifications_user_created_at;
+DROP INDEX IF EXISTS idx_manga_follows_manga_user;
+
+DROP TABLE IF EXISTS notification_jobs;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS manga_follows;
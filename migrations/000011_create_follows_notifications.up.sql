@@ -0,0 +1,48 @@
+-- migrations/000011_create_follows_notifications.up.sql
+
+-- Подписки пользователей на мангу
+CREATE TABLE IF NOT EXISTS manga_follows (
+    user_id INTEGER NOT NULL,
+    manga_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, manga_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+);
+
+-- Обход подписчиков манги по возрастанию user_id при рассылке
+CREATE INDEX idx_manga_follows_manga_user ON manga_follows(manga_id, user_id);
+
+-- Уведомления внутри приложения
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(30) NOT NULL, -- new_chapter
+    manga_id INTEGER NOT NULL,
+    chapter_id INTEGER,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (chapter_id) REFERENCES chapters(id) ON DELETE CASCADE,
+    UNIQUE (user_id, type, chapter_id) -- Повтор пакета рассылки не создает дубликатов
+);
+
+CREATE INDEX idx_notifications_user_created_at ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Задания рассылки уведомлений; cursor_user_id — последний обработанный подписчик
+CREATE TABLE IF NOT EXISTS notification_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(30) NOT NULL,
+    manga_id INTEGER NOT NULL,
+    chapter_id INTEGER,
+    cursor_user_id INTEGER NOT NULL DEFAULT 0,
+    notified INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (chapter_id) REFERENCES chapters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_jobs_pending ON notification_jobs(id) WHERE completed_at IS NULL;
@@ -0,0 +1,18 @@
+-- migrations/000012_extend_comments.down.sql
+
+ALTER TABLE chapters DROP COLUMN IF EXISTS comment_count;
+ALTER TABLE manga DROP COLUMN IF EXISTS comment_count;
+
+DROP INDEX IF EXISTS idx_comment_edits_comment_id;
+DROP TABLE IF EXISTS comment_edits;
+
+DROP INDEX IF EXISTS idx_comments_chapter_created_at;
+DROP INDEX IF EXISTS idx_comments_manga_created_at;
+DROP INDEX IF EXISTS idx_comments_parent_id;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS reply_count;
+ALTER TABLE comments DROP COLUMN IF EXISTS spoiler;
+ALTER TABLE comments DROP COLUMN IF EXISTS content_html;
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,38 @@
+-- migrations/000012_extend_comments.up.sql
+
+-- Ветки ответов, отрендеренный Markdown, спойлеры и мягкое удаление комментариев
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spoiler BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reply_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX idx_comments_parent_id ON comments(parent_id, created_at);
+CREATE INDEX idx_comments_manga_created_at ON comments(manga_id, created_at DESC) WHERE parent_id IS NULL;
+CREATE INDEX idx_comments_chapter_created_at ON comments(chapter_id, created_at DESC) WHERE parent_id IS NULL;
+
+-- История правок: предыдущие версии текста комментария
+CREATE TABLE IF NOT EXISTS comment_edits (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    edited_by INTEGER,
+    edited_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (edited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_comment_edits_comment_id ON comment_edits(comment_id, edited_at);
+
+-- Счетчики видимых комментариев, поддерживаются при создании и удалении
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS comment_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS comment_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE manga m SET comment_count = c.cnt
+FROM (SELECT manga_id, COUNT(*) AS cnt FROM comments WHERE manga_id IS NOT NULL GROUP BY manga_id) c
+WHERE m.id = c.manga_id;
+
+UPDATE chapters ch SET comment_count = c.cnt
+FROM (SELECT chapter_id, COUNT(*) AS cnt FROM comments WHERE chapter_id IS NOT NULL GROUP BY chapter_id) c
+WHERE ch.id = c.chapter_id;
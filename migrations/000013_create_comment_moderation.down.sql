@@ -0,0 +1,19 @@
+-- migrations/000013_create_comment_moderation.down.sql
+
+DROP INDEX IF EXISTS idx_moderation_log_moderator_id;
+DROP INDEX IF EXISTS idx_moderation_log_target_user_id;
+DROP INDEX IF EXISTS idx_moderation_log_created_at;
+DROP TABLE IF EXISTS moderation_log;
+
+DROP INDEX IF EXISTS idx_comments_reported;
+DROP INDEX IF EXISTS idx_comment_reports_open;
+DROP TABLE IF EXISTS comment_reports;
+
+DROP INDEX IF EXISTS idx_comment_votes_comment_id;
+DROP TABLE IF EXISTS comment_votes;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS report_count;
+ALTER TABLE comments DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS score;
+ALTER TABLE comments DROP COLUMN IF EXISTS downvotes;
+ALTER TABLE comments DROP COLUMN IF EXISTS upvotes;
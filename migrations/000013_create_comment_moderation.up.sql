@@ -0,0 +1,59 @@
+-- migrations/000013_create_comment_moderation.up.sql
+
+-- Рейтинг и скрытие комментариев
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS upvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS downvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0; -- upvotes - downvotes
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS report_count INTEGER NOT NULL DEFAULT 0; -- открытые жалобы
+
+-- Голоса пользователей за комментарии
+CREATE TABLE IF NOT EXISTS comment_votes (
+    user_id INTEGER NOT NULL,
+    comment_id INTEGER NOT NULL,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, comment_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_comment_votes_comment_id ON comment_votes(comment_id);
+
+-- Жалобы на комментарии
+CREATE TABLE IF NOT EXISTS comment_reports (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL,
+    reporter_id INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL, -- spam, abuse, spoiler, offtopic, other
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, resolved, dismissed
+    resolved_by INTEGER,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE (comment_id, reporter_id)
+);
+
+CREATE INDEX idx_comment_reports_open ON comment_reports(comment_id) WHERE status = 'open';
+CREATE INDEX idx_comments_reported ON comments(report_count DESC) WHERE report_count > 0;
+
+-- Журнал действий модераторов; moderator_id NULL — автоматическое действие системы
+CREATE TABLE IF NOT EXISTS moderation_log (
+    id BIGSERIAL PRIMARY KEY,
+    moderator_id INTEGER,
+    action VARCHAR(20) NOT NULL, -- hide, unhide, delete, warn, ban, dismiss, auto_hide
+    comment_id INTEGER,
+    target_user_id INTEGER,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (moderator_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE SET NULL,
+    FOREIGN KEY (target_user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_moderation_log_created_at ON moderation_log(created_at DESC);
+CREATE INDEX idx_moderation_log_target_user_id ON moderation_log(target_user_id);
+CREATE INDEX idx_moderation_log_moderator_id ON moderation_log(moderator_id);
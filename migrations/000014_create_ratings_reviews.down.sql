@@ -0,0 +1,25 @@
+-- migrations/000014_create_ratings_reviews.down.sql
+
+DROP INDEX IF EXISTS idx_review_votes_review_id;
+DROP TABLE IF EXISTS review_votes;
+
+DROP INDEX IF EXISTS idx_manga_reviews_user_id;
+DROP INDEX IF EXISTS idx_manga_reviews_created_at;
+DROP INDEX IF EXISTS idx_manga_reviews_helpful;
+DROP TABLE IF EXISTS manga_reviews;
+
+DROP TABLE IF EXISTS rating_stats;
+
+DROP INDEX IF EXISTS idx_manga_ratings_manga_id;
+DROP TABLE IF EXISTS manga_ratings;
+
+DROP INDEX IF EXISTS idx_manga_rating_count;
+DROP INDEX IF EXISTS idx_manga_rating_average;
+DROP INDEX IF EXISTS idx_manga_bayesian_score;
+
+ALTER TABLE manga DROP COLUMN IF EXISTS review_count;
+ALTER TABLE manga DROP COLUMN IF EXISTS rating_histogram;
+ALTER TABLE manga DROP COLUMN IF EXISTS bayesian_score;
+ALTER TABLE manga DROP COLUMN IF EXISTS rating_average;
+ALTER TABLE manga DROP COLUMN IF EXISTS rating_sum;
+ALTER TABLE manga DROP COLUMN IF EXISTS rating_count;
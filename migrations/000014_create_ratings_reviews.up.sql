@@ -0,0 +1,73 @@
+-- migrations/000014_create_ratings_reviews.up.sql
+
+-- Агрегаты оценок и рецензий манги; обновляются вместе с оценками, а не пересчитываются при чтении
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS rating_sum BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS rating_average NUMERIC(4, 2) NOT NULL DEFAULT 0;
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS bayesian_score NUMERIC(4, 2) NOT NULL DEFAULT 0;
+-- Количество оценок 1..10 по индексу оценки
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS rating_histogram INTEGER[] NOT NULL DEFAULT '{0,0,0,0,0,0,0,0,0,0}';
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_manga_bayesian_score ON manga(bayesian_score DESC, id DESC);
+CREATE INDEX idx_manga_rating_average ON manga(rating_average DESC, id DESC);
+CREATE INDEX idx_manga_rating_count ON manga(rating_count DESC, id DESC);
+
+-- Оценки пользователей
+CREATE TABLE IF NOT EXISTS manga_ratings (
+    user_id INTEGER NOT NULL,
+    manga_id INTEGER NOT NULL,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 10),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, manga_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_manga_ratings_manga_id ON manga_ratings(manga_id);
+
+-- Общие счетчики оценок по всему каталогу: среднее используется как априорное в байесовской оценке.
+-- Обновляются периодически по суммам из manga
+CREATE TABLE IF NOT EXISTS rating_stats (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    rating_count BIGINT NOT NULL DEFAULT 0,
+    rating_sum BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO rating_stats (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
+
+-- Рецензии: не больше одной на пользователя для каждой манги
+CREATE TABLE IF NOT EXISTS manga_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    manga_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL,
+    content_html TEXT NOT NULL,
+    spoiler BOOLEAN NOT NULL DEFAULT FALSE,
+    helpful_count INTEGER NOT NULL DEFAULT 0,
+    unhelpful_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (manga_id, user_id)
+);
+
+CREATE INDEX idx_manga_reviews_helpful ON manga_reviews(manga_id, helpful_count DESC, created_at DESC);
+CREATE INDEX idx_manga_reviews_created_at ON manga_reviews(manga_id, created_at DESC);
+CREATE INDEX idx_manga_reviews_user_id ON manga_reviews(user_id);
+
+-- Оценки полезности рецензий
+CREATE TABLE IF NOT EXISTS review_votes (
+    user_id INTEGER NOT NULL,
+    review_id INTEGER NOT NULL,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, review_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (review_id) REFERENCES manga_reviews(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_review_votes_review_id ON review_votes(review_id);
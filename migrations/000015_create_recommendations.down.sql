@@ -0,0 +1,5 @@
+-- migrations/000015_create_recommendations.down.sql
+
+DROP INDEX IF EXISTS idx_manga_genres_genre;
+DROP TABLE IF EXISTS manga_similarity_state;
+DROP TABLE IF EXISTS manga_similarity;
@@ -0,0 +1,26 @@
+-- migrations/000015_create_recommendations.up.sql
+
+-- Похожесть манги по совместному чтению ("кто читал X, читал и Y").
+-- Пересчитывается периодически целиком; для каждой манги хранятся только ближайшие соседи
+CREATE TABLE IF NOT EXISTS manga_similarity (
+    manga_id INTEGER NOT NULL,
+    similar_manga_id INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL, -- косинусная мера по множествам читателей со сглаживанием
+    co_readers INTEGER NOT NULL,
+    PRIMARY KEY (manga_id, similar_manga_id),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (similar_manga_id) REFERENCES manga(id) ON DELETE CASCADE
+);
+
+-- Состояние пересчета похожести: строка блокируется на время пересчета,
+-- чтобы несколько экземпляров приложения не пересчитывали таблицу одновременно
+CREATE TABLE IF NOT EXISTS manga_similarity_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    pair_count INTEGER NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP
+);
+
+INSERT INTO manga_similarity_state (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
+
+-- Поиск манги по жанрам при подборе рекомендаций по жанровым предпочтениям
+CREATE INDEX IF NOT EXISTS idx_manga_genres_genre ON manga_genres(genre_id);
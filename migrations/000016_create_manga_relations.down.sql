@@ -0,0 +1,5 @@
+-- migrations/000016_create_manga_relations.down.sql
+
+DROP INDEX IF EXISTS idx_manga_artist_lower;
+DROP INDEX IF EXISTS idx_manga_author_lower;
+DROP TABLE IF EXISTS manga_relations;
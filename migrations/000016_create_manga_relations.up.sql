@@ -0,0 +1,20 @@
+-- migrations/000016_create_manga_relations.up.sql
+
+-- Связи между мангой, заданные администраторами (продолжение, спин-офф, адаптация и т.п.).
+-- Каждая связь хранится в обе стороны с обратным типом: продолжение X — это X как предыстория для продолжения
+CREATE TABLE IF NOT EXISTS manga_relations (
+    manga_id INTEGER NOT NULL,
+    related_manga_id INTEGER NOT NULL,
+    relation_type VARCHAR(20) NOT NULL, -- sequel, prequel, spin_off, parent_story, adaptation, source, same_universe
+    created_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (manga_id, related_manga_id),
+    CHECK (manga_id <> related_manga_id),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Поиск похожей манги того же автора или художника
+CREATE INDEX IF NOT EXISTS idx_manga_author_lower ON manga(LOWER(author));
+CREATE INDEX IF NOT EXISTS idx_manga_artist_lower ON manga(LOWER(artist));
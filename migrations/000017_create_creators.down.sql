@@ -0,0 +1,11 @@
+-- migrations/000017_create_creators.down.sql
+
+CREATE INDEX IF NOT EXISTS idx_manga_author_lower ON manga(LOWER(author));
+CREATE INDEX IF NOT EXISTS idx_manga_artist_lower ON manga(LOWER(artist));
+
+DROP INDEX IF EXISTS idx_manga_creators_creator_id;
+DROP TABLE IF EXISTS manga_creators;
+DROP INDEX IF EXISTS idx_creator_aliases_name_key;
+DROP TABLE IF EXISTS creator_aliases;
+DROP TABLE IF EXISTS creators;
+DROP FUNCTION IF EXISTS creator_name_key(TEXT);
@@ -0,0 +1,87 @@
+-- migrations/000017_create_creators.up.sql
+
+-- Ключ сравнения имен: нижний регистр, без знаков препинания, слова по алфавиту.
+-- "Oda Eiichiro" и "Eiichiro Oda" дают один ключ и считаются одним человеком
+CREATE OR REPLACE FUNCTION creator_name_key(name TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(word, ' ' ORDER BY word), '')
+    FROM regexp_split_to_table(LOWER(regexp_replace(name, '[[:space:][:punct:]]+', ' ', 'g')), ' ') AS word
+    WHERE word <> ''
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Авторы и художники
+CREATE TABLE IF NOT EXISTS creators (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Варианты написания имени, включая основное; по ключу имени текстовые значения сопоставляются с авторами
+CREATE TABLE IF NOT EXISTS creator_aliases (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL,
+    alias VARCHAR(100) NOT NULL,
+    name_key VARCHAR(100) NOT NULL,
+    FOREIGN KEY (creator_id) REFERENCES creators(id) ON DELETE CASCADE,
+    UNIQUE (creator_id, alias)
+);
+
+CREATE INDEX IF NOT EXISTS idx_creator_aliases_name_key ON creator_aliases(name_key);
+
+-- Участие авторов в манге; position задает порядок имен в пределах роли
+CREATE TABLE IF NOT EXISTS manga_creators (
+    manga_id INTEGER NOT NULL,
+    creator_id INTEGER NOT NULL,
+    role VARCHAR(20) NOT NULL, -- author, artist
+    position SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (manga_id, creator_id, role),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    FOREIGN KEY (creator_id) REFERENCES creators(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_manga_creators_creator_id ON manga_creators(creator_id);
+
+-- Перенос текстовых author/artist: значения через запятую разбиваются на имена,
+-- имена с одинаковым ключом объединяются в одного автора с самым частым написанием в качестве основного
+CREATE TABLE creator_import AS
+SELECT manga_id, role, name, position, creator_name_key(name) AS name_key
+FROM (
+    SELECT m.id AS manga_id, 'author' AS role,
+           TRIM(regexp_replace(t.part, '\s+', ' ', 'g')) AS name, (t.ord - 1)::smallint AS position
+    FROM manga m
+    CROSS JOIN LATERAL regexp_split_to_table(COALESCE(m.author, ''), ',') WITH ORDINALITY AS t(part, ord)
+    UNION ALL
+    SELECT m.id, 'artist',
+           TRIM(regexp_replace(t.part, '\s+', ' ', 'g')), (t.ord - 1)::smallint
+    FROM manga m
+    CROSS JOIN LATERAL regexp_split_to_table(COALESCE(m.artist, ''), ',') WITH ORDINALITY AS t(part, ord)
+) parts;
+
+DELETE FROM creator_import WHERE name_key = '';
+
+INSERT INTO creators (name)
+SELECT DISTINCT ON (name_key) name
+FROM (
+    SELECT name_key, name, COUNT(*) AS usages
+    FROM creator_import
+    GROUP BY name_key, name
+) spellings
+ORDER BY name_key, usages DESC, name;
+
+INSERT INTO creator_aliases (creator_id, alias, name_key)
+SELECT DISTINCT c.id, i.name, i.name_key
+FROM creators c
+JOIN creator_import i ON i.name_key = creator_name_key(c.name);
+
+INSERT INTO manga_creators (manga_id, creator_id, role, position)
+SELECT DISTINCT ON (i.manga_id, c.id, i.role) i.manga_id, c.id, i.role, i.position
+FROM creator_import i
+JOIN creators c ON creator_name_key(c.name) = i.name_key
+ORDER BY i.manga_id, c.id, i.role, i.position;
+
+DROP TABLE creator_import;
+
+-- Похожая манга теперь ищется по общим авторам в manga_creators
+DROP INDEX IF EXISTS idx_manga_author_lower;
+DROP INDEX IF EXISTS idx_manga_artist_lower;
@@ -0,0 +1,6 @@
+-- migrations/000018_create_manga_translations.down.sql
+
+DROP TABLE IF EXISTS manga_descriptions;
+DROP INDEX IF EXISTS idx_manga_title_trgm;
+DROP INDEX IF EXISTS idx_manga_alt_titles_title_trgm;
+DROP TABLE IF EXISTS manga_alt_titles;
@@ -0,0 +1,28 @@
+-- migrations/000018_create_manga_translations.up.sql
+
+-- Триграммные индексы ускоряют поиск ILIKE '%...%' по названиям (pg_trgm — доверенное расширение с PostgreSQL 13)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Альтернативные названия манги: японское, ромадзи, английское, русское и т.д.
+-- Основное название остается в manga.title
+CREATE TABLE IF NOT EXISTS manga_alt_titles (
+    id SERIAL PRIMARY KEY,
+    manga_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    language VARCHAR(20) NOT NULL, -- код языка: ja, ja-ro (ромадзи), en, ru
+    position SMALLINT NOT NULL DEFAULT 0,
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+    UNIQUE (manga_id, language, title)
+);
+
+CREATE INDEX IF NOT EXISTS idx_manga_alt_titles_title_trgm ON manga_alt_titles USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_manga_title_trgm ON manga USING GIN (title gin_trgm_ops);
+
+-- Описания манги на разных языках; manga.description используется, если описания на языке запроса нет
+CREATE TABLE IF NOT EXISTS manga_descriptions (
+    manga_id INTEGER NOT NULL,
+    language VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (manga_id, language),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+);
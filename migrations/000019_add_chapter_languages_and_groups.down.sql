@@ -0,0 +1,13 @@
+-- migrations/000019_add_chapter_languages_and_groups.down.sql
+
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_languages;
+
+-- Восстановление прежнего ограничения не пройдет, если у главы есть несколько переводов
+DROP INDEX IF EXISTS idx_chapters_group;
+DROP INDEX IF EXISTS idx_chapters_release;
+ALTER TABLE chapters ADD CONSTRAINT chapters_manga_id_number_key UNIQUE (manga_id, number);
+
+ALTER TABLE chapters DROP COLUMN IF EXISTS group_id;
+ALTER TABLE chapters DROP COLUMN IF EXISTS language;
+
+DROP TABLE IF EXISTS scanlation_groups;
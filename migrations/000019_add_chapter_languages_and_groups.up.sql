@@ -0,0 +1,26 @@
+-- migrations/000019_add_chapter_languages_and_groups.up.sql
+
+-- Группы переводчиков (сканлейт-команды)
+CREATE TABLE IF NOT EXISTS scanlation_groups (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    website VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scanlation_groups_name ON scanlation_groups (lower(name));
+
+-- Язык перевода и группа главы. Существующие главы считаются русскими и без группы.
+-- Группа не удаляется, пока у нее есть главы: иначе два перевода одной главы совпали бы по уникальному ключу
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS language VARCHAR(20) NOT NULL DEFAULT 'ru';
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS group_id INTEGER REFERENCES scanlation_groups(id) ON DELETE RESTRICT;
+
+-- Одна глава может выйти в нескольких переводах: уникален номер в пределах языка и группы
+ALTER TABLE chapters DROP CONSTRAINT IF EXISTS chapters_manga_id_number_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chapters_release ON chapters (manga_id, number, language, COALESCE(group_id, 0));
+CREATE INDEX IF NOT EXISTS idx_chapters_group ON chapters (group_id, created_at DESC) WHERE group_id IS NOT NULL;
+
+-- Предпочитаемые языки глав пользователя в порядке приоритета
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_languages TEXT[] NOT NULL DEFAULT '{}';
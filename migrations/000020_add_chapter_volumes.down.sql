@@ -0,0 +1,5 @@
+-- migrations/000020_add_chapter_volumes.down.sql
+
+DROP TABLE IF EXISTS manga_volumes;
+DROP INDEX IF EXISTS idx_chapters_manga_volume;
+ALTER TABLE chapters DROP COLUMN IF EXISTS volume;
@@ -0,0 +1,17 @@
+-- migrations/000020_add_chapter_volumes.up.sql
+
+-- Номер тома главы; главы без тома (например, еще не вошедшие в танкобон) идут в конце списка
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS volume INTEGER CHECK (volume > 0);
+
+CREATE INDEX IF NOT EXISTS idx_chapters_manga_volume ON chapters (manga_id, volume, number);
+
+-- Название и обложка тома
+CREATE TABLE IF NOT EXISTS manga_volumes (
+    manga_id INTEGER NOT NULL,
+    number INTEGER NOT NULL CHECK (number > 0),
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    cover_image VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (manga_id, number),
+    FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- migrations/000021_add_chapter_publishing.down.sql
+
+DELETE FROM role_permissions WHERE permission = 'chapter:early_access';
+DELETE FROM permissions WHERE name = 'chapter:early_access';
+UPDATE users SET role = 'reader' WHERE role = 'supporter';
+DELETE FROM roles WHERE name = 'supporter';
+
+DROP INDEX IF EXISTS idx_chapters_scheduled;
+
+-- Неопубликованные главы удалить нельзя без потери данных, поэтому они становятся опубликованными
+ALTER TABLE chapters DROP COLUMN IF EXISTS early_access_until;
+ALTER TABLE chapters DROP COLUMN IF EXISTS published_at;
+ALTER TABLE chapters DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE chapters DROP COLUMN IF EXISTS status;
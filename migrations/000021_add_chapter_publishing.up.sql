@@ -0,0 +1,29 @@
+-- migrations/000021_add_chapter_publishing.up.sql
+
+-- Статус публикации главы: черновик, запланирована на publish_at, опубликована.
+-- До early_access_until опубликованную главу могут читать только пользователи с ранним доступом
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'scheduled', 'published'));
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS early_access_until TIMESTAMP;
+
+UPDATE chapters SET published_at = created_at WHERE published_at IS NULL AND status = 'published';
+
+-- Планировщик выбирает главы, время публикации которых наступило
+CREATE INDEX IF NOT EXISTS idx_chapters_scheduled ON chapters (publish_at) WHERE status = 'scheduled';
+
+-- Ранний доступ к главам
+INSERT INTO roles (name, description) VALUES
+    ('supporter', 'Читатель с ранним доступом к главам')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('chapter:early_access', 'Чтение глав в период раннего доступа')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('supporter', 'chapter:early_access'),
+    ('editor', 'chapter:early_access'),
+    ('admin', 'chapter:early_access')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,4 @@
+-- migrations/000022_make_page_numbers_deferrable.down.sql
+
+ALTER TABLE pages DROP CONSTRAINT IF EXISTS pages_chapter_id_number_key;
+ALTER TABLE pages ADD CONSTRAINT pages_chapter_id_number_key UNIQUE (chapter_id, number);
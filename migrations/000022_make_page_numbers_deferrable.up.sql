@@ -0,0 +1,7 @@
+-- migrations/000022_make_page_numbers_deferrable.up.sql
+
+-- Уникальность номеров страниц проверяется в конце транзакции, если она отложена через SET CONSTRAINTS:
+-- перенумерация и вставка страниц сдвигают номера, временно повторяя их
+ALTER TABLE pages DROP CONSTRAINT IF EXISTS pages_chapter_id_number_key;
+ALTER TABLE pages ADD CONSTRAINT pages_chapter_id_number_key
+    UNIQUE (chapter_id, number) DEFERRABLE INITIALLY IMMEDIATE;
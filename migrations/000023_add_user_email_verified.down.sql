@@ -0,0 +1,3 @@
+-- migrations/000023_add_user_email_verified.down.sql
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
@@ -0,0 +1,5 @@
+-- migrations/000023_add_user_email_verified.up.sql
+
+-- Признак подтвержденного email. Локальная регистрация email не подтверждает,
+-- поэтому вход через провайдера автоматически привязывается только к подтвержденным адресам
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+-- migrations/000024_use_timestamptz_for_chapter_publishing.down.sql
+
+ALTER TABLE chapters ALTER COLUMN early_access_until TYPE TIMESTAMP;
+ALTER TABLE chapters ALTER COLUMN published_at TYPE TIMESTAMP;
+ALTER TABLE chapters ALTER COLUMN publish_at TYPE TIMESTAMP;
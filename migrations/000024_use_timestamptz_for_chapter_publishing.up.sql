@@ -0,0 +1,8 @@
+-- migrations/000024_use_timestamptz_for_chapter_publishing.up.sql
+
+-- Время публикации приходит от клиента со смещением (RFC3339). В TIMESTAMP смещение терялось,
+-- и глава со временем +03:00 публиковалась на три часа позже. Существующие значения
+-- интерпретируются в часовом поясе сессии, в котором их сравнивал NOW()
+ALTER TABLE chapters ALTER COLUMN publish_at TYPE TIMESTAMPTZ;
+ALTER TABLE chapters ALTER COLUMN published_at TYPE TIMESTAMPTZ;
+ALTER TABLE chapters ALTER COLUMN early_access_until TYPE TIMESTAMPTZ;
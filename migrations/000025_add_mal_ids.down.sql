@@ -0,0 +1,6 @@
+-- migrations/000025_add_mal_ids.down.sql
+
+ALTER TABLE library_entries DROP COLUMN IF EXISTS mal_id;
+
+DROP INDEX IF EXISTS idx_manga_mal_id;
+ALTER TABLE manga DROP COLUMN IF EXISTS mal_id;
@@ -0,0 +1,8 @@
+-- migrations/000025_add_mal_ids.up.sql
+
+-- ID манги на MyAnimeList: по нему MyAnimeList сопоставляет записи при импорте списка.
+-- В каталоге его задают редакторы, в записи библиотеки он сохраняется из импортированного файла
+ALTER TABLE manga ADD COLUMN IF NOT EXISTS mal_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_manga_mal_id ON manga (mal_id) WHERE mal_id IS NOT NULL;
+
+ALTER TABLE library_entries ADD COLUMN IF NOT EXISTS mal_id BIGINT;
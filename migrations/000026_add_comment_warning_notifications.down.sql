@@ -0,0 +1,6 @@
+-- migrations/000026_add_comment_warning_notifications.down.sql
+
+DELETE FROM notifications WHERE type = 'comment_warning';
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS message;
+ALTER TABLE notifications DROP COLUMN IF EXISTS comment_id;
@@ -0,0 +1,6 @@
+-- migrations/000026_add_comment_warning_notifications.up.sql
+
+-- Предупреждение модератора приходит автору комментария уведомлением comment_warning
+-- с причиной в message. chapter_id у таких уведомлений не заполняется
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS message TEXT;